@@ -0,0 +1,144 @@
+// Package blockedservers fetches Mojang's list of blocked Minecraft servers,
+// i.e. servers which the game refuses to connect to because they have been
+// blocked for violating Mojang's EULA, and matches server addresses against it.
+//
+// Mojang publishes the list as SHA1 hashes of addresses, some of which contain
+// wildcards, so the list can only be matched against known addresses rather
+// than be read. For example:
+//	l, err := blockedservers.Load(context.TODO())
+//	if err != nil {
+//		log.Fatal("Failed to fetch blocked servers: " + err.Error())
+//	}
+//	if l.IsBlocked("play.example.com") {
+//		fmt.Println("play.example.com is blocked by Mojang")
+//	}
+// For more information, see http://wiki.vg/Mojang_API#Blocked_Servers.
+package blockedservers
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/PhilipBorgesen/minecraft/internal"
+)
+
+// The endpoint to fetch the list of blocked servers from.
+// For test purposes a cached response should be downloaded to testdata/cached/<SERVER PATH>
+const listURL = "https://sessionserver.mojang.com/blockedservers"
+
+var client = &http.Client{}
+
+// List is a list of blocked servers, as published by Mojang.
+type List struct {
+	hashes map[string]struct{} // Hex-encoded SHA1 hashes of blocked addresses.
+}
+
+// Load fetches the list of blocked servers from Mojang's servers. ctx must be
+// non-nil. If an error occurs, a nil List is returned. Load reports Mojang
+// server communication failures using *url.Error, including responses which
+// aren't lists of SHA1 hashes.
+func Load(ctx context.Context) (*List, error) {
+	internal.CheckContext(ctx, "blockedservers", "Load")
+
+	req, err := http.NewRequest("GET", listURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		// Read the body to EOF so the connection can be reused
+		io.Copy(ioutil.Discard, resp.Body)
+		return nil, &url.Error{
+			Op:  "Get",
+			URL: listURL,
+			Err: &internal.FailedRequestError{StatusCode: resp.StatusCode},
+		}
+	}
+
+	l := &List{hashes: make(map[string]struct{})}
+	s := bufio.NewScanner(resp.Body)
+	for s.Scan() {
+		h := strings.ToLower(strings.TrimSpace(s.Text()))
+		if h == "" {
+			continue
+		}
+		if bs, err := hex.DecodeString(h); err != nil || len(bs) != sha1.Size {
+			return nil, &url.Error{Op: "Parse", URL: listURL, Err: internal.ErrUnknownFormat}
+		}
+		l.hashes[h] = struct{}{}
+	}
+	if err := s.Err(); err != nil {
+		return nil, &url.Error{Op: "Parse", URL: listURL, Err: err}
+	}
+	return l, nil
+}
+
+// Len returns the number of hashes in l.
+func (l *List) Len() int {
+	return len(l.hashes)
+}
+
+// Hashes returns the hex-encoded SHA1 hashes of the blocked addresses in l,
+// sorted.
+func (l *List) Hashes() []string {
+	hs := make([]string, 0, len(l.hashes))
+	for h := range l.hashes {
+		hs = append(hs, h)
+	}
+	sort.Strings(hs)
+	return hs
+}
+
+// IsBlocked reports whether the game refuses to connect to the server at host,
+// which is a domain name or IPv4 address without port, e.g. "play.example.com".
+// host is matched case-insensitively.
+//
+// Like the game, IsBlocked tries host as is, and then with a wildcard
+// replacing an increasing number of its labels. For domain names the wildcard
+// is first prepended to host, so "*.example.com" also blocks "example.com"
+// itself, e.g. "*.play.example.com", "*.example.com" and "*.com" are tried for
+// "play.example.com". For IPv4 addresses, e.g. "192.168.5.*", "192.168.*" and
+// "192.*" are tried.
+func (l *List) IsBlocked(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if l.has(host) {
+		return true
+	}
+
+	labels := strings.Split(host, ".")
+	if ip := net.ParseIP(host); ip != nil && ip.To4() != nil && len(labels) == 4 {
+		for i := len(labels) - 1; i > 0; i-- {
+			if l.has(strings.Join(labels[:i], ".") + ".*") {
+				return true
+			}
+		}
+		return false
+	}
+	for i := 0; i < len(labels); i++ {
+		if l.has("*." + strings.Join(labels[i:], ".")) {
+			return true
+		}
+	}
+	return false
+}
+
+// has reports whether l contains the hash of addr.
+func (l *List) has(addr string) bool {
+	sum := sha1.Sum([]byte(addr))
+	_, ok := l.hashes[hex.EncodeToString(sum[:])]
+	return ok
+}
@@ -0,0 +1,145 @@
+package blockedservers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/PhilipBorgesen/minecraft/internal"
+)
+
+var testLoadInput = [...]struct {
+	transport http.RoundTripper
+	expHashes []string
+	expErr    error
+}{
+	{
+		transport: http.NewFileTransport(http.Dir("testdata/cached")),
+		expHashes: []string{
+			"4b50089e526d707964387c797a9db5392a2a7851", // 192.168.5.*
+			"8b3a77b3faa4a68cc5a3c215c48fc66682f4dbf2", // 192.*
+			"8c7122d652cb7be22d1986f1f30b07fd5108d9c0", // *.example.com
+			"bf3f8bda06cdb52f7e0f152ac27fd0bf1699d34b", // play.example.com
+		},
+	},
+	{
+		transport: http.NewFileTransport(http.Dir("testdata/malformed")),
+		expErr:    &url.Error{Op: "Parse", URL: listURL, Err: internal.ErrUnknownFormat},
+	},
+	{
+		transport: http.NewFileTransport(http.Dir("testdata/nonexisting")),
+		expErr:    &url.Error{Op: "Get", URL: listURL, Err: &internal.FailedRequestError{StatusCode: 404}},
+	},
+	{
+		transport: errorTransport{testError},
+		expErr:    &url.Error{Op: "Get", URL: listURL, Err: testError},
+	},
+}
+
+func TestLoad(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	for _, tc := range testLoadInput {
+		client.Transport = tc.transport
+
+		l, err := Load(context.Background())
+		var hashes []string
+		if l != nil {
+			hashes = l.Hashes()
+		}
+		if !reflect.DeepEqual(hashes, tc.expHashes) || !reflect.DeepEqual(err, tc.expErr) {
+			t.Errorf(
+				"Load(ctx)\n"+
+					" was: hashes %q, %v\n"+
+					"want: hashes %q, %v",
+				hashes, err,
+				tc.expHashes, tc.expErr,
+			)
+		}
+	}
+}
+
+func TestLoadContextUsed(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	ctx := context.WithValue(context.Background(), dummy, nil)
+	ct := CtxStoreTransport{}
+
+	client.Transport = &ct
+	Load(ctx)
+
+	if ct.Context != ctx {
+		t.Error("Load(ctx) didn't pass context to underlying http.Client")
+	}
+}
+
+func TestLoadNilContext(t *testing.T) {
+	const exp = "minecraft/blockedservers: nil Context passed to Load"
+	defer func() {
+		if r := recover(); r != exp {
+			t.Errorf("Load(nil) panicked with %#v; want %q", r, exp)
+		}
+	}()
+	Load(nil)
+}
+
+var testIsBlockedInput = [...]struct {
+	host string
+	exp  bool
+}{
+	{host: "play.example.com", exp: true},  // Exact match
+	{host: "PLAY.Example.com.", exp: true}, // Case-insensitive, trailing dot
+	{host: "mc.example.com", exp: true},    // *.example.com
+	{host: "a.b.example.com", exp: true},   // *.example.com
+	{host: "example.com", exp: true},       // *.example.com matches example.com itself
+	{host: "mc.example.org", exp: false},
+	{host: "192.168.5.10", exp: true},  // 192.168.5.*
+	{host: "192.10.20.30", exp: true},  // 192.*
+	{host: "193.168.5.10", exp: false}, // Not a prefix of any blocked address
+	{host: "10.0.0.192", exp: false},
+	{host: "", exp: false},
+}
+
+func TestList_IsBlocked(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	client.Transport = http.NewFileTransport(http.Dir("testdata/cached"))
+	l, err := Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load(ctx) failed: %s", err)
+	}
+	for _, tc := range testIsBlockedInput {
+		if res := l.IsBlocked(tc.host); res != tc.exp {
+			t.Errorf("List.IsBlocked(%q) was %t; want %t", tc.host, res, tc.exp)
+		}
+	}
+}
+
+/*** TEST UTILS ***/
+
+var testError = errors.New("test error")
+
+var dummy struct{}
+
+type errorTransport struct {
+	err error
+}
+
+func (t errorTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, t.err
+}
+
+type CtxStoreTransport struct {
+	Context context.Context
+}
+
+func (ct *CtxStoreTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ct.Context = req.Context()
+	return nil, errors.New("dummy error")
+}
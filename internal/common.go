@@ -1,18 +1,32 @@
 package internal
 
 import (
+	"bufio"
 	"bytes"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
 )
 
 var ErrUnknownFormat = errors.New("unknown JSON data format")
 
+// CheckContext panics with a descriptive message if ctx is nil. pkg and fn
+// name the package and exported function ctx was passed to, e.g. "profile" and
+// "Load", and are used in the panic message.
+func CheckContext(ctx context.Context, pkg, fn string) {
+	if ctx == nil {
+		panic("minecraft/" + pkg + ": nil Context passed to " + fn)
+	}
+}
+
 // FailedRequestError represents a non-200 response from the Mojang servers,
 // incl. potential JSON error types and messages.
 type FailedRequestError struct {
@@ -38,48 +52,178 @@ func (err *FailedRequestError) Error() string {
 
 // FetchJSON GETs JSON from an URL and parses it into a map hierarchy.
 // If a non-200 response is returned, the returned url.Error wraps a
-// FailedRequestError.
+// FailedRequestError. Compressed responses are decoded as described for
+// decodeBody.
 func FetchJSON(ctx context.Context, client *http.Client, endpoint string) (interface{}, error) {
+	j, _, err := FetchRawJSON(ctx, client, endpoint)
+	return j, err
+}
+
+// FetchRawJSON is like FetchJSON, but also returns the raw JSON response body,
+// decompressed. The raw body is only returned if no error occurs.
+func FetchRawJSON(ctx context.Context, client *http.Client, endpoint string) (interface{}, []byte, error) {
 	// Fetch JSON
-	req, _ := http.NewRequest("GET", endpoint, nil) // Error only occurs if endpoint is bad
-	req = req.WithContext(ctx)
+	req, _ := NewRequest(ctx, "GET", endpoint, nil) // Error only occurs if endpoint is bad
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	return readJSON(resp, "Get", endpoint)
+}
+
+// Validators are the validators of a response, i.e. its ETag and Last-Modified
+// headers, by which a conditional request asks whether the response is still
+// current.
+type Validators struct {
+	ETag         string
+	LastModified string
+}
+
+// FetchJSONIfModified is like FetchJSON, but makes the request conditional on
+// the JSON having been modified since the response which v are the validators
+// of. If the server responds 304 Not Modified, j is nil and modified is false.
+// Otherwise modified is true. nv are the validators of the JSON returned by the
+// server, or v updated by the 304 response. If v is zero, the request is
+// unconditional.
+func FetchJSONIfModified(ctx context.Context, client *http.Client, endpoint string, v Validators) (j interface{}, nv Validators, modified bool, err error) {
+	req, _ := NewRequest(ctx, "GET", endpoint, nil) // Error only occurs if endpoint is bad
+	if v.ETag != "" {
+		req.Header.Set("If-None-Match", v.ETag)
+	}
+	if v.LastModified != "" {
+		req.Header.Set("If-Modified-Since", v.LastModified)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, Validators{}, false, err
 	}
 	defer resp.Body.Close()
 
-	return parseResponse(resp.Body, resp.StatusCode, "Get", endpoint)
+	if resp.StatusCode == http.StatusNotModified && v != (Validators{}) {
+		// Read the body to EOF so the connection can be reused
+		io.Copy(ioutil.Discard, resp.Body)
+		if e := resp.Header.Get("ETag"); e != "" {
+			v.ETag = e
+		}
+		if lm := resp.Header.Get("Last-Modified"); lm != "" {
+			v.LastModified = lm
+		}
+		return nil, v, false, nil
+	}
+
+	j, _, err = readJSON(resp, "Get", endpoint)
+	if err != nil {
+		return nil, Validators{}, false, err
+	}
+	nv = Validators{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	return j, nv, true, nil
+}
+
+// readJSON reads and parses the JSON response body of resp, which is the
+// response to an op request to endpoint, as described for parseResponse. The
+// raw body, decompressed, is returned as well if no error occurs.
+func readJSON(resp *http.Response, op, endpoint string) (interface{}, []byte, error) {
+	body, err := decodeBody(resp)
+	if err != nil {
+		return nil, nil, &url.Error{Op: "Parse", URL: endpoint, Err: err}
+	}
+	raw, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, nil, &url.Error{Op: "Parse", URL: endpoint, Err: err}
+	}
+	j, err := parseResponse(bytes.NewReader(raw), resp.StatusCode, op, endpoint)
+	if err != nil {
+		return nil, nil, err
+	}
+	return j, raw, nil
 }
 
 // ExchangeJSON POSTs JSON to an URL and parses the response JSON into a map
 // hierarchy. If a non-200 response is returned, the returned url.Error wraps
-// a FailedRequestError.
+// a FailedRequestError. Compressed responses are decoded as described for
+// decodeBody.
 func ExchangeJSON(ctx context.Context, client *http.Client, endpoint string, data interface{}) (interface{}, error) {
-	buf := bytes.Buffer{}
-	err := json.NewEncoder(&buf).Encode(data)
+	req, err := NewRequest(ctx, "POST", endpoint, data)
 	if err != nil {
 		return nil, err
 	}
 
-	req, _ := http.NewRequest("POST", endpoint, &buf) // Error only occurs if endpoint is bad
-	req = req.WithContext(ctx)
-
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	return parseResponse(resp.Body, resp.StatusCode, "Post", endpoint)
+	body, err := decodeBody(resp)
+	if err != nil {
+		return nil, &url.Error{Op: "Parse", URL: endpoint, Err: err}
+	}
+	return parseResponse(body, resp.StatusCode, "Post", endpoint)
 }
 
-func parseResponse(r io.ReadCloser, statusCode int, op, endpoint string) (interface{}, error) {
+// NewRequest returns the request FetchJSON (for GET) or ExchangeJSON (for POST)
+// sends to endpoint. If data is non-nil, it is encoded as JSON and used as the
+// request body. An error is returned if data can't be encoded or endpoint
+// isn't a valid URL.
+func NewRequest(ctx context.Context, method, endpoint string, data interface{}) (*http.Request, error) {
+	var body io.Reader
+	if data != nil {
+		buf := &bytes.Buffer{}
+		if err := json.NewEncoder(buf).Encode(data); err != nil {
+			return nil, err
+		}
+		body = buf
+	}
+
+	req, err := http.NewRequest(method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept-Encoding", "gzip")
+	return req.WithContext(ctx), nil
+}
+
+// decodeBody returns a reader of the decompressed body of resp.
+//
+// Since FetchJSON and ExchangeJSON request gzip compression explicitly, the
+// transport of the http.Client won't decompress responses transparently, even
+// if it is an http.Transport. This way responses are decoded the same no matter
+// which transport is used. Bodies with a Content-Encoding of gzip or deflate
+// are decompressed; all other bodies are returned as-is. Empty bodies, e.g. of
+// 204 No Content responses, are returned as empty bodies even if they claim to
+// be compressed, so the status of such responses is reported.
+func decodeBody(resp *http.Response) (io.Reader, error) {
+	enc := strings.ToLower(resp.Header.Get("Content-Encoding"))
+	if enc != "gzip" && enc != "deflate" {
+		return resp.Body, nil
+	}
+	body := bufio.NewReader(resp.Body)
+	if _, err := body.Peek(1); err == io.EOF {
+		return body, nil // Empty body
+	}
+	if enc == "gzip" {
+		return gzip.NewReader(body)
+	}
+	return zlib.NewReader(body)
+}
+
+// parseResponse parses the JSON response body r. A non-200 response is
+// returned as a url.Error wrapping a FailedRequestError, and so is a 200
+// response consisting of a Mojang error object, i.e. a JSON object with an
+// "error" or "errorMessage" field, which Mojang occasionally responds with in
+// place of an error status.
+func parseResponse(r io.Reader, statusCode int, op, endpoint string) (interface{}, error) {
 	var j interface{}
 	parseErr := json.NewDecoder(r).Decode(&j)
 
-	if statusCode != 200 {
+	if statusCode != 200 || (parseErr == nil && isErrorObject(j)) {
 		err := &FailedRequestError{
 			StatusCode: statusCode,
 		}
@@ -107,9 +251,29 @@ func parseResponse(r io.ReadCloser, statusCode int, op, endpoint string) (interf
 	return j, nil
 }
 
+// isErrorObject reports whether j is a Mojang error object.
+func isErrorObject(j interface{}) bool {
+	m, ok := j.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	_, hasError := m["error"]
+	_, hasErrorMessage := m["errorMessage"]
+	return hasError || hasErrorMessage
+}
+
 func UnwrapFailedRequestError(uerr error) (err *FailedRequestError, ok bool) {
 	if e, match := uerr.(*url.Error); match {
 		err, ok = e.Err.(*FailedRequestError)
 	}
 	return
 }
+
+// HTTPStatus returns the status code carried by err and true if err is a
+// url.Error wrapping a FailedRequestError. Otherwise it returns 0 and false.
+func HTTPStatus(err error) (status int, ok bool) {
+	if e, ok := UnwrapFailedRequestError(err); ok {
+		return e.StatusCode, true
+	}
+	return 0, false
+}
@@ -1,6 +1,9 @@
 package internal
 
 import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
 	"encoding/json"
 	"errors"
@@ -106,6 +109,22 @@ func TestUnwrapErrFailedRequest(t *testing.T) {
 	}
 }
 
+func TestHTTPStatus(t *testing.T) {
+	for _, tc := range testUnwrapErrors {
+		expStatus := 0
+		if tc.expOk {
+			expStatus = tc.expErr.StatusCode
+		}
+		status, ok := HTTPStatus(tc.err)
+		if status != expStatus || ok != tc.expOk {
+			t.Errorf(
+				"HTTPStatus(%#v) was %d, %t; want %d, %t",
+				tc.err, status, ok, expStatus, tc.expOk,
+			)
+		}
+	}
+}
+
 var testParseResponseInput = [...]struct {
 	response   string
 	statusCode int
@@ -216,6 +235,37 @@ var testParseResponseInput = [...]struct {
 			Err: io.EOF,
 		},
 	},
+	{
+		response:   "{\"error\": \"TooManyRequestsException\", \"errorMessage\": \"The client has sent too many requests within a certain amount of time\"}",
+		statusCode: 200,
+		op:         "Dummy",
+		endpoint:   "dummyURL",
+		expRes:     nil,
+		expErr: &url.Error{
+			Op:  "Dummy",
+			URL: "dummyURL",
+			Err: &FailedRequestError{
+				StatusCode:   200,
+				ErrorCode:    "TooManyRequestsException",
+				ErrorMessage: "The client has sent too many requests within a certain amount of time",
+			},
+		},
+	},
+	{
+		response:   "{\"errorMessage\": \"Not a valid UUID\"}",
+		statusCode: 200,
+		op:         "Dummy",
+		endpoint:   "dummyURL",
+		expRes:     nil,
+		expErr: &url.Error{
+			Op:  "Dummy",
+			URL: "dummyURL",
+			Err: &FailedRequestError{
+				StatusCode:   200,
+				ErrorMessage: "Not a valid UUID",
+			},
+		},
+	},
 }
 
 func TestParseResponse(t *testing.T) {
@@ -269,6 +319,71 @@ var testFetchJSONInput = [...]struct {
 		expRes:    make(map[string]interface{}),
 		expErr:    nil,
 	},
+	{
+		transport: encodingTransport{encoding: "gzip", body: gzipped(`{"a":1}`)},
+		endpoint:  "dummyURL",
+		expRes:    map[string]interface{}{"a": 1.0},
+		expErr:    nil,
+	},
+	{
+		transport: encodingTransport{encoding: "deflate", body: deflated(`{"a":1}`)},
+		endpoint:  "dummyURL",
+		expRes:    map[string]interface{}{"a": 1.0},
+		expErr:    nil,
+	},
+	{
+		transport: encodingTransport{encoding: "identity", body: []byte(`{"a":1}`)},
+		endpoint:  "dummyURL",
+		expRes:    map[string]interface{}{"a": 1.0},
+		expErr:    nil,
+	},
+	{
+		transport: encodingTransport{status: 204, encoding: "gzip", body: nil},
+		endpoint:  "dummyURL",
+		expRes:    nil,
+		expErr: &url.Error{
+			Op:  "Get",
+			URL: "dummyURL",
+			Err: &FailedRequestError{
+				StatusCode: 204,
+			},
+		},
+	},
+	{
+		transport: encodingTransport{status: 404, encoding: "deflate", body: nil},
+		endpoint:  "dummyURL",
+		expRes:    nil,
+		expErr: &url.Error{
+			Op:  "Get",
+			URL: "dummyURL",
+			Err: &FailedRequestError{
+				StatusCode: 404,
+			},
+		},
+	},
+	{
+		transport: encodingTransport{encoding: "gzip", body: []byte(`{"a":1} is not gzipped`)},
+		endpoint:  "dummyURL",
+		expRes:    nil,
+		expErr: &url.Error{
+			Op:  "Parse",
+			URL: "dummyURL",
+			Err: gzip.ErrHeader,
+		},
+	},
+}
+
+func TestCheckContext(t *testing.T) {
+	CheckContext(context.Background(), "pkg", "Fn") // Must not panic
+
+	const exp = "minecraft/pkg: nil Context passed to Fn"
+	var ctx context.Context
+	defer func() {
+		if r := recover(); r != exp {
+			t.Errorf("CheckContext(nil, \"pkg\", \"Fn\") panicked with %#v; want %q", r, exp)
+		}
+	}()
+	CheckContext(ctx, "pkg", "Fn")
 }
 
 func TestFetchJSON(t *testing.T) {
@@ -290,6 +405,46 @@ func TestFetchJSON(t *testing.T) {
 	}
 }
 
+func TestFetchRawJSON(t *testing.T) {
+	client := &http.Client{Transport: encodingTransport{encoding: "gzip", body: gzipped(`{"a":1}`)}}
+	res, raw, err := FetchRawJSON(context.Background(), client, "dummyURL")
+	if exp := map[string]interface{}{"a": 1.0}; !reflect.DeepEqual(res, exp) || string(raw) != `{"a":1}` || err != nil {
+		t.Errorf("FetchRawJSON(ctx, client, endpoint) of gzipped response was %#v, %q, %s; want %#v, %q, <nil>", res, raw, p(err), exp, `{"a":1}`)
+	}
+
+	client = &http.Client{Transport: encodingTransport{encoding: "identity", body: []byte(`{"a":`)}}
+	if _, raw, err := FetchRawJSON(context.Background(), client, "dummyURL"); raw != nil || err == nil {
+		t.Errorf("FetchRawJSON(ctx, client, endpoint) of malformed response was %q, %s; want <nil>, error", raw, p(err))
+	}
+}
+
+func TestFetchJSONIfModified(t *testing.T) {
+	client := &http.Client{Transport: etagTransport{etag: `"v1"`, body: `{"a":1}`}}
+
+	j, v, modified, err := FetchJSONIfModified(context.Background(), client, "dummyURL", Validators{})
+	if exp := map[string]interface{}{"a": 1.0}; !reflect.DeepEqual(j, exp) || !modified || err != nil {
+		t.Errorf("FetchJSONIfModified(ctx, client, endpoint, Validators{}) was %#v, %t, %s; want %#v, true, <nil>", j, modified, p(err), exp)
+	}
+	if exp := (Validators{ETag: `"v1"`, LastModified: etagLastModified}); v != exp {
+		t.Errorf("FetchJSONIfModified(ctx, client, endpoint, Validators{}) returned validators %#v; want %#v", v, exp)
+	}
+
+	if j, nv, modified, err := FetchJSONIfModified(context.Background(), client, "dummyURL", v); j != nil || nv != v || modified || err != nil {
+		t.Errorf("FetchJSONIfModified(ctx, client, endpoint, %#v) of unmodified JSON was %#v, %#v, %t, %s; want <nil>, %#v, false, <nil>", v, j, nv, modified, p(err), v)
+	}
+
+	v.ETag = `"v0"`
+	if _, _, modified, err := FetchJSONIfModified(context.Background(), client, "dummyURL", v); !modified || err != nil {
+		t.Errorf("FetchJSONIfModified(ctx, client, endpoint, %#v) of modified JSON was %t, %s; want true, <nil>", v, modified, p(err))
+	}
+
+	// A 304 response to an unconditional request is a failure
+	client = &http.Client{Transport: etagTransport{etag: "*"}}
+	if _, _, _, err := FetchJSONIfModified(context.Background(), client, "dummyURL", Validators{}); !reflect.DeepEqual(err, &url.Error{Op: "Get", URL: "dummyURL", Err: &FailedRequestError{StatusCode: 304}}) {
+		t.Errorf("FetchJSONIfModified(ctx, client, endpoint, Validators{}) of 304 response returned error %s; want 304 FailedRequestError", p(err))
+	}
+}
+
 func TestFetchJSONContextUsed(t *testing.T) {
 	ctx := context.WithValue(context.Background(), dummy, nil)
 	ct := CtxStoreTransport{}
@@ -382,6 +537,41 @@ func TestExchangeJSONContextUsed(t *testing.T) {
 	}
 }
 
+func TestExchangeJSONCompressed(t *testing.T) {
+	client := &http.Client{Transport: encodingTransport{encoding: "gzip", body: gzipped(`[]`)}}
+	res, err := ExchangeJSON(context.Background(), client, "dummyURL", nil)
+	if exp := []interface{}{}; !reflect.DeepEqual(res, exp) || err != nil {
+		t.Errorf("ExchangeJSON(ctx, client, endpoint, nil) of gzipped response was %#v, %s; want %#v, <nil>", res, p(err), exp)
+	}
+}
+
+func TestNewRequest(t *testing.T) {
+	ctx := context.WithValue(context.Background(), dummy, nil)
+
+	req, err := NewRequest(ctx, "GET", "https://example.com/a", nil)
+	if err != nil || req.Method != "GET" || req.URL.String() != "https://example.com/a" || req.Body != nil || req.Context() != ctx {
+		t.Errorf("NewRequest(ctx, \"GET\", \"https://example.com/a\", nil) was %#v, %s", req, p(err))
+	}
+
+	req, err = NewRequest(ctx, "POST", "https://example.com/b", []string{"a"})
+	if err != nil {
+		t.Fatalf("NewRequest(ctx, \"POST\", \"https://example.com/b\", []string{\"a\"}) failed: %s", err)
+	}
+	if body, _ := ioutil.ReadAll(req.Body); string(body) != "[\"a\"]\n" {
+		t.Errorf("NewRequest(ctx, \"POST\", \"https://example.com/b\", []string{\"a\"}) has body %q; want %q", body, "[\"a\"]\n")
+	}
+	if enc := req.Header.Get("Accept-Encoding"); enc != "gzip" {
+		t.Errorf("NewRequest(ctx, \"POST\", \"https://example.com/b\", []string{\"a\"}) has Accept-Encoding %q; want \"gzip\"", enc)
+	}
+
+	if _, err := NewRequest(ctx, "GET", "%zz", nil); err == nil {
+		t.Error("NewRequest(ctx, \"GET\", \"%zz\", nil) didn't fail on malformed endpoint")
+	}
+	if _, err := NewRequest(ctx, "POST", "https://example.com/b", func() {}); err == nil {
+		t.Error("NewRequest(ctx, \"POST\", \"https://example.com/b\", func() {}) didn't fail on unencodable data")
+	}
+}
+
 /*************
 * TEST UTILS *
 *************/
@@ -396,6 +586,71 @@ func (et errorTransport) RoundTrip(_ *http.Request) (*http.Response, error) {
 	return nil, et.err
 }
 
+// encodingTransport responds with status and body, declaring the body to be
+// compressed using encoding. If status is 0, it responds 200 OK. It fails
+// requests which don't accept gzip compression.
+type encodingTransport struct {
+	status   int
+	encoding string
+	body     []byte
+}
+
+func (et encodingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Accept-Encoding") != "gzip" {
+		return nil, errors.New("request didn't accept gzip compression")
+	}
+	status := et.status
+	if status == 0 {
+		status = 200
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Encoding": {et.encoding}},
+		Body:       ioutil.NopCloser(bytes.NewReader(et.body)),
+		Request:    req,
+	}, nil
+}
+
+func gzipped(s string) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write([]byte(s))
+	w.Close()
+	return buf.Bytes()
+}
+
+func deflated(s string) []byte {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write([]byte(s))
+	w.Close()
+	return buf.Bytes()
+}
+
+const etagLastModified = "Fri, 26 May 2017 12:00:00 GMT"
+
+// etagTransport responds with body, tagged etag. It responds 304 Not Modified
+// to requests with an If-None-Match header of etag, or if etag is "*", to all
+// requests.
+type etagTransport struct {
+	etag string
+	body string
+}
+
+func (et etagTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp := &http.Response{
+		StatusCode: 200,
+		Header:     http.Header{"Etag": {et.etag}, "Last-Modified": {etagLastModified}},
+		Body:       ioutil.NopCloser(strings.NewReader(et.body)),
+		Request:    req,
+	}
+	if et.etag == "*" || req.Header.Get("If-None-Match") == et.etag {
+		resp.StatusCode = 304
+		resp.Body = ioutil.NopCloser(strings.NewReader(""))
+	}
+	return resp, nil
+}
+
 type CtxStoreTransport struct {
 	Context context.Context
 }
@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/json"
+	"net/url"
 	"time"
+
+	"github.com/PhilipBorgesen/minecraft/uuid"
 )
 
-var emptyHist = make([]PastName, 0, 0)
+var emptyHist = make(NameHistory, 0, 0)
 
 // fillProfile fills out p with basic profile information from m.
 // m MUST contain string values for the keys "id" and "name".
@@ -39,16 +42,17 @@ func fillProfile(p *Profile, m map[string]interface{}) bool {
 }
 
 // buildHistory creates a username history (previous username first, original
-// username last) and returns it along with the current username.
+// username last) and returns it along with the current username. The original
+// username, if part of the history, is marked as such.
 // a is an array of maps containing "name" and (possibly) "changedToAt" keys.
 // The "name" values MUST be string and the "changedToAt" values MUST be integer.
 // A "changedToAt" field is the "until" field of the previous PastName struct.
-func buildHistory(arr []interface{}) (name string, hist []PastName) {
+func buildHistory(arr []interface{}) (name string, hist NameHistory) {
 	if len(arr) == 0 {
 		return "", nil
 	}
 
-	hist = make([]PastName, len(arr)-1)
+	hist = make(NameHistory, len(arr)-1)
 
 	h := len(hist) - 1
 	for i, v := range arr {
@@ -63,6 +67,7 @@ func buildHistory(arr []interface{}) (name string, hist []PastName) {
 			break
 		} else {
 			hist[h].Name = m["name"].(string)
+			hist[h].Original = i == 0
 			h--
 		}
 	}
@@ -76,18 +81,25 @@ func msToTime(ms int64) time.Time {
 	return time.Unix(s, ns)
 }
 
-// buildProperties returns a property set based on a JSON array of properties.
-// props MUST consist of map[string]interface{} maps, each map containing
-// string values for the keys "name" and "value".
-func buildProperties(props []interface{}) (ps *Properties, err error) {
+// buildProperties returns a property set based on a JSON array of properties
+// of the profile identified by id. props MUST consist of map[string]interface{}
+// maps, each map containing string values for the keys "name" and "value". If
+// available, "signature" MUST map to a string value.
+func buildProperties(props []interface{}, id string) (ps *Properties, err error) {
 	ps = &Properties{}
 	for _, p := range props {
 		prop := p.(map[string]interface{})
 		name := prop["name"].(string)
 		value := prop["value"].(string) // base64 encoded
 
+		raw := RawProperty{Name: name, Value: value}
+		if sig, ok := prop["signature"]; ok {
+			raw.Signature = sig.(string)
+		}
+		ps.raw = append(ps.raw, raw)
+
 		if parser, ok := propertyPopulators[name]; ok {
-			err = parser(value, ps)
+			err = parser(value, id, ps)
 			if err != nil {
 				return nil, err
 			}
@@ -97,15 +109,15 @@ func buildProperties(props []interface{}) (ps *Properties, err error) {
 }
 
 // propertyPopulators is a map of property name/value parser pairs.
-// Each parser takes the base64 encoded value, decodes it, and populates p with
-// the parsed data.
-var propertyPopulators = map[string]func(base64 string, p *Properties) error{
+// Each parser takes the base64 encoded value and the ID of the profile having
+// the property, decodes the value, and populates p with the parsed data.
+var propertyPopulators = map[string]func(base64, id string, p *Properties) error{
 	"textures": populateTextures,
 }
 
-// populateTextures parses the base64 encoded "textures" property enc and adds
-// its information to the Properties struct.
-func populateTextures(enc string, props *Properties) error {
+// populateTextures parses the base64 encoded "textures" property enc of the
+// profile identified by id and adds its information to the Properties struct.
+func populateTextures(enc, id string, props *Properties) error {
 	bs, err := base64.StdEncoding.DecodeString(enc)
 	if err != nil {
 		return err
@@ -119,10 +131,18 @@ func populateTextures(enc string, props *Properties) error {
 
 	ts := j["textures"].(map[string]interface{})
 
+	if t, set := j["timestamp"]; set {
+		props.Timestamp = msToTime(int64(t.(float64)))
+	}
+
 	// Set skin URL and skin Model if present
 	if s, set := ts["SKIN"]; set {
 		skin := s.(map[string]interface{})
-		props.SkinURL = skin["url"].(string)
+		u := skin["url"].(string)
+		if !isTextureURL(u) {
+			return ErrInvalidTextureURL
+		}
+		props.SkinURL = u
 
 		props.Model = Steve // Steve unless explicitly overridden
 		if s, set := skin["metadata"]; set {
@@ -131,20 +151,36 @@ func populateTextures(enc string, props *Properties) error {
 				props.Model = Alex
 			}
 		}
-	} else {
+	} else if pid, set := j["profileId"]; set {
 		// Default skin and model depends on player ID
-		props.Model = defaultModel(j["profileId"].(string))
+		props.Model = defaultModel(pid.(string))
+	} else if u, err := uuid.ToUndashed(id); err == nil {
+		// Some non-Mojang backends omit profileId
+		props.Model = defaultModel(u)
+	} else {
+		props.Model = Steve
 	}
 
 	// Set cape URL
 	if c, ok := ts["CAPE"]; ok {
 		cape := c.(map[string]interface{})
-		props.CapeURL = cape["url"].(string)
+		u := cape["url"].(string)
+		if !isTextureURL(u) {
+			return ErrInvalidTextureURL
+		}
+		props.CapeURL = u
 	}
 
 	return nil
 }
 
+// isTextureURL reports whether s is an absolute URL from where a texture may
+// be downloaded.
+func isTextureURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.IsAbs() && u.Host != ""
+}
+
 // defaultModel implementation is inspired by https://git.io/vSF4a.
 // Credit goes to Minecrell for compacting Java's 'uuid.hashCode() & 1' into the below.
 //
@@ -168,6 +204,9 @@ func populateTextures(enc string, props *Properties) error {
 // OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
 // SOFTWARE.
 func defaultModel(uuid string) Model {
+	if len(uuid) != 32 {
+		panic("minecraft/profile: player uuid '" + uuid + "' is not 32 hexadecimal digits")
+	}
 	if (isEven(uuid[7]) != isEven(uuid[16+7])) != (isEven(uuid[15]) != isEven(uuid[16+15])) {
 		return Alex
 	} else {
@@ -179,7 +218,7 @@ func isEven(c uint8) bool {
 	switch {
 	case c >= '0' && c <= '9':
 		return (c & 1) == 0
-	case c >= 'a' && c <= 'f':
+	case c >= 'a' && c <= 'f', c >= 'A' && c <= 'F':
 		return (c & 1) == 1
 	default:
 		panic("minecraft/profile: invalid digit '" + string(c) + "' in player uuid")
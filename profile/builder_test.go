@@ -116,7 +116,7 @@ func TestFillProfile(t *testing.T) {
 var testBuildHistoryInput = [...]struct {
 	arr     []interface{}
 	expName string
-	expHist []PastName
+	expHist NameHistory
 }{
 	{
 		arr:     nil,
@@ -150,8 +150,9 @@ var testBuildHistoryInput = [...]struct {
 		expName: "A",
 		expHist: []PastName{
 			{
-				Name:  "B",
-				Until: msToTime(1423047705000),
+				Name:     "B",
+				Until:    msToTime(1423047705000),
+				Original: true,
 			},
 		},
 	},
@@ -176,8 +177,9 @@ var testBuildHistoryInput = [...]struct {
 				Until: msToTime(1423047705000),
 			},
 			{
-				Name:  "C",
-				Until: msToTime(1000047705000),
+				Name:     "C",
+				Until:    msToTime(1000047705000),
+				Original: true,
 			},
 		},
 	},
@@ -223,6 +225,12 @@ func TestIsEven(t *testing.T) {
 			t.Errorf("isEven(%q) was %t; want %t", hex, even, expEven)
 		}
 	}
+	for dec, hex := range [...]uint8{'A', 'B', 'C', 'D', 'E', 'F'} {
+		expEven := dec%2 == 0
+		if even := isEven(hex); even != expEven {
+			t.Errorf("isEven(%q) was %t; want %t", hex, even, expEven)
+		}
+	}
 }
 
 var testDefaultModelInput = [...]struct {
@@ -237,6 +245,10 @@ var testDefaultModelInput = [...]struct {
 		uuid:     "3fe136c0cd434f7783fc94b9b86eed6d", // Feathertail
 		expModel: Alex,
 	},
+	{
+		uuid:     "3FE136C0CD434F7783FC94B9B86EED6D", // Feathertail, upper case
+		expModel: Alex,
+	},
 }
 
 func TestDefaultModel(t *testing.T) {
@@ -249,6 +261,7 @@ func TestDefaultModel(t *testing.T) {
 
 var testPopulateTexturesInput = [...]struct {
 	enc           string
+	id            string
 	expProperties *Properties
 	expErr        error
 }{
@@ -265,33 +278,76 @@ var testPopulateTexturesInput = [...]struct {
 	{
 		enc: "eyJ0aW1lc3RhbXAiOjE0OTM4NzUyMDcyMDYsInByb2ZpbGVJZCI6ImQ5MGI2OGJjODE3MjQzMjlhMDQ3ZjExODZkY2Q0MzM2IiwicHJvZmlsZU5hbWUiOiJha3Jvbm1hbjEiLCJ0ZXh0dXJlcyI6eyJTS0lOIjp7InVybCI6Imh0dHA6Ly90ZXh0dXJlcy5taW5lY3JhZnQubmV0L3RleHR1cmUvMzE3YTQxYzdhMzE1ODIxZTM2ZWU4YzdjOGMzOTQ3MTc0ZTQxYjU1MmViNDE2OGI3MTI3YzJkNWI4MmZhY2UwIn0sIkNBUEUiOnsidXJsIjoiaHR0cDovL3RleHR1cmVzLm1pbmVjcmFmdC5uZXQvdGV4dHVyZS9lYzgwYTIyNWIxNDVjODEyYTZlZjFjYTI5YWYwZjNlYmYwMjE2Mzg3NGQxYTY2ZTUzYmFjOTk5NjUyMjVlMCJ9fX0=",
 		expProperties: &Properties{
-			SkinURL: "http://textures.minecraft.net/texture/317a41c7a315821e36ee8c7c8c3947174e41b552eb4168b7127c2d5b82face0",
-			CapeURL: "http://textures.minecraft.net/texture/ec80a225b145c812a6ef1ca29af0f3ebf02163874d1a66e53bac99965225e0",
-			Model:   Steve,
+			SkinURL:   "http://textures.minecraft.net/texture/317a41c7a315821e36ee8c7c8c3947174e41b552eb4168b7127c2d5b82face0",
+			CapeURL:   "http://textures.minecraft.net/texture/ec80a225b145c812a6ef1ca29af0f3ebf02163874d1a66e53bac99965225e0",
+			Model:     Steve,
+			Timestamp: msToTime(1493875207206),
 		},
 	},
 	{
 		enc: "eyJ0aW1lc3RhbXAiOjE0OTM4NzUwMTAxODEsInByb2ZpbGVJZCI6ImNhYmVmYzkxYjVkZjRjODc4ODZhNmM2MDRkYTJlNDZmIiwicHJvZmlsZU5hbWUiOiJBeGVMYXciLCJ0ZXh0dXJlcyI6eyJTS0lOIjp7InVybCI6Imh0dHA6Ly90ZXh0dXJlcy5taW5lY3JhZnQubmV0L3RleHR1cmUvZDcyZDliMDBmM2Y2NDk0NjA3ZDIwZTU1N2U3ZjFiMjc2ZTczODZiYmZlNjk2NDliZTg3YmVjOGM0NDhkIn19fQ==",
 		expProperties: &Properties{
-			SkinURL: "http://textures.minecraft.net/texture/d72d9b00f3f6494607d20e557e7f1b276e7386bbfe69649be87bec8c448d",
-			CapeURL: "",
-			Model:   Steve,
+			SkinURL:   "http://textures.minecraft.net/texture/d72d9b00f3f6494607d20e557e7f1b276e7386bbfe69649be87bec8c448d",
+			CapeURL:   "",
+			Model:     Steve,
+			Timestamp: msToTime(1493875010181),
 		},
 	},
 	{
 		enc: "eyJ0aW1lc3RhbXAiOjE0OTM4NzcwNzE4NzAsInByb2ZpbGVJZCI6IjM2ZGNjN2E4M2NhMDQzNzI4NjU3ODI4MTg1ODZjYjJjIiwicHJvZmlsZU5hbWUiOiJTYWt1cmFCZWxsIiwidGV4dHVyZXMiOnsiU0tJTiI6eyJtZXRhZGF0YSI6eyJtb2RlbCI6InNsaW0ifSwidXJsIjoiaHR0cDovL3RleHR1cmVzLm1pbmVjcmFmdC5uZXQvdGV4dHVyZS9iYzJlMTc1MGMwNGMxNWU1YjdiMWYyYmFmZmEzNzEyMTM0ZmFmNzc0NGM0MTcyMzUxN2I1OTYwOGU0Yzk1NjgifX19",
 		expProperties: &Properties{
-			SkinURL: "http://textures.minecraft.net/texture/bc2e1750c04c15e5b7b1f2baffa3712134faf7744c41723517b59608e4c9568",
-			CapeURL: "",
-			Model:   Alex,
+			SkinURL:   "http://textures.minecraft.net/texture/bc2e1750c04c15e5b7b1f2baffa3712134faf7744c41723517b59608e4c9568",
+			CapeURL:   "",
+			Model:     Alex,
+			Timestamp: msToTime(1493877071870),
 		},
 	},
 	{
 		enc: "eyJ0aW1lc3RhbXAiOjE0OTM4Nzc4NTc0NTYsInByb2ZpbGVJZCI6ImVjNTYxNTM4ZjNmZDQ2MWRhZmY1MDg2YjIyMTU0YmNlIiwicHJvZmlsZU5hbWUiOiJBbGV4IiwidGV4dHVyZXMiOnt9fQ==",
 		expProperties: &Properties{
-			SkinURL: "",
-			CapeURL: "",
-			Model:   Steve,
+			SkinURL:   "",
+			CapeURL:   "",
+			Model:     Steve,
+			Timestamp: msToTime(1493877857456),
+		},
+	},
+	{ // Missing profileId; model depends on ID of loaded profile
+		enc: "eyJ0aW1lc3RhbXAiOjE0OTM4Nzc4NTc0NTYsInByb2ZpbGVOYW1lIjoiQWxleCIsInRleHR1cmVzIjp7fX0=",
+		id:  "3fe136c0cd434f7783fc94b9b86eed6d",
+		expProperties: &Properties{
+			Model:     Alex,
+			Timestamp: msToTime(1493877857456),
+		},
+	},
+	{ // Missing profileId; hyphenated ID of loaded profile
+		enc: "eyJ0aW1lc3RhbXAiOjE0OTM4Nzc4NTc0NTYsInByb2ZpbGVOYW1lIjoiQWxleCIsInRleHR1cmVzIjp7fX0=",
+		id:  "3fe136c0-cd43-4f77-83fc-94b9b86eed6d",
+		expProperties: &Properties{
+			Model:     Alex,
+			Timestamp: msToTime(1493877857456),
+		},
+	},
+	{ // Relative skin URL
+		enc: "eyJ0aW1lc3RhbXAiOjE0OTM4NzUyMDcyMDYsInByb2ZpbGVJZCI6IjA4N2NjMTUzYzM0MzRmZjdhYzQ5N2RlMTU2OWFmZmExIiwicHJvZmlsZU5hbWUiOiJOZXJnYWxpYyIsInRleHR1cmVzIjp7IlNLSU4iOnsidXJsIjoidGV4dHVyZXMubWluZWNyYWZ0Lm5ldC90ZXh0dXJlLzViNDAifX19",
+		expProperties: &Properties{
+			Timestamp: msToTime(1493875207206),
+		},
+		expErr: ErrInvalidTextureURL,
+	},
+	{ // Unparsable cape URL
+		enc: "eyJ0aW1lc3RhbXAiOjE0OTM4NzUyMDcyMDYsInByb2ZpbGVJZCI6IjA4N2NjMTUzYzM0MzRmZjdhYzQ5N2RlMTU2OWFmZmExIiwicHJvZmlsZU5hbWUiOiJOZXJnYWxpYyIsInRleHR1cmVzIjp7IlNLSU4iOnsidXJsIjoiaHR0cDovL3RleHR1cmVzLm1pbmVjcmFmdC5uZXQvdGV4dHVyZS81YjQwIn0sIkNBUEUiOnsidXJsIjoiaHR0cDovLyV6eiJ9fX0=",
+		expProperties: &Properties{
+			SkinURL:   "http://textures.minecraft.net/texture/5b40",
+			Model:     Steve,
+			Timestamp: msToTime(1493875207206),
+		},
+		expErr: ErrInvalidTextureURL,
+	},
+	{ // Missing profileId and ID unknown
+		enc: "eyJ0aW1lc3RhbXAiOjE0OTM4Nzc4NTc0NTYsInByb2ZpbGVOYW1lIjoiQWxleCIsInRleHR1cmVzIjp7fX0=",
+		expProperties: &Properties{
+			Model:     Steve,
+			Timestamp: msToTime(1493877857456),
 		},
 	},
 }
@@ -299,13 +355,13 @@ var testPopulateTexturesInput = [...]struct {
 func TestPopulateTextures(t *testing.T) {
 	for _, tc := range testPopulateTexturesInput {
 		var p Properties
-		err := populateTextures(tc.enc, &p)
+		err := populateTextures(tc.enc, tc.id, &p)
 		if !reflect.DeepEqual(&p, tc.expProperties) || err != tc.expErr {
 			t.Errorf(
-				"populateTextures(%q, Properties) produced result:\n"+
+				"populateTextures(%q, %q, Properties) produced result:\n"+
 					"      %#v, %s\n"+
 					"want: %#v, %s",
-				tc.enc,
+				tc.enc, tc.id,
 				&p, err,
 				tc.expProperties, tc.expErr,
 			)
@@ -325,11 +381,16 @@ var testBuildPropertiesInput = [...]struct {
 	{
 		props: []interface{}{
 			map[string]interface{}{
-				"name":  "nonExistingProperty",
-				"value": "dummy",
+				"name":      "nonExistingProperty",
+				"value":     "dummy",
+				"signature": "signed",
+			},
+		},
+		expProperties: &Properties{
+			raw: []RawProperty{
+				{Name: "nonExistingProperty", Value: "dummy", Signature: "signed"},
 			},
 		},
-		expProperties: &Properties{},
 	},
 	{
 		props: []interface{}{
@@ -349,9 +410,13 @@ var testBuildPropertiesInput = [...]struct {
 			},
 		},
 		expProperties: &Properties{
-			SkinURL: "http://textures.minecraft.net/texture/317a41c7a315821e36ee8c7c8c3947174e41b552eb4168b7127c2d5b82face0",
-			CapeURL: "http://textures.minecraft.net/texture/ec80a225b145c812a6ef1ca29af0f3ebf02163874d1a66e53bac99965225e0",
-			Model:   Steve,
+			SkinURL:   "http://textures.minecraft.net/texture/317a41c7a315821e36ee8c7c8c3947174e41b552eb4168b7127c2d5b82face0",
+			CapeURL:   "http://textures.minecraft.net/texture/ec80a225b145c812a6ef1ca29af0f3ebf02163874d1a66e53bac99965225e0",
+			Model:     Steve,
+			Timestamp: msToTime(1493875207206),
+			raw: []RawProperty{
+				{Name: "textures", Value: "eyJ0aW1lc3RhbXAiOjE0OTM4NzUyMDcyMDYsInByb2ZpbGVJZCI6ImQ5MGI2OGJjODE3MjQzMjlhMDQ3ZjExODZkY2Q0MzM2IiwicHJvZmlsZU5hbWUiOiJha3Jvbm1hbjEiLCJ0ZXh0dXJlcyI6eyJTS0lOIjp7InVybCI6Imh0dHA6Ly90ZXh0dXJlcy5taW5lY3JhZnQubmV0L3RleHR1cmUvMzE3YTQxYzdhMzE1ODIxZTM2ZWU4YzdjOGMzOTQ3MTc0ZTQxYjU1MmViNDE2OGI3MTI3YzJkNWI4MmZhY2UwIn0sIkNBUEUiOnsidXJsIjoiaHR0cDovL3RleHR1cmVzLm1pbmVjcmFmdC5uZXQvdGV4dHVyZS9lYzgwYTIyNWIxNDVjODEyYTZlZjFjYTI5YWYwZjNlYmYwMjE2Mzg3NGQxYTY2ZTUzYmFjOTk5NjUyMjVlMCJ9fX0="},
+			},
 		},
 	},
 	// Other cases:
@@ -362,7 +427,7 @@ var testBuildPropertiesInput = [...]struct {
 
 func TestBuildProperties(t *testing.T) {
 	for _, tc := range testBuildPropertiesInput {
-		ps, err := buildProperties(tc.props)
+		ps, err := buildProperties(tc.props, "")
 		if !reflect.DeepEqual(ps, tc.expProperties) || err != tc.expErr {
 			t.Errorf(
 				"buildProperties(%#v)\n"+
@@ -375,3 +440,49 @@ func TestBuildProperties(t *testing.T) {
 		}
 	}
 }
+
+func TestRegisterPropertyParser(t *testing.T) {
+	defer func() { propertyPopulators["textures"] = populateTextures }()
+
+	var values []string
+	RegisterPropertyParser("custom", func(value string, p *Properties) error {
+		values = append(values, value)
+		p.CapeURL = value
+		return nil
+	})
+	defer RegisterPropertyParser("custom", nil)
+	RegisterPropertyParser("textures", nil)
+
+	props := []interface{}{
+		map[string]interface{}{
+			"name":  "textures",
+			"value": "!notBase64",
+		},
+		map[string]interface{}{
+			"name":  "custom",
+			"value": "customValue",
+		},
+	}
+	expProperties := &Properties{
+		CapeURL: "customValue",
+		raw: []RawProperty{
+			{Name: "textures", Value: "!notBase64"},
+			{Name: "custom", Value: "customValue"},
+		},
+	}
+
+	ps, err := buildProperties(props, "")
+	if !reflect.DeepEqual(ps, expProperties) || err != nil {
+		t.Errorf(
+			"buildProperties(%#v) with custom parser\n"+
+				"was:  %#v, %s\n"+
+				"want: %#v, <nil>",
+			props,
+			ps, err,
+			expProperties,
+		)
+	}
+	if exp := []string{"customValue"}; !reflect.DeepEqual(values, exp) {
+		t.Errorf("custom parser was called with values %q; want %q", values, exp)
+	}
+}
@@ -0,0 +1,179 @@
+package profile
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PhilipBorgesen/minecraft/internal"
+)
+
+// DefaultCacheTTL is how long a Cache caches profiles unless configured
+// otherwise.
+const DefaultCacheTTL = 10 * time.Minute
+
+// ProfileData is the information about a profile which is stored in a
+// CacheBackend. It only holds exported fields of basic types, so backends may
+// serialize it using e.g. encoding/json or encoding/gob. ProfileData with an
+// empty ID records that no profile is associated with the username it's
+// stored under.
+type ProfileData struct {
+	ID     string // The profile's ID, or "" if there is no such profile.
+	Name   string // The profile's username.
+	Legacy bool   // Whether the profile is known to have no name history.
+}
+
+// CacheBackend stores the profiles cached by a Cache, e.g. in Redis or
+// memcached, to share a cache between several processes. Implementations must
+// be safe for concurrent use by multiple goroutines.
+type CacheBackend interface {
+	// Get returns the data stored under key and true, or false if no data
+	// is stored under key or it has expired.
+	Get(key string) (*ProfileData, bool)
+	// Set stores d under key, replacing any data already stored under key.
+	// d should expire after ttl.
+	Set(key string, d *ProfileData, ttl time.Duration)
+}
+
+// A Cache loads profiles by username like Load does, but caches the profiles
+// loaded, thereby reducing the number of requests made to Mojang's servers.
+// Usernames are cached case-insensitively as Mojang treats them. Since
+// usernames may change, a cached profile may be associated with a different
+// username than the one it's cached under. Configure TTL accordingly.
+//
+// A Cache must not be copied after first use. Its methods are safe for
+// concurrent use by multiple goroutines, but its fields must not be modified
+// after first use.
+type Cache struct {
+	// Backend stores the cached profiles. If nil, profiles are cached in the
+	// memory of the current process.
+	Backend CacheBackend
+	// TTL is how long a loaded profile is cached. If zero, DefaultCacheTTL
+	// is used.
+	TTL time.Duration
+	// NegativeTTL is how long to remember that no profile is associated with
+	// a username, i.e. that Load returned ErrNoSuchProfile. If zero, such
+	// results aren't cached. Since unused usernames may be registered at any
+	// time, NegativeTTL should be short, e.g. a minute.
+	NegativeTTL time.Duration
+
+	once sync.Once
+	mem  *memoryBackend
+
+	_ struct{} // Ensure Cache is constructed using named parameters.
+}
+
+// Load returns the profile currently associated with username, as described
+// for the package-level Load function. ctx must be non-nil. If the profile is
+// cached, it's returned without contacting Mojang's servers. Otherwise it's
+// loaded and cached for c.TTL. If c.NegativeTTL is set, ErrNoSuchProfile is
+// cached likewise for c.NegativeTTL. Other errors aren't cached.
+//
+// The returned profile only has ID, Name and, for legacy profiles,
+// NameHistory set. The client may modify it freely without affecting the
+// cache.
+func (c *Cache) Load(ctx context.Context, username string) (*Profile, error) {
+	internal.CheckContext(ctx, "profile", "Cache.Load")
+
+	p, _, err := c.load(ctx, username)
+	return p, err
+}
+
+// LoadHit is like Load, but also reports whether the result was served from
+// the cache rather than loaded from Mojang's servers, e.g. to log the cache
+// hit rate when tuning c.TTL. A cached ErrNoSuchProfile counts as served from
+// the cache. ctx must be non-nil.
+func (c *Cache) LoadHit(ctx context.Context, username string) (p *Profile, fromCache bool, err error) {
+	internal.CheckContext(ctx, "profile", "Cache.LoadHit")
+
+	return c.load(ctx, username)
+}
+
+func (c *Cache) load(ctx context.Context, username string) (*Profile, bool, error) {
+	b := c.backend()
+	key := strings.ToLower(username)
+	if d, ok := b.Get(key); ok {
+		if d.ID == "" {
+			return nil, true, ErrNoSuchProfile
+		}
+		return d.profile(), true, nil
+	}
+
+	p, err := Load(ctx, username)
+	if err == ErrNoSuchProfile && c.NegativeTTL > 0 {
+		b.Set(key, &ProfileData{}, c.NegativeTTL)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	b.Set(key, &ProfileData{ID: p.ID, Name: p.Name, Legacy: p.NameHistory != nil}, c.ttl())
+	return p, false, nil
+}
+
+func (c *Cache) backend() CacheBackend {
+	if c.Backend != nil {
+		return c.Backend
+	}
+	c.once.Do(func() {
+		c.mem = &memoryBackend{entries: make(map[string]memoryEntry)}
+	})
+	return c.mem
+}
+
+func (c *Cache) ttl() time.Duration {
+	if c.TTL == 0 {
+		return DefaultCacheTTL
+	}
+	return c.TTL
+}
+
+// profile returns a new profile holding the information of d.
+func (d *ProfileData) profile() *Profile {
+	p := &Profile{ID: d.ID, Name: d.Name}
+	if d.Legacy {
+		p.NameHistory = emptyHist
+	}
+	return p
+}
+
+// memoryBackend is the CacheBackend used by a Cache when none is configured.
+type memoryBackend struct {
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	pruneSize int // Size of entries at which to prune it.
+}
+
+type memoryEntry struct {
+	data    ProfileData
+	expires time.Time
+}
+
+func (m *memoryBackend) Get(key string) (*ProfileData, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[key]
+	if !ok || !now().Before(e.expires) {
+		return nil, false
+	}
+	d := e.data
+	return &d, true
+}
+
+func (m *memoryBackend) Set(key string, d *ProfileData, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tm := now()
+	if len(m.entries) >= m.pruneSize {
+		// Forget expired entries to keep memory usage bounded
+		for k, e := range m.entries {
+			if !tm.Before(e.expires) {
+				delete(m.entries, k)
+			}
+		}
+		m.pruneSize = 2*len(m.entries) + 64
+	}
+	m.entries[key] = memoryEntry{data: *d, expires: tm.Add(ttl)}
+}
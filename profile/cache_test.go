@@ -0,0 +1,260 @@
+package profile
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCache_Load(t *testing.T) {
+	origTransport := client.Transport
+	origNow := now
+	defer func() {
+		client.Transport = origTransport
+		now = origNow
+	}()
+
+	tm := time.Date(2017, 05, 26, 12, 00, 00, 00, time.UTC)
+	now = func() time.Time { return tm }
+
+	ct := &countingTransport{rt: http.NewFileTransport(http.Dir("testdata"))}
+	client.Transport = ct
+
+	c := &Cache{TTL: time.Minute}
+	exp := &Profile{ID: "087cc153c3434ff7ac497de1569affa1", Name: "Nergalic"}
+
+	for i, username := range []string{"nergalic", "Nergalic", "NERGALIC"} {
+		p, err := c.Load(context.Background(), username)
+		if !reflect.DeepEqual(p, exp) || err != nil {
+			t.Errorf("Cache.Load(ctx, %q)\n"+
+				" was: %#v, %v\n"+
+				"want: %#v, <nil>",
+				username, p, err, exp)
+		}
+		if n := ct.requests(); n != 1 {
+			t.Errorf("Cache.Load(ctx, %q) made %d requests in total after %d loads; want 1", username, n, i+1)
+		}
+	}
+
+	p, _ := c.Load(context.Background(), "nergalic")
+	p.Name = "modified"
+	if p, _ := c.Load(context.Background(), "nergalic"); p.Name != exp.Name {
+		t.Errorf("modifying profile returned by Cache.Load modified cache; Name was %q, want %q", p.Name, exp.Name)
+	}
+
+	tm = tm.Add(time.Minute)
+	if _, err := c.Load(context.Background(), "nergalic"); err != nil || ct.requests() != 2 {
+		t.Errorf("Cache.Load(ctx, \"nergalic\") after TTL expired made %d requests in total, error %v; want 2, <nil>", ct.requests(), err)
+	}
+
+	// Errors aren't cached
+	for i := 0; i < 2; i++ {
+		if _, err := c.Load(context.Background(), "demoAccount"); err != ErrDemoProfile {
+			t.Errorf("Cache.Load(ctx, \"demoAccount\") returned error %v; want %s", err, ErrDemoProfile)
+		}
+	}
+	if n := ct.requests(); n != 4 {
+		t.Errorf("Cache.Load(ctx, \"demoAccount\") twice made %d requests in total; want 4", n)
+	}
+}
+
+var testCacheLoadHitInput = [...]struct {
+	username     string
+	expErr       error
+	expFromCache bool
+}{
+	{username: "nergalic", expErr: nil, expFromCache: false},
+	{username: "NERGALIC", expErr: nil, expFromCache: true},
+	{username: "demoAccount", expErr: ErrDemoProfile, expFromCache: false},
+	{username: "doesNotExist", expErr: ErrNoSuchProfile, expFromCache: false},
+	{username: "doesNotExist", expErr: ErrNoSuchProfile, expFromCache: true},
+}
+
+func TestCache_LoadHit(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	client.Transport = http.NewFileTransport(http.Dir("testdata")) // 404 Not Found for doesNotExist
+
+	c := &Cache{NegativeTTL: time.Minute}
+	for _, tc := range testCacheLoadHitInput {
+		pr, fromCache, err := c.LoadHit(context.Background(), tc.username)
+		if err != tc.expErr || fromCache != tc.expFromCache || (pr == nil) != (err != nil) {
+			t.Errorf("Cache.LoadHit(ctx, %q) was %v, %t, %v; want profile: %t, %t, %v", tc.username, pr, fromCache, err, tc.expErr == nil, tc.expFromCache, tc.expErr)
+		}
+	}
+}
+
+func TestCache_LoadLegacy(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	client.Transport = responseTransport{200, `{"id":"069a79f444e94726a5befca90e38aaf5","name":"Notch","legacy":true}`}
+
+	c := &Cache{}
+	exp := &Profile{ID: "069a79f444e94726a5befca90e38aaf5", Name: "Notch", NameHistory: emptyHist}
+	for i := 0; i < 2; i++ {
+		if p, err := c.Load(context.Background(), "notch"); !reflect.DeepEqual(p, exp) || p.NameHistory == nil || err != nil {
+			t.Errorf("Cache.Load(ctx, \"notch\")\n"+
+				" was: %#v, %v\n"+
+				"want: %#v, <nil>",
+				p, err, exp)
+		}
+	}
+}
+
+var testCacheNegativeInput = [...]struct {
+	negativeTTL time.Duration
+	wait        time.Duration
+	transport   http.RoundTripper
+	expErr      error
+	expRequests int
+}{
+	{ // Negative results not cached by default
+		transport:   responseTransport{204, ""},
+		expErr:      ErrNoSuchProfile,
+		expRequests: 2,
+	},
+	{
+		negativeTTL: time.Minute,
+		wait:        59 * time.Second,
+		transport:   responseTransport{204, ""},
+		expErr:      ErrNoSuchProfile,
+		expRequests: 1,
+	},
+	{
+		negativeTTL: time.Minute,
+		wait:        time.Minute,
+		transport:   responseTransport{204, ""},
+		expErr:      ErrNoSuchProfile,
+		expRequests: 2,
+	},
+	{ // Demo profiles aren't cached
+		negativeTTL: time.Minute,
+		transport:   http.NewFileTransport(http.Dir("testdata")),
+		expErr:      ErrDemoProfile,
+		expRequests: 2,
+	},
+}
+
+func TestCache_LoadNegative(t *testing.T) {
+	origTransport := client.Transport
+	origNow := now
+	defer func() {
+		client.Transport = origTransport
+		now = origNow
+	}()
+
+	for _, tc := range testCacheNegativeInput {
+		tm := time.Date(2017, 05, 26, 12, 00, 00, 00, time.UTC)
+		now = func() time.Time { return tm }
+
+		ct := &countingTransport{rt: tc.transport}
+		client.Transport = ct
+
+		c := &Cache{NegativeTTL: tc.negativeTTL}
+		for i := 0; i < 2; i++ {
+			if p, err := c.Load(context.Background(), "demoAccount"); p != nil || err != tc.expErr {
+				t.Errorf("Cache{NegativeTTL: %s}.Load(ctx, \"demoAccount\") was %#v, %v; want <nil>, %s", tc.negativeTTL, p, err, tc.expErr)
+			}
+			tm = tm.Add(tc.wait)
+		}
+		if n := ct.requests(); n != tc.expRequests {
+			t.Errorf("Cache{NegativeTTL: %s}.Load(ctx, \"demoAccount\") twice %s apart made %d requests; want %d", tc.negativeTTL, tc.wait, n, tc.expRequests)
+		}
+	}
+}
+
+func TestCache_Backend(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	client.Transport = http.NewFileTransport(http.Dir("testdata"))
+
+	b := &mapBackend{m: make(map[string]*ProfileData)}
+	c := &Cache{Backend: b}
+
+	if _, err := c.Load(context.Background(), "nergalic"); err != nil {
+		t.Fatalf("Cache.Load(ctx, \"nergalic\") failed: %v", err)
+	}
+	exp := &ProfileData{ID: "087cc153c3434ff7ac497de1569affa1", Name: "Nergalic"}
+	if d := b.m["nergalic"]; !reflect.DeepEqual(d, exp) || b.ttl != DefaultCacheTTL {
+		t.Errorf("Cache.Load(ctx, \"nergalic\") stored %#v for %s; want %#v for %s", d, b.ttl, exp, DefaultCacheTTL)
+	}
+
+	client.Transport = errorTransport{testError}
+	b.m["nergalic"] = &ProfileData{ID: "087cc153c3434ff7ac497de1569affa1", Name: "Cached"}
+	if p, err := c.Load(context.Background(), "NERGALIC"); err != nil || p.Name != "Cached" {
+		t.Errorf("Cache.Load(ctx, \"NERGALIC\") was %#v, %v; want profile from backend", p, err)
+	}
+}
+
+func TestMemoryBackendPrune(t *testing.T) {
+	origNow := now
+	defer func() { now = origNow }()
+
+	tm := time.Date(2017, 05, 26, 12, 00, 00, 00, time.UTC)
+	now = func() time.Time { return tm }
+
+	m := &memoryBackend{entries: make(map[string]memoryEntry)}
+	m.Set("expired", &ProfileData{}, time.Second)
+	tm = tm.Add(time.Second)
+	if _, ok := m.Get("expired"); ok {
+		t.Error("memoryBackend returned entry after its TTL")
+	}
+
+	for i := 0; len(m.entries) < m.pruneSize; i++ {
+		m.Set(string(rune('a'+i)), &ProfileData{}, time.Hour)
+	}
+	m.Set("fresh", &ProfileData{}, time.Hour)
+	if _, ok := m.entries["expired"]; ok {
+		t.Error("memoryBackend didn't prune expired entry")
+	}
+	if _, ok := m.Get("fresh"); !ok {
+		t.Error("memoryBackend pruned unexpired entry")
+	}
+}
+
+/*************
+* TEST UTILS *
+*************/
+
+// countingTransport counts the requests it passes on to rt.
+type countingTransport struct {
+	mu sync.Mutex
+	rt http.RoundTripper
+	n  int
+}
+
+func (ct *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ct.mu.Lock()
+	ct.n++
+	ct.mu.Unlock()
+	return ct.rt.RoundTrip(req)
+}
+
+func (ct *countingTransport) requests() int {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	return ct.n
+}
+
+// mapBackend is a CacheBackend which never expires entries, but records the
+// TTL last passed to Set.
+type mapBackend struct {
+	m   map[string]*ProfileData
+	ttl time.Duration
+}
+
+func (b *mapBackend) Get(key string) (*ProfileData, bool) {
+	d, ok := b.m[key]
+	return d, ok
+}
+
+func (b *mapBackend) Set(key string, d *ProfileData, ttl time.Duration) {
+	b.m[key] = d
+	b.ttl = ttl
+}
@@ -1,12 +1,114 @@
 package profile
 
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	// DefaultProfileBaseURL is the default base URL of the Mojang API
+	// endpoints used to look up profiles and name histories.
+	DefaultProfileBaseURL = "https://api.mojang.com"
+	// DefaultSessionBaseURL is the default base URL of the Mojang session
+	// server endpoints used to load profile properties.
+	DefaultSessionBaseURL = "https://sessionserver.mojang.com"
+	// DefaultAuthBaseURL is the default base URL of the Mojang authentication
+	// server endpoints.
+	DefaultAuthBaseURL = "https://authserver.mojang.com"
+)
+
+// Endpoint paths relative to the base URL of their endpoint group.
 const (
-	loadURL                = "https://api.mojang.com/users/profiles/minecraft/%s"
-	loadAtTimeURL          = "https://api.mojang.com/users/profiles/minecraft/%s?at=%d"
-	loadWithNameHistoryURL = "https://api.mojang.com/user/profiles/%s/names"
-	loadWithPropertiesURL  = "https://sessionserver.mojang.com/session/minecraft/profile/%s"
-	loadManyURL            = "https://api.mojang.com/profiles/minecraft"
+	loadPath                = "/users/profiles/minecraft/%s"
+	loadAtTimePath          = "/users/profiles/minecraft/%s?at=%d"
+	loadWithNameHistoryPath = "/user/profiles/%s/names"
+	loadManyPath            = "/profiles/minecraft"
+	loadWithPropertiesPath  = "/session/minecraft/profile/%s"
+)
 
+const (
 	steveSkinURL = "http://assets.mojang.com/SkinTemplates/steve.png"
 	alexSkinURL  = "http://assets.mojang.com/SkinTemplates/alex.png"
 )
+
+// Config specifies where the endpoints used by this package are located, e.g.
+// to direct requests through a caching proxy. Each field is the base URL of a
+// group of endpoints, which is prefixed to the paths of the Mojang endpoints.
+// Empty fields select the default base URL of the group.
+type Config struct {
+	// ProfileBaseURL is the base URL of the endpoints used by Load,
+	// LoadAtTime, LoadMany, LoadByID, LoadWithNameHistory and
+	// Profile.LoadNameHistory. It defaults to DefaultProfileBaseURL.
+	ProfileBaseURL string
+	// SessionBaseURL is the base URL of the endpoint used by
+	// LoadWithProperties and Profile.LoadProperties. It defaults to
+	// DefaultSessionBaseURL. Set it to test against a preview session
+	// server; properties this package doesn't know how to parse remain
+	// available from Properties.Raw.
+	SessionBaseURL string
+	// AuthBaseURL is the base URL of the Mojang authentication endpoints. No
+	// function in this package calls them yet; the field is reserved for
+	// authenticated requests such as skin uploads. It defaults to
+	// DefaultAuthBaseURL.
+	AuthBaseURL string
+
+	_ struct{} // Ensure Config is constructed using named parameters.
+}
+
+// config holds the current Config, as set by Configure.
+var config atomic.Value
+
+func init() {
+	Configure(Config{})
+}
+
+// Configure sets the base URLs of the endpoints used by this package to those
+// given by c. For example, to only request profile properties through a cache:
+//	profile.Configure(profile.Config{
+//		SessionBaseURL: "https://session-cache.example.com",
+//	})
+//
+// Configure replaces the entire configuration at once; every request made by
+// this package uses either the old or the new configuration, never a mix of
+// the two. Configure may be called concurrently with other functions of this
+// package, but requests which already have been started aren't affected.
+func Configure(c Config) {
+	if c.ProfileBaseURL == "" {
+		c.ProfileBaseURL = DefaultProfileBaseURL
+	}
+	if c.SessionBaseURL == "" {
+		c.SessionBaseURL = DefaultSessionBaseURL
+	}
+	if c.AuthBaseURL == "" {
+		c.AuthBaseURL = DefaultAuthBaseURL
+	}
+	c.ProfileBaseURL = strings.TrimRight(c.ProfileBaseURL, "/")
+	c.SessionBaseURL = strings.TrimRight(c.SessionBaseURL, "/")
+	c.AuthBaseURL = strings.TrimRight(c.AuthBaseURL, "/")
+	config.Store(c)
+}
+
+// CurrentConfig returns the configuration currently in effect, i.e. the Config
+// last passed to Configure with defaults applied to empty fields and trailing
+// slashes removed. To change a single setting, modify the result and pass it
+// to Configure:
+//	c := profile.CurrentConfig()
+//	c.ProfileBaseURL = "https://api-cache.example.com"
+//	profile.Configure(c)
+//
+// Note that doing so from several goroutines at once may lose changes, since
+// the settings may be changed between the calls to CurrentConfig and Configure.
+func CurrentConfig() Config {
+	return config.Load().(Config)
+}
+
+// profileURL returns the URL of the profile endpoint at path, formatted with a.
+func profileURL(path string, a ...interface{}) string {
+	return CurrentConfig().ProfileBaseURL + fmt.Sprintf(path, a...)
+}
+
+// sessionURL returns the URL of the session endpoint at path, formatted with a.
+func sessionURL(path string, a ...interface{}) string {
+	return CurrentConfig().SessionBaseURL + fmt.Sprintf(path, a...)
+}
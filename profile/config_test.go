@@ -0,0 +1,125 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+var testConfigureInput = [...]struct {
+	config     Config
+	expProfile string
+	expSession string
+}{
+	{
+		config:     Config{},
+		expProfile: "https://api.mojang.com/users/profiles/minecraft/nergalic",
+		expSession: "https://sessionserver.mojang.com/session/minecraft/profile/087cc153c3434ff7ac497de1569affa1",
+	},
+	{
+		config:     Config{SessionBaseURL: "http://cache.example.com/session/"},
+		expProfile: "https://api.mojang.com/users/profiles/minecraft/nergalic",
+		expSession: "http://cache.example.com/session/session/minecraft/profile/087cc153c3434ff7ac497de1569affa1",
+	},
+	{
+		config:     Config{ProfileBaseURL: "http://localhost:8080", SessionBaseURL: "http://localhost:8081"},
+		expProfile: "http://localhost:8080/users/profiles/minecraft/nergalic",
+		expSession: "http://localhost:8081/session/minecraft/profile/087cc153c3434ff7ac497de1569affa1",
+	},
+}
+
+func TestConfigure(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+	defer Configure(Config{})
+
+	rt := &urlStoreTransport{}
+	client.Transport = rt
+
+	for _, tc := range testConfigureInput {
+		Configure(tc.config)
+
+		Load(context.Background(), "nergalic")
+		if rt.URL != tc.expProfile {
+			t.Errorf("Load(ctx, \"nergalic\") with %+v requested %q; want %q", tc.config, rt.URL, tc.expProfile)
+		}
+
+		LoadWithProperties(context.Background(), "087cc153c3434ff7ac497de1569affa1")
+		if rt.URL != tc.expSession {
+			t.Errorf("LoadWithProperties(ctx, id) with %+v requested %q; want %q", tc.config, rt.URL, tc.expSession)
+		}
+	}
+}
+
+var testCurrentConfigInput = [...]struct {
+	config    Config
+	expConfig Config
+}{
+	{
+		config:    Config{},
+		expConfig: Config{ProfileBaseURL: DefaultProfileBaseURL, SessionBaseURL: DefaultSessionBaseURL, AuthBaseURL: DefaultAuthBaseURL},
+	},
+	{
+		config:    Config{SessionBaseURL: "http://cache.example.com/session/"},
+		expConfig: Config{ProfileBaseURL: DefaultProfileBaseURL, SessionBaseURL: "http://cache.example.com/session", AuthBaseURL: DefaultAuthBaseURL},
+	},
+	{
+		config:    Config{ProfileBaseURL: "http://localhost:8080//", SessionBaseURL: "http://localhost:8081", AuthBaseURL: "http://localhost:8082/"},
+		expConfig: Config{ProfileBaseURL: "http://localhost:8080", SessionBaseURL: "http://localhost:8081", AuthBaseURL: "http://localhost:8082"},
+	},
+}
+
+func TestCurrentConfig(t *testing.T) {
+	defer Configure(Config{})
+
+	for _, tc := range testCurrentConfigInput {
+		Configure(tc.config)
+		if c := CurrentConfig(); c != tc.expConfig {
+			t.Errorf("CurrentConfig() after Configure(%+v) was %+v; want %+v", tc.config, c, tc.expConfig)
+		}
+	}
+}
+
+func TestConfigureConcurrently(t *testing.T) {
+	defer Configure(Config{})
+
+	a := Config{ProfileBaseURL: "http://a.example.com", SessionBaseURL: "http://a.example.com"}
+	b := Config{ProfileBaseURL: "http://b.example.com", SessionBaseURL: "http://b.example.com"}
+
+	Configure(a)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			Configure(b)
+			Configure(a)
+		}
+	}()
+
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		if c := CurrentConfig(); c.ProfileBaseURL != c.SessionBaseURL {
+			t.Errorf("CurrentConfig() returned inconsistent configuration %+v", c)
+			<-done
+			return
+		}
+	}
+}
+
+/***************
+*  TEST UTILS  *
+***************/
+
+type urlStoreTransport struct {
+	URL string
+}
+
+func (ut *urlStoreTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ut.URL = req.URL.String()
+	return nil, errors.New("RoundTrip was called")
+}
@@ -0,0 +1,87 @@
+package profile
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PhilipBorgesen/minecraft/uuid"
+)
+
+// PropertiesCooldown is how long Mojang requires clients to wait between
+// requests for the properties of the same profile. It is the rate limit
+// reported by RateLimitFor(OpLoadProperties).
+const PropertiesCooldown = time.Minute
+
+var tracker *cooldownTracker // nil when tracking is disabled.
+
+// TrackPropertiesCooldown enables or disables tracking of when the properties
+// of each profile last were requested. When enabled, LoadWithProperties and
+// Profile.LoadProperties return an ErrPropertiesCooldown error instead of
+// making a request which otherwise would fail with ErrTooManyRequests.
+// Disabling tracking forgets all recorded requests.
+//
+// TrackPropertiesCooldown must not be called concurrently with other functions
+// of this package. Enable tracking once before loading any profiles.
+func TrackPropertiesCooldown(enabled bool) {
+	if enabled {
+		if tracker == nil {
+			tracker = &cooldownTracker{last: make(map[string]time.Time)}
+		}
+	} else {
+		tracker = nil
+	}
+}
+
+// now returns the current time. It may be replaced by tests.
+var now = time.Now
+
+// cooldownTracker records when the properties of profiles last were requested.
+type cooldownTracker struct {
+	mu        sync.Mutex
+	last      map[string]time.Time // Indexed by cooldownKey of profile ID.
+	pruneSize int                  // Size of last at which to prune it.
+}
+
+// remaining returns how long to wait before the properties of the profile
+// identified by id may be requested again, or 0 if they may be requested now.
+func (t *cooldownTracker) remaining(id string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if last, ok := t.last[cooldownKey(id)]; ok {
+		if d := last.Add(PropertiesCooldown).Sub(now()); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// record records that the properties of the profile identified by id were
+// requested just now.
+func (t *cooldownTracker) record(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tm := now()
+	if len(t.last) >= t.pruneSize {
+		// Forget expired requests to keep memory usage bounded
+		for k, last := range t.last {
+			if tm.Sub(last) >= PropertiesCooldown {
+				delete(t.last, k)
+			}
+		}
+		t.pruneSize = 2*len(t.last) + 64
+	}
+	t.last[cooldownKey(id)] = tm
+}
+
+// cooldownKey returns the key under which requests for the properties of the
+// profile identified by id are recorded, such that the hyphenated and undashed
+// forms of the same ID share a cooldown. IDs which aren't UUIDs are lower cased.
+func cooldownKey(id string) string {
+	if u, err := uuid.ToUndashed(id); err == nil {
+		return u
+	}
+	return strings.ToLower(id)
+}
@@ -0,0 +1,154 @@
+package profile
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTrackPropertiesCooldown(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+	client.Transport = http.NewFileTransport(http.Dir("testdata"))
+
+	origNow := now
+	defer func() { now = origNow }()
+	tm := time.Date(2017, 01, 01, 12, 00, 00, 00, time.UTC)
+	now = func() time.Time { return tm }
+
+	TrackPropertiesCooldown(true)
+	defer TrackPropertiesCooldown(false)
+
+	const id = "087cc153c3434ff7ac497de1569affa1"
+	load := func(id string) error {
+		_, err := LoadWithProperties(context.Background(), id)
+		return err
+	}
+
+	if err := load(id); err != nil {
+		t.Fatalf("LoadWithProperties(ctx, %q) failed: %s", id, err)
+	}
+
+	tm = tm.Add(15 * time.Second)
+	exp := ErrPropertiesCooldown{45 * time.Second}
+	if err := load(id); !reflect.DeepEqual(err, exp) {
+		t.Errorf("LoadWithProperties(ctx, %q) after 15s returned error %#v; want %#v", id, err, exp)
+	}
+	if err := load("087CC153C3434FF7AC497DE1569AFFA1"); !reflect.DeepEqual(err, exp) {
+		t.Errorf("LoadWithProperties(ctx, upper case id) after 15s returned error %#v; want %#v", err, exp)
+	}
+
+	tm = tm.Add(45 * time.Second)
+	if err := load(id); err != nil {
+		t.Errorf("LoadWithProperties(ctx, %q) after cooldown failed: %s", id, err)
+	}
+
+	// Requests rejected due to rate limits must also be waited out
+	client.Transport = statusOverrideTransport{status: 429, transport: http.NewFileTransport(http.Dir("testdata"))}
+	if err := load("tooManyRequests"); err != ErrTooManyRequests {
+		t.Fatalf("LoadWithProperties(ctx, \"tooManyRequests\") returned error %v; want %s", err, ErrTooManyRequests)
+	}
+	exp = ErrPropertiesCooldown{PropertiesCooldown}
+	if err := load("tooManyRequests"); !reflect.DeepEqual(err, exp) {
+		t.Errorf("LoadWithProperties(ctx, \"tooManyRequests\") returned error %#v; want %#v", err, exp)
+	}
+
+	// Disabling tracking forgets past requests
+	TrackPropertiesCooldown(false)
+	TrackPropertiesCooldown(true)
+	if err := load("tooManyRequests"); err != ErrTooManyRequests {
+		t.Errorf("LoadWithProperties(ctx, \"tooManyRequests\") after reset returned error %v; want %s", err, ErrTooManyRequests)
+	}
+}
+
+func TestLoadWithPropertiesForce(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+	ct := &countingTransport{rt: http.NewFileTransport(http.Dir("testdata"))}
+	client.Transport = ct
+
+	origNow := now
+	defer func() { now = origNow }()
+	tm := time.Date(2017, 01, 01, 12, 00, 00, 00, time.UTC)
+	now = func() time.Time { return tm }
+
+	TrackPropertiesCooldown(true)
+	defer TrackPropertiesCooldown(false)
+
+	const id = "087cc153c3434ff7ac497de1569affa1"
+	exp, err := LoadWithProperties(context.Background(), id)
+	if err != nil {
+		t.Fatalf("LoadWithProperties(ctx, %q) failed: %s", id, err)
+	}
+
+	tm = tm.Add(15 * time.Second)
+	pr, err := LoadWithPropertiesForce(context.Background(), id)
+	if !reflect.DeepEqual(pr, exp) || err != nil || ct.requests() != 2 {
+		t.Errorf(
+			"LoadWithPropertiesForce(ctx, %q) during cooldown made %d requests in total\n"+
+				" was: %#v, %s\n"+
+				"want: %#v, <nil>",
+			id, ct.requests(), pr, p(err), exp,
+		)
+	}
+
+	// The forced request must restart the cooldown
+	tm = tm.Add(50 * time.Second)
+	expErr := ErrPropertiesCooldown{10 * time.Second}
+	if _, err := LoadWithProperties(context.Background(), id); !reflect.DeepEqual(err, expErr) {
+		t.Errorf("LoadWithProperties(ctx, %q) after forced request returned error %#v; want %#v", id, err, expErr)
+	}
+
+	if pr, err := LoadWithPropertiesForce(context.Background(), ""); pr != nil || err != ErrNoSuchProfile {
+		t.Errorf("LoadWithPropertiesForce(ctx, \"\") was %#v, %v; want <nil>, %s", pr, err, ErrNoSuchProfile)
+	}
+}
+
+func TestCooldownTrackerPrune(t *testing.T) {
+	origNow := now
+	defer func() { now = origNow }()
+	tm := time.Date(2017, 01, 01, 12, 00, 00, 00, time.UTC)
+	now = func() time.Time { return tm }
+
+	tr := &cooldownTracker{last: make(map[string]time.Time)}
+	for i := 0; i < 100; i++ {
+		tr.record(fmt.Sprintf("old%d", i))
+	}
+
+	tm = tm.Add(PropertiesCooldown)
+	for i := 0; i < 200; i++ {
+		tr.record(fmt.Sprintf("new%d", i))
+	}
+	for i := 0; i < 100; i++ {
+		if id := fmt.Sprintf("old%d", i); !tr.last[id].IsZero() {
+			t.Fatalf("cooldownTracker didn't forget expired request for %q", id)
+		}
+	}
+	if d := tr.remaining("new0"); d != PropertiesCooldown {
+		t.Errorf("cooldownTracker.remaining(\"new0\") was %s; want %s", d, PropertiesCooldown)
+	}
+}
+
+func TestCooldownTrackerIDForms(t *testing.T) {
+	origNow := now
+	defer func() { now = origNow }()
+	tm := time.Date(2017, 01, 01, 12, 00, 00, 00, time.UTC)
+	now = func() time.Time { return tm }
+
+	tr := &cooldownTracker{last: make(map[string]time.Time)}
+	tr.record("069A79F4-44E9-4726-A5BE-FCA90E38AAF5")
+	if d := tr.remaining("069a79f444e94726a5befca90e38aaf5"); d != PropertiesCooldown {
+		t.Errorf("cooldownTracker.remaining of undashed ID after recording hyphenated ID was %s; want %s", d, PropertiesCooldown)
+	}
+}
+
+func TestErrPropertiesCooldown_Error(t *testing.T) {
+	err := ErrPropertiesCooldown{42 * time.Second}
+	exp := "minecraft/profile: properties requested too recently; retry in 42s"
+	if s := err.Error(); s != exp {
+		t.Errorf("%#v.Error() = %q; want %q", err, s, exp)
+	}
+}
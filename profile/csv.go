@@ -0,0 +1,98 @@
+package profile
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+)
+
+// CSVColumn is a column which WriteCSV may write. The value of each column is
+// its header.
+type CSVColumn string
+
+const (
+	ColumnID          CSVColumn = "uuid"    // Profile.ID
+	ColumnName        CSVColumn = "name"    // Profile.Name
+	ColumnNameHistory CSVColumn = "history" // Past usernames, last first, separated by ';'
+	ColumnSkinURL     CSVColumn = "skin"    // Properties.SkinURL
+	ColumnCapeURL     CSVColumn = "cape"    // Properties.CapeURL
+	ColumnModel       CSVColumn = "model"   // Properties.Model, e.g. "Alex"
+)
+
+// ErrUnknownColumn is returned by WriteCSV when passed a column not declared by
+// this package.
+var ErrUnknownColumn = errors.New("minecraft/profile: unknown CSV column")
+
+// WriteCSV writes ps to w as CSV, one row per profile preceded by a header row
+// naming the columns, e.g. to output the profiles resolved by LoadManyOrdered:
+//	uuid,name
+//	087cc153c3434ff7ac497de1569affa1,Nergalic
+//
+// columns selects which columns to write, in order; if none are given,
+// ColumnID and ColumnName are written. Fields are quoted as necessary.
+// Columns whose information isn't loaded, e.g. ColumnSkinURL of a profile
+// without Properties, are empty, and so are all columns of nil profiles, so
+// rows stay aligned with ps. If an unknown column is given, ErrUnknownColumn
+// is returned before anything is written. Otherwise errors writing to w are
+// returned.
+func WriteCSV(w io.Writer, ps []*Profile, columns ...CSVColumn) error {
+	if len(columns) == 0 {
+		columns = []CSVColumn{ColumnID, ColumnName}
+	}
+	row := make([]string, len(columns))
+	for i, c := range columns {
+		switch c {
+		case ColumnID, ColumnName, ColumnNameHistory, ColumnSkinURL, ColumnCapeURL, ColumnModel:
+			row[i] = string(c)
+		default:
+			return ErrUnknownColumn
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(row); err != nil {
+		return err
+	}
+	for _, p := range ps {
+		for i, c := range columns {
+			row[i] = csvField(p, c)
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvField returns the value of column c of p.
+func csvField(p *Profile, c CSVColumn) string {
+	if p == nil {
+		return ""
+	}
+	switch c {
+	case ColumnID:
+		return p.ID
+	case ColumnName:
+		return p.Name
+	case ColumnNameHistory:
+		names := make([]string, len(p.NameHistory))
+		for i, n := range p.NameHistory {
+			names[i] = n.Name
+		}
+		return strings.Join(names, ";")
+	}
+
+	if p.Properties == nil {
+		return ""
+	}
+	switch c {
+	case ColumnSkinURL:
+		return p.Properties.SkinURL
+	case ColumnCapeURL:
+		return p.Properties.CapeURL
+	default: // ColumnModel
+		return p.Properties.Model.String()
+	}
+}
@@ -0,0 +1,75 @@
+package profile
+
+import (
+	"bytes"
+	"testing"
+)
+
+var testWriteCSVInput = [...]struct {
+	ps      []*Profile
+	columns []CSVColumn
+	expCSV  string
+	expErr  error
+}{
+	{
+		ps:     nil,
+		expCSV: "uuid,name\n",
+	},
+	{
+		ps: []*Profile{
+			{ID: "087cc153c3434ff7ac497de1569affa1", Name: "Nergalic"},
+			nil,
+			{ID: "cabefc91b5df4c87886a6c604da2e46f", Name: "AxeLaw"},
+		},
+		expCSV: "uuid,name\n" +
+			"087cc153c3434ff7ac497de1569affa1,Nergalic\n" +
+			",\n" +
+			"cabefc91b5df4c87886a6c604da2e46f,AxeLaw\n",
+	},
+	{
+		ps: []*Profile{
+			{
+				ID:          "087cc153c3434ff7ac497de1569affa1",
+				Name:        "Nergalic",
+				NameHistory: []PastName{{Name: "B"}, {Name: "GeneralSezuan", Original: true}},
+				Properties: &Properties{
+					SkinURL: "http://textures.minecraft.net/texture/5b40",
+					Model:   Alex,
+				},
+			},
+			{Name: "Not yet loaded"},
+		},
+		columns: []CSVColumn{ColumnName, ColumnNameHistory, ColumnModel, ColumnSkinURL, ColumnCapeURL},
+		expCSV: "name,history,model,skin,cape\n" +
+			"Nergalic,B;GeneralSezuan,Alex,http://textures.minecraft.net/texture/5b40,\n" +
+			"Not yet loaded,,,,\n",
+	},
+	{ // Fields are escaped
+		ps:      []*Profile{{ID: "a,b", Name: `say "hi"`}},
+		columns: []CSVColumn{ColumnName, ColumnID},
+		expCSV:  "name,uuid\n" + `"say ""hi""","a,b"` + "\n",
+	},
+	{
+		ps:      []*Profile{{ID: "087cc153c3434ff7ac497de1569affa1"}},
+		columns: []CSVColumn{ColumnID, CSVColumn("UUID")},
+		expCSV:  "",
+		expErr:  ErrUnknownColumn,
+	},
+}
+
+func TestWriteCSV(t *testing.T) {
+	for _, tc := range testWriteCSVInput {
+		var buf bytes.Buffer
+		err := WriteCSV(&buf, tc.ps, tc.columns...)
+		if s := buf.String(); s != tc.expCSV || err != tc.expErr {
+			t.Errorf(
+				"WriteCSV(w, %v, %q...)\n"+
+					" was: %q, %v\n"+
+					"want: %q, %v",
+				tc.ps, tc.columns,
+				s, err,
+				tc.expCSV, tc.expErr,
+			)
+		}
+	}
+}
@@ -3,24 +3,81 @@ package profile
 import (
 	"errors"
 	"fmt"
+	"time"
+
+	"github.com/PhilipBorgesen/minecraft/internal"
 )
 
 var (
 	ErrNoCape        = errors.New("minecraft/profile: profile has no cape")
+	ErrNoSkin        = errors.New("minecraft/profile: profile has no custom skin")
 	ErrNoSuchProfile = errors.New("minecraft/profile: no such profile")
 	ErrUnsetPlayerID = errors.New("minecraft/profile: player id is not set")
+	ErrInvalidID     = errors.New("minecraft/profile: invalid profile id")
 	ErrUnknownModel  = errors.New("minecraft/profile: unknown model")
+	ErrInvalidTime   = errors.New("minecraft/profile: time is neither in RFC 3339 format nor Unix seconds")
+
+	// ErrInvalidUsername is returned by Resolve when its input is neither a
+	// profile ID nor a username adhering to Mojang's username rules, and by
+	// LoadManyStrict when passed an empty username.
+	ErrInvalidUsername = errors.New("minecraft/profile: neither a profile ID nor a valid username")
+
+	// ErrInvalidTextureURL is reported when loading profile properties whose
+	// textures information holds a skin or cape URL which isn't an absolute
+	// URL. The error is wrapped in a *url.Error, as other parse errors are.
+	ErrInvalidTextureURL = errors.New("minecraft/profile: malformed texture URL")
+
+	// ErrUnknownFormat is reported when JSON data isn't structured as expected.
+	// Such responses from Mojang's servers are reported using a *url.Error
+	// with Op "Parse" wrapping ErrUnknownFormat, while JSON data parsed from
+	// other sources, e.g. by ParseProfilesStream, is reported using
+	// ErrUnknownFormat itself. It is the error the versions package reports
+	// likewise.
+	ErrUnknownFormat = internal.ErrUnknownFormat
 
 	// ErrTooManyRequests is returned if the client has exceeded its server
 	// communication rate limit. At the time of writing, the load operations
-	// have a shared rate limit of 600 requests per 10 minutes.
+	// have a shared rate limit of LoadRateLimit requests per LoadRateWindow.
 	//
 	// Note that the rate limit for reading profile properties is much
 	// stricter: For each profile, profile properties may only be requested
-	// once per minute.
+	// once per PropertiesCooldown. Use RateLimitFor to get the limits
+	// programmatically.
 	ErrTooManyRequests = errors.New("minecraft/profile: request rate limit exceeded")
+
+	// ErrDemoProfile is returned instead of ErrNoSuchProfile when the requested
+	// profile exists, but is a demo profile. Demo profiles are never returned
+	// by this package. For backward compatibility errors.Is reports ErrDemoProfile
+	// to match ErrNoSuchProfile; compare against ErrDemoProfile explicitly to
+	// tell an unused username apart from one held by a demo profile.
+	ErrDemoProfile error = demoProfileError{}
 )
 
+// HTTPStatus returns the HTTP status code of the response from Mojang's servers
+// which caused err, and true, if err was returned because Mojang responded
+// with an unexpected status, e.g. 503 Service Unavailable. Responses handled by
+// this package, such as those reported as ErrNoSuchProfile or
+// ErrTooManyRequests, aren't reported, while error objects which Mojang
+// responded with in place of an error status are reported with status 200.
+// Otherwise HTTPStatus returns 0 and false.
+//
+// Errors returned by the versions package are inspected the same way using
+// versions.HTTPStatus.
+func HTTPStatus(err error) (status int, ok bool) {
+	return internal.HTTPStatus(err)
+}
+
+type demoProfileError struct{}
+
+func (demoProfileError) Error() string {
+	return "minecraft/profile: profile is a demo profile"
+}
+
+// Is reports whether target is ErrNoSuchProfile.
+func (demoProfileError) Is(target error) bool {
+	return target == ErrNoSuchProfile
+}
+
 // An ErrMaxSizeExceeded error is returned when LoadMany is requested to load
 // more than LoadManyMaxSize profiles at once.
 type ErrMaxSizeExceeded struct {
@@ -30,3 +87,50 @@ type ErrMaxSizeExceeded struct {
 func (e ErrMaxSizeExceeded) Error() string {
 	return fmt.Sprintf("minecraft/profile: aggregate request size of %d exceeded maximum of %d", e.Size, LoadManyMaxSize)
 }
+
+// Is reports whether target is an ErrMaxSizeExceeded error of the same Size,
+// or the zero value ErrMaxSizeExceeded{}, such that
+// errors.Is(err, ErrMaxSizeExceeded{}) reports any ErrMaxSizeExceeded error.
+func (e ErrMaxSizeExceeded) Is(target error) bool {
+	t, ok := target.(ErrMaxSizeExceeded)
+	return ok && (t == ErrMaxSizeExceeded{} || t == e)
+}
+
+// An ErrPropertiesCooldown error is returned instead of requesting the
+// properties of a profile which were requested less than PropertiesCooldown
+// ago. It is only returned if tracking is enabled by TrackPropertiesCooldown.
+type ErrPropertiesCooldown struct {
+	Remaining time.Duration // Time until the properties may be requested again.
+}
+
+func (e ErrPropertiesCooldown) Error() string {
+	return fmt.Sprintf("minecraft/profile: properties requested too recently; retry in %s", e.Remaining)
+}
+
+// Is reports whether target is an ErrPropertiesCooldown error of the same
+// Remaining, or the zero value ErrPropertiesCooldown{}, such that
+// errors.Is(err, ErrPropertiesCooldown{}) reports any ErrPropertiesCooldown
+// error.
+func (e ErrPropertiesCooldown) Is(target error) bool {
+	t, ok := target.(ErrPropertiesCooldown)
+	return ok && (t == ErrPropertiesCooldown{} || t == e)
+}
+
+// An ErrHeadsFailed error is returned by DownloadHeads when the heads of some
+// profiles couldn't be downloaded.
+type ErrHeadsFailed struct {
+	Errors map[string]error // Why each head failed, indexed by profile ID.
+}
+
+func (e ErrHeadsFailed) Error() string {
+	return fmt.Sprintf("minecraft/profile: failed to download %d heads", len(e.Errors))
+}
+
+// Is reports whether target is an ErrHeadsFailed error, regardless of its
+// Errors, such that errors.Is(err, ErrHeadsFailed{}) reports any
+// ErrHeadsFailed error. Without it errors.Is couldn't match ErrHeadsFailed
+// errors at all, since they can't be compared using ==.
+func (e ErrHeadsFailed) Is(target error) bool {
+	_, ok := target.(ErrHeadsFailed)
+	return ok
+}
@@ -1,9 +1,15 @@
 package profile
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestErrMaxSizeExceeded_Error(t *testing.T) {
@@ -19,3 +25,84 @@ func TestErrMaxSizeExceeded_Error(t *testing.T) {
 		)
 	}
 }
+
+func TestErrDemoProfile_Is(t *testing.T) {
+	if !errors.Is(ErrDemoProfile, ErrNoSuchProfile) {
+		t.Error("errors.Is(ErrDemoProfile, ErrNoSuchProfile) was false; want true")
+	}
+	if errors.Is(ErrNoSuchProfile, ErrDemoProfile) {
+		t.Error("errors.Is(ErrNoSuchProfile, ErrDemoProfile) was true; want false")
+	}
+	if errors.Is(ErrDemoProfile, ErrTooManyRequests) {
+		t.Error("errors.Is(ErrDemoProfile, ErrTooManyRequests) was true; want false")
+	}
+}
+
+var testErrorsIsInput = [...]struct {
+	err    error
+	target error
+	exp    bool
+}{
+	{err: ErrMaxSizeExceeded{12}, target: ErrMaxSizeExceeded{}, exp: true},
+	{err: ErrMaxSizeExceeded{12}, target: ErrMaxSizeExceeded{12}, exp: true},
+	{err: ErrMaxSizeExceeded{12}, target: ErrMaxSizeExceeded{13}, exp: false},
+	{err: ErrMaxSizeExceeded{12}, target: ErrTooManyRequests, exp: false},
+	{err: ErrPropertiesCooldown{time.Second}, target: ErrPropertiesCooldown{}, exp: true},
+	{err: ErrPropertiesCooldown{time.Second}, target: ErrPropertiesCooldown{time.Second}, exp: true},
+	{err: ErrPropertiesCooldown{time.Second}, target: ErrPropertiesCooldown{time.Minute}, exp: false},
+	{err: ErrHeadsFailed{map[string]error{"id": testError}}, target: ErrHeadsFailed{}, exp: true},
+	{err: ErrHeadsFailed{}, target: ErrMaxSizeExceeded{}, exp: false},
+	{err: &url.Error{Op: "Parse", URL: "dummy", Err: ErrInvalidTextureURL}, target: ErrInvalidTextureURL, exp: true},
+	{err: fmt.Errorf("wrapped: %w", ErrMaxSizeExceeded{12}), target: ErrMaxSizeExceeded{}, exp: true},
+}
+
+func TestErrors_Is(t *testing.T) {
+	for _, tc := range testErrorsIsInput {
+		if res := errors.Is(tc.err, tc.target); res != tc.exp {
+			t.Errorf("errors.Is(%#v, %#v) was %t; want %t", tc.err, tc.target, res, tc.exp)
+		}
+	}
+}
+
+func TestLoadManyErrorsIs(t *testing.T) {
+	_, err := LoadMany(context.Background(), make([]string, LoadManyMaxSize+1)...)
+	if !errors.Is(err, ErrMaxSizeExceeded{}) {
+		t.Errorf("errors.Is(%v, ErrMaxSizeExceeded{}) was false; want true", err)
+	}
+}
+
+func TestUnknownFormatErrorsIs(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	client.Transport = http.NewFileTransport(http.Dir("testdata"))
+	if _, err := Load(context.Background(), "unexpectedFormat"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("errors.Is(%v, ErrUnknownFormat) of Load error was false; want true", err)
+	}
+	err := ParseProfilesStream(strings.NewReader(`{}`), func(*Profile) error { return nil })
+	if !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("errors.Is(%v, ErrUnknownFormat) of ParseProfilesStream error was false; want true", err)
+	}
+}
+
+func TestHTTPStatus(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	for _, tc := range [...]struct {
+		transport http.RoundTripper
+		expStatus int
+		expOk     bool
+	}{
+		{transport: responseTransport{status: 503, body: ``}, expStatus: 503, expOk: true},
+		{transport: responseTransport{status: 200, body: `{"error":"IllegalArgumentException"}`}, expStatus: 200, expOk: true},
+		{transport: responseTransport{status: 429, body: `{"error":"TooManyRequestsException"}`}, expStatus: 0, expOk: false},
+		{transport: errorTransport{testError}, expStatus: 0, expOk: false},
+	} {
+		client.Transport = tc.transport
+		_, err := LoadByID(context.Background(), "087cc153c3434ff7ac497de1569affa1")
+		if status, ok := HTTPStatus(err); status != tc.expStatus || ok != tc.expOk {
+			t.Errorf("HTTPStatus(%v) was %d, %t; want %d, %t", err, status, ok, tc.expStatus, tc.expOk)
+		}
+	}
+}
@@ -114,3 +114,18 @@ func ExampleLoadMany() {
 	// BreeSakana d9a5b542ce88442aaab38ec13e6c7773
 	// Nergalic   087cc153c3434ff7ac497de1569affa1
 }
+
+// The following example shows how to bound the time spent loading a profile.
+func ExampleWithTimeout() {
+	ctx, cancel := profile.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	p, err := profile.Load(ctx, "nergalic")
+	if err != nil {
+		log.Fatalf("Failed to load profile: %s", err)
+	}
+
+	fmt.Println(p.Name)
+	// output:
+	// Nergalic
+}
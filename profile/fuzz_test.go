@@ -0,0 +1,130 @@
+//go:build go1.18
+// +build go1.18
+
+package profile
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+// The fuzz targets below feed arbitrary response bodies to the loaders and
+// check that malformed Mojang responses are reported as errors rather than
+// crashing the process.
+
+func FuzzLoad(f *testing.F) {
+	addSeeds(f,
+		"testdata/users/profiles/minecraft/nergalic",
+		"testdata/users/profiles/minecraft/demoAccount",
+		"testdata/users/profiles/minecraft/unexpectedFormat",
+	)
+	f.Fuzz(func(t *testing.T, body []byte) {
+		withBody(body, func() {
+			p, err := Load(context.Background(), "nergalic")
+			checkResult(t, p != nil, err)
+		})
+	})
+}
+
+func FuzzLoadNameHistory(f *testing.F) {
+	addSeeds(f,
+		"testdata/user/profiles/087cc153c3434ff7ac497de1569affa1/names",
+		"testdata/user/profiles/unexpectedFormat/names",
+	)
+	f.Fuzz(func(t *testing.T, body []byte) {
+		withBody(body, func() {
+			p := Profile{ID: "087cc153c3434ff7ac497de1569affa1"}
+			_, err := p.LoadNameHistory(context.Background(), true)
+			checkResult(t, true, err)
+		})
+	})
+}
+
+func FuzzLoadProperties(f *testing.F) {
+	addSeeds(f,
+		"testdata/session/minecraft/profile/087cc153c3434ff7ac497de1569affa1",
+		"testdata/session/minecraft/profile/badProperties",
+		"testdata/session/minecraft/profile/fictiveDemo",
+		"testdata/session/minecraft/profile/noSkinAndBadUUID",
+	)
+	f.Fuzz(func(t *testing.T, body []byte) {
+		withBody(body, func() {
+			p := Profile{ID: "087cc153c3434ff7ac497de1569affa1"}
+			ps, err := p.LoadProperties(context.Background(), true)
+			checkResult(t, ps != nil, err)
+		})
+	})
+}
+
+func FuzzLoadMany(f *testing.F) {
+	addSeeds(f,
+		"testdata/LoadMany/success/profiles/minecraft",
+		"testdata/LoadMany/unexpectedFormat/profiles/minecraft",
+	)
+	f.Fuzz(func(t *testing.T, body []byte) {
+		withBody(body, func() {
+			ps, err := LoadMany(context.Background(), "nergalic", "AxeLaw")
+			checkResult(t, true, err)
+			for _, p := range ps {
+				if p == nil {
+					t.Errorf("LoadMany(ctx, ...) returned nil profile in %v", ps)
+				}
+			}
+		})
+	})
+}
+
+/***************
+*  FUZZ UTILS  *
+***************/
+
+func addSeeds(f *testing.F, files ...string) {
+	for _, file := range files {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(b)
+	}
+}
+
+// withBody runs fn while every request is answered by a 200 OK response with
+// the given body.
+func withBody(body []byte, fn func()) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	client.Transport = bodyTransport(body)
+	fn()
+}
+
+// checkResult fails t unless err is a typed error that this package documents
+// to return, or unless err is nil and ok is true.
+func checkResult(t *testing.T, ok bool, err error) {
+	switch err.(type) {
+	case nil:
+		if !ok {
+			t.Error("no result returned, but error was nil")
+		}
+	case *url.Error:
+	default:
+		if err != ErrNoSuchProfile && err != ErrDemoProfile && err != ErrTooManyRequests {
+			t.Errorf("unexpected error: %#v", err)
+		}
+	}
+}
+
+type bodyTransport []byte
+
+func (bt bodyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: 200,
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(bytes.NewReader(bt)),
+		Request:    req,
+	}, nil
+}
@@ -0,0 +1,108 @@
+package profile
+
+import (
+	"context"
+	"image"
+	"image/png"
+	"sync"
+
+	"github.com/PhilipBorgesen/minecraft/internal"
+	"github.com/PhilipBorgesen/minecraft/skin"
+)
+
+// DownloadHeads downloads the skins of the profiles identified by ids and
+// renders the face of each as a size x size pixels avatar, as described for
+// skin.Head. Profiles without a custom skin get the head of their default skin.
+// At most concurrency profiles are processed at the same time. ctx must be
+// non-nil. DownloadHeads panics if size <= 0 or concurrency <= 0. Connections
+// are reused between profiles; if concurrency exceeds
+// DefaultMaxIdleConnsPerHost, consider raising it using SetMaxIdleConnsPerHost.
+//
+// The heads are returned indexed by the IDs of ids. If the heads of some
+// profiles can't be downloaded, the heads of the others are returned along
+// with an ErrHeadsFailed error holding the error of each failed profile. If
+// ctx is done before all heads are downloaded, the heads downloaded so far are
+// returned along with ctx.Err().
+//
+// NB! DownloadHeads loads the properties of each profile, which for each
+// profile may only be requested once per minute. See Profile.LoadProperties.
+func DownloadHeads(ctx context.Context, ids []string, size, concurrency int) (map[string]image.Image, error) {
+	internal.CheckContext(ctx, "profile", "DownloadHeads")
+	if size <= 0 {
+		panic("minecraft/profile: non-positive size passed to DownloadHeads")
+	}
+	if concurrency <= 0 {
+		panic("minecraft/profile: non-positive concurrency passed to DownloadHeads")
+	}
+
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		heads = make(map[string]image.Image, len(ids))
+		errs  = make(map[string]error)
+	)
+
+	work := make(chan string)
+	for i := 0; i < concurrency && i < len(ids); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range work {
+				h, err := downloadHead(ctx, id, size)
+				mu.Lock()
+				if err != nil {
+					errs[id] = err
+				} else {
+					heads[id] = h
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	seen := make(map[string]bool, len(ids))
+feed:
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		select {
+		case work <- id:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return heads, err
+	}
+	if len(errs) != 0 {
+		return heads, ErrHeadsFailed{errs}
+	}
+	return heads, nil
+}
+
+// downloadHead downloads the skin of the profile with the given ID and renders
+// its head.
+func downloadHead(ctx context.Context, id string, size int) (image.Image, error) {
+	p := &Profile{ID: id}
+	props, err := p.LoadProperties(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := props.SkinReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	img, err := png.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	return skin.Head(img, size)
+}
@@ -0,0 +1,136 @@
+package profile
+
+import (
+	"context"
+	"image"
+	"image/png"
+	"net/http"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/PhilipBorgesen/minecraft/skin"
+)
+
+func TestDownloadHeads(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	client.Transport = http.NewFileTransport(http.Dir("testdata"))
+
+	const (
+		nergalic = "087cc153c3434ff7ac497de1569affa1"
+		steve    = "ec561538f3fd461daff5086b22154bce" // Default skin
+	)
+	ids := []string{nergalic, steve, "fictiveDemo", "badProperties", "nonexisting", nergalic}
+	heads, err := DownloadHeads(context.Background(), ids, 16, 2)
+
+	hf, ok := err.(ErrHeadsFailed)
+	if !ok {
+		t.Fatalf("DownloadHeads(ctx, %q, 16, 2) returned error %s; want ErrHeadsFailed", ids, p(err))
+	}
+	if failed, exp := sortedKeys(hf.Errors), []string{"badProperties", "fictiveDemo", "nonexisting"}; !reflect.DeepEqual(failed, exp) {
+		t.Errorf("DownloadHeads(ctx, %q, 16, 2) failed for %q; want %q", ids, failed, exp)
+	}
+
+	var loaded []string
+	for id := range heads {
+		loaded = append(loaded, id)
+	}
+	sort.Strings(loaded)
+	if exp := []string{nergalic, steve}; !reflect.DeepEqual(loaded, exp) {
+		t.Fatalf("DownloadHeads(ctx, %q, 16, 2) returned heads for %q; want %q", ids, loaded, exp)
+	}
+	for id, h := range heads {
+		if b, exp := h.Bounds(), image.Rect(0, 0, 16, 16); b != exp {
+			t.Errorf("head of %s has bounds %s; want %s", id, b, exp)
+		}
+	}
+
+	exp := expectedHead(t, "testdata/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e", 16)
+	if !reflect.DeepEqual(heads[nergalic], exp) {
+		t.Errorf("head of %s doesn't match the face of the profile's skin", nergalic)
+	}
+	exp = expectedHead(t, "testdata/SkinTemplates/steve.png", 16)
+	if !reflect.DeepEqual(heads[steve], exp) {
+		t.Errorf("head of %s doesn't match the face of the default skin", steve)
+	}
+}
+
+func TestDownloadHeadsCancelled(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	client.Transport = http.NewFileTransport(http.Dir("testdata"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ids := []string{"087cc153c3434ff7ac497de1569affa1", "ec561538f3fd461daff5086b22154bce"}
+	heads, err := DownloadHeads(ctx, ids, 8, 1)
+	if len(heads) != 0 || err != context.Canceled {
+		t.Errorf("DownloadHeads(ctx, %q, 8, 1) with cancelled ctx was %v, %s; want no heads, %s", ids, heads, p(err), context.Canceled)
+	}
+}
+
+var testDownloadHeadsPanicInput = [...]struct {
+	size, concurrency int
+	expPanic          string
+}{
+	{size: 0, concurrency: 1, expPanic: "minecraft/profile: non-positive size passed to DownloadHeads"},
+	{size: 8, concurrency: 0, expPanic: "minecraft/profile: non-positive concurrency passed to DownloadHeads"},
+}
+
+func TestDownloadHeadsPanic(t *testing.T) {
+	for _, tc := range testDownloadHeadsPanicInput {
+		func() {
+			defer func() {
+				if r := recover(); r != tc.expPanic {
+					t.Errorf("DownloadHeads(ctx, nil, %d, %d) panicked with %#v; want %q", tc.size, tc.concurrency, r, tc.expPanic)
+				}
+			}()
+			DownloadHeads(context.Background(), nil, tc.size, tc.concurrency)
+		}()
+	}
+}
+
+func TestErrHeadsFailed_Error(t *testing.T) {
+	err := ErrHeadsFailed{map[string]error{"a": ErrNoSuchProfile, "b": ErrTooManyRequests}}
+	exp := "minecraft/profile: failed to download 2 heads"
+	if s := err.Error(); s != exp {
+		t.Errorf("%#v.Error() = %q; want %q", err, s, exp)
+	}
+}
+
+/*************
+* TEST UTILS *
+*************/
+
+// expectedHead renders the head of the skin stored in the file named path.
+func expectedHead(t *testing.T, path string, size int) image.Image {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := skin.Head(img, size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func sortedKeys(m map[string]error) []string {
+	ks := make([]string, 0, len(m))
+	for k := range m {
+		ks = append(ks, k)
+	}
+	sort.Strings(ks)
+	return ks
+}
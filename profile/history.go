@@ -0,0 +1,101 @@
+package profile
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// NameHistory is a profile's past usernames incl. when each username stopped
+// being used. Its last username is first, its original username is last. The
+// profile's current username is not part of its name history; see Profile.Name.
+type NameHistory []PastName
+
+// Latest returns the username the profile used last before its current
+// username, i.e. h[0]. ok is false if h holds no past usernames, in which case
+// the profile still uses the username it was registered with.
+func (h NameHistory) Latest() (n PastName, ok bool) {
+	if len(h) == 0 {
+		return PastName{}, false
+	}
+	return h[0], true
+}
+
+// Original returns the username the profile was registered with, i.e. the
+// last entry of h if it's marked Original. ok is false if h holds no entry
+// marked Original, in which case the original username is the profile's current
+// username if h is empty, and unknown otherwise.
+func (h NameHistory) Original() (n PastName, ok bool) {
+	if len(h) == 0 || !h[len(h)-1].Original {
+		return PastName{}, false
+	}
+	return h[len(h)-1], true
+}
+
+// At returns the past username which the profile used at the instant of time t.
+// ok is false if t is after h[0].Until, in which case the profile used the
+// username following h[0], or if it isn't known which username was used at t.
+// If h is the name history of a profile p, the username used at t is p.Name if
+// t is neither before h[0].Until nor p's creation.
+func (h NameHistory) At(t time.Time) (name string, ok bool) {
+	for i := len(h) - 1; i >= 0; i-- {
+		if t.Before(h[i].Until) {
+			if i == len(h)-1 && !h[i].Original {
+				return "", false // The username used before h[i] is unknown
+			}
+			return h[i].Name, true
+		}
+	}
+	return "", false
+}
+
+// String returns the past usernames of h in brackets, last first, separated by
+// spaces like fmt formats a []PastName, e.g.
+//	"[Nergalic GeneralSezuan]"
+func (h NameHistory) String() string {
+	names := make([]string, len(h))
+	for i, n := range h {
+		names[i] = n.Name
+	}
+	return "[" + strings.Join(names, " ") + "]"
+}
+
+// pastNameJSON is the JSON representation of a PastName.
+type pastNameJSON struct {
+	Name     string    `json:"name"`
+	Until    time.Time `json:"until"`
+	Original bool      `json:"original,omitempty"`
+}
+
+// MarshalJSON encodes h as a JSON array of past usernames, last first, e.g.
+//	[{"name":"GeneralSezuan","until":"2015-02-04T11:01:45Z","original":true}]
+// A nil history is encoded as null, to keep it apart from a loaded history
+// without past usernames, which is encoded as [].
+func (h NameHistory) MarshalJSON() ([]byte, error) {
+	if h == nil {
+		return []byte("null"), nil
+	}
+	js := make([]pastNameJSON, len(h))
+	for i, n := range h {
+		js[i] = pastNameJSON{Name: n.Name, Until: n.Until, Original: n.Original}
+	}
+	return json.Marshal(js)
+}
+
+// UnmarshalJSON decodes a name history encoded by MarshalJSON into h.
+func (h *NameHistory) UnmarshalJSON(bs []byte) error {
+	var js []pastNameJSON
+	if err := json.Unmarshal(bs, &js); err != nil {
+		return err
+	}
+	if js == nil {
+		*h = nil
+		return nil
+	}
+	hist := make(NameHistory, len(js))
+	for i, n := range js {
+		hist[i] = PastName{Name: n.Name, Until: n.Until, Original: n.Original}
+	}
+	*h = hist
+	return nil
+}
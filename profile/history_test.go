@@ -0,0 +1,105 @@
+package profile
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+var testHistory = NameHistory{
+	{Name: "Third", Until: time.Date(2017, 3, 1, 0, 0, 0, 0, time.UTC)},
+	{Name: "Second", Until: time.Date(2016, 2, 1, 0, 0, 0, 0, time.UTC)},
+	{Name: "First", Until: time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC), Original: true},
+}
+
+var testNameHistoryAtInput = [...]struct {
+	hist    NameHistory
+	t       time.Time
+	expName string
+	expOK   bool
+}{
+	{hist: nil, t: time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)},
+	{hist: testHistory, t: time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC), expName: "First", expOK: true},
+	{hist: testHistory, t: time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC), expName: "Second", expOK: true},
+	{hist: testHistory, t: time.Date(2016, 6, 1, 0, 0, 0, 0, time.UTC), expName: "Third", expOK: true},
+	{hist: testHistory, t: time.Date(2017, 3, 1, 0, 0, 0, 0, time.UTC)}, // Current username
+	{hist: testHistory[:2], t: time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)},
+	{hist: testHistory[:2], t: time.Date(2016, 6, 1, 0, 0, 0, 0, time.UTC), expName: "Third", expOK: true},
+}
+
+func TestNameHistory_At(t *testing.T) {
+	for _, tc := range testNameHistoryAtInput {
+		name, ok := tc.hist.At(tc.t)
+		if name != tc.expName || ok != tc.expOK {
+			t.Errorf(
+				"%s.At(%s) was %q, %t; want %q, %t",
+				tc.hist, tc.t, name, ok, tc.expName, tc.expOK,
+			)
+		}
+	}
+}
+
+func TestNameHistory_LatestOriginal(t *testing.T) {
+	if n, ok := testHistory.Latest(); !ok || !n.Equal(testHistory[0]) {
+		t.Errorf("%s.Latest() was %v, %t; want %v, true", testHistory, n, ok, testHistory[0])
+	}
+	if n, ok := testHistory.Original(); !ok || !n.Equal(testHistory[2]) {
+		t.Errorf("%s.Original() was %v, %t; want %v, true", testHistory, n, ok, testHistory[2])
+	}
+	if n, ok := testHistory[:2].Original(); ok {
+		t.Errorf("%s.Original() was %v, true; want false", testHistory[:2], n)
+	}
+	if n, ok := emptyHist.Latest(); ok {
+		t.Errorf("%s.Latest() was %v, true; want false", emptyHist, n)
+	}
+	if n, ok := emptyHist.Original(); ok {
+		t.Errorf("%s.Original() was %v, true; want false", emptyHist, n)
+	}
+}
+
+func TestNameHistory_String(t *testing.T) {
+	const exp = "[Third Second First]"
+	if s := testHistory.String(); s != exp {
+		t.Errorf("NameHistory.String() was %q; want %q", s, exp)
+	}
+	if s := emptyHist.String(); s != "[]" {
+		t.Errorf("NameHistory{}.String() was %q; want \"[]\"", s)
+	}
+}
+
+func TestNameHistory_JSON(t *testing.T) {
+	for _, hist := range []NameHistory{nil, emptyHist, testHistory} {
+		bs, err := json.Marshal(hist)
+		if err != nil {
+			t.Errorf("json.Marshal(%#v) failed: %s", hist, err)
+			continue
+		}
+		var res NameHistory
+		if err = json.Unmarshal(bs, &res); err != nil {
+			t.Errorf("json.Unmarshal(%s, &hist) failed: %s", bs, err)
+			continue
+		}
+		if !equalHistories(res, hist) {
+			t.Errorf("NameHistory JSON round trip of %#v produced %#v", hist, res)
+		}
+	}
+
+	const exp = `[{"name":"Second","until":"2016-02-01T00:00:00Z"},{"name":"First","until":"2015-01-01T00:00:00Z","original":true}]`
+	if bs, err := json.Marshal(testHistory[1:]); string(bs) != exp || err != nil {
+		t.Errorf("json.Marshal(%#v) was %s, %v; want %s, <nil>", testHistory[1:], bs, err, exp)
+	}
+}
+
+/*** TEST UTILS ***/
+
+func equalHistories(a, b NameHistory) bool {
+	if (a == nil) != (b == nil) || len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,103 @@
+package profile
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// profileDataJSON is the JSON representation of a Profile, as encoded by
+// Profile.MarshalJSON. It isn't the representation used by Mojang.
+type profileDataJSON struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	NameHistory NameHistory     `json:"nameHistory"`
+	Properties  *propertiesJSON `json:"properties,omitempty"`
+}
+
+// propertiesJSON is the JSON representation of Properties.
+type propertiesJSON struct {
+	SkinURL   string            `json:"skinURL,omitempty"`
+	CapeURL   string            `json:"capeURL,omitempty"`
+	Model     string            `json:"model"`
+	Timestamp time.Time         `json:"timestamp"`
+	Raw       []rawPropertyJSON `json:"raw,omitempty"`
+}
+
+// rawPropertyJSON is the JSON representation of a RawProperty.
+type rawPropertyJSON struct {
+	Name      string `json:"name"`
+	Value     string `json:"value"`
+	Signature string `json:"signature,omitempty"`
+}
+
+// MarshalJSON encodes p as a JSON object holding everything known about the
+// profile, e.g. to persist it in a database or cache, such that UnmarshalJSON
+// may reconstruct it. For example:
+//	{"id":"5ec1954793a24d1d8c8d8f4fd4d0c36b","name":"Nergalic",
+//	 "nameHistory":[{"name":"GeneralSezuan","until":"2015-02-04T11:01:45Z","original":true}],
+//	 "properties":{"skinURL":"http://textures.minecraft.net/texture/...","model":"Steve",
+//	  "timestamp":"2017-05-26T12:00:00Z","raw":[{"name":"textures","value":"..."}]}}
+// A name history which hasn't been loaded is encoded as null, to keep it apart
+// from a name history without past usernames, e.g. of a legacy profile, which
+// is encoded as []. Properties are left out if they haven't been loaded. If p's
+// properties have a Model not declared by this package, ErrUnknownModel is
+// returned.
+func (p *Profile) MarshalJSON() ([]byte, error) {
+	js := profileDataJSON{ID: p.ID, Name: p.Name, NameHistory: p.NameHistory}
+	if ps := p.Properties; ps != nil {
+		if ps.Model != Steve && ps.Model != Alex {
+			return nil, ErrUnknownModel
+		}
+		js.Properties = &propertiesJSON{
+			SkinURL:   ps.SkinURL,
+			CapeURL:   ps.CapeURL,
+			Model:     ps.Model.String(),
+			Timestamp: ps.Timestamp,
+		}
+		for _, r := range ps.raw {
+			js.Properties.Raw = append(js.Properties.Raw, rawPropertyJSON(r))
+		}
+	}
+	return json.Marshal(js)
+}
+
+// UnmarshalJSON decodes a profile encoded by MarshalJSON into p, replacing
+// everything p holds. The raw properties are restored as well, so
+// p.Properties.Raw returns the properties the profile was loaded from. If the
+// JSON data isn't structured as encoded by MarshalJSON, ErrUnknownFormat is
+// returned.
+func (p *Profile) UnmarshalJSON(bs []byte) error {
+	var js profileDataJSON
+	if err := json.Unmarshal(bs, &js); err != nil {
+		if _, ok := err.(*json.UnmarshalTypeError); ok {
+			return ErrUnknownFormat
+		}
+		return err
+	}
+
+	res := Profile{ID: js.ID, Name: js.Name, NameHistory: js.NameHistory}
+	if res.NameHistory != nil && len(res.NameHistory) == 0 {
+		res.NameHistory = emptyHist
+	}
+	if pj := js.Properties; pj != nil {
+		ps := &Properties{
+			SkinURL:   pj.SkinURL,
+			CapeURL:   pj.CapeURL,
+			Timestamp: pj.Timestamp,
+		}
+		switch pj.Model {
+		case Steve.String():
+			ps.Model = Steve
+		case Alex.String():
+			ps.Model = Alex
+		default:
+			return ErrUnknownFormat
+		}
+		for _, r := range pj.Raw {
+			ps.raw = append(ps.raw, RawProperty(r))
+		}
+		res.Properties = ps
+	}
+	*p = res
+	return nil
+}
@@ -0,0 +1,89 @@
+package profile
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testProfileJSONInput = [...]*Profile{
+	{ID: "5ec1954793a24d1d8c8d8f4fd4d0c36b", Name: "Nergalic"},
+	{ID: "5ec1954793a24d1d8c8d8f4fd4d0c36b", Name: "Nergalic", NameHistory: emptyHist},
+	{ID: "5ec1954793a24d1d8c8d8f4fd4d0c36b", Name: "Nergalic", NameHistory: testHistory},
+	{
+		ID:   "5ec1954793a24d1d8c8d8f4fd4d0c36b",
+		Name: "Nergalic",
+		Properties: &Properties{
+			SkinURL:   alexSkinURL,
+			CapeURL:   "http://textures.minecraft.net/texture/3f688e0e699b3d9fe448b5bb50a3a288f9c589762b3dae8308842122dcb81",
+			Model:     Alex,
+			Timestamp: time.Date(2017, 5, 26, 12, 0, 0, 0, time.UTC),
+			raw: []RawProperty{
+				{Name: "textures", Value: "eyJ0aW1lc3RhbXAiOjB9", Signature: "c2lnbmF0dXJl"},
+				{Name: "unknown", Value: "e30="},
+			},
+		},
+	},
+	{ID: "5ec1954793a24d1d8c8d8f4fd4d0c36b", Name: "Nergalic", Properties: &Properties{}},
+}
+
+func TestProfileJSON(t *testing.T) {
+	for _, p := range testProfileJSONInput {
+		bs, err := json.Marshal(p)
+		if err != nil {
+			t.Errorf("json.Marshal(%s) failed: %s", p, err)
+			continue
+		}
+		var res Profile
+		if err = json.Unmarshal(bs, &res); err != nil {
+			t.Errorf("json.Unmarshal(%s, &p) failed: %s", bs, err)
+			continue
+		}
+		if res.ID != p.ID || res.Name != p.Name || !equalHistories(res.NameHistory, p.NameHistory) {
+			t.Errorf("Profile JSON round trip of %s produced %s with name history %#v; want %#v", p, &res, res.NameHistory, p.NameHistory)
+		}
+		if !reflect.DeepEqual(res.Properties, p.Properties) {
+			t.Errorf("Profile JSON round trip of %s produced properties:\n"+
+				"      %#v\n"+
+				"want: %#v",
+				p, res.Properties, p.Properties)
+		}
+	}
+}
+
+func TestProfileJSONNameHistory(t *testing.T) {
+	const exp = `{"id":"5ec1954793a24d1d8c8d8f4fd4d0c36b","name":"Nergalic","nameHistory":[]}`
+	p := &Profile{ID: "5ec1954793a24d1d8c8d8f4fd4d0c36b", Name: "Nergalic", NameHistory: emptyHist}
+	if bs, err := json.Marshal(p); string(bs) != exp || err != nil {
+		t.Errorf("json.Marshal(%s) of legacy profile was %s, %v; want %s, <nil>", p, bs, err, exp)
+	}
+
+	res := &Profile{NameHistory: testHistory, Properties: &Properties{}}
+	if err := json.Unmarshal([]byte(`{"id":"5ec1954793a24d1d8c8d8f4fd4d0c36b","name":"Nergalic","nameHistory":null}`), res); err != nil || res.NameHistory != nil || res.Properties != nil {
+		t.Errorf("json.Unmarshal of profile without name history kept name history %#v and properties %v, %v; want nil, nil, <nil>", res.NameHistory, res.Properties, err)
+	}
+}
+
+var testProfileJSONErrorInput = [...]struct {
+	data   string
+	expErr error
+}{
+	{data: `{"id":1,"name":"Nergalic"}`, expErr: ErrUnknownFormat},
+	{data: `{"id":"5ec1954793a24d1d8c8d8f4fd4d0c36b","name":"Nergalic","properties":{"model":"???"}}`, expErr: ErrUnknownFormat},
+	{data: `[]`, expErr: ErrUnknownFormat},
+}
+
+func TestProfileJSONError(t *testing.T) {
+	for _, tc := range testProfileJSONErrorInput {
+		var p Profile
+		if err := json.Unmarshal([]byte(tc.data), &p); err != tc.expErr {
+			t.Errorf("json.Unmarshal(%s, &p) returned error %v; want %s", tc.data, err, tc.expErr)
+		}
+	}
+
+	p := &Profile{Properties: &Properties{Model: Model(42)}}
+	if _, err := json.Marshal(p); err == nil {
+		t.Errorf("json.Marshal(%s) with unknown model succeeded; want error", p)
+	}
+}
@@ -2,12 +2,16 @@ package profile
 
 import (
 	"context"
-	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/PhilipBorgesen/minecraft/internal"
+	"github.com/PhilipBorgesen/minecraft/uuid"
 )
 
 // LoadManyMaxSize is the maximum number of profiles which may be requested at
@@ -15,63 +19,181 @@ import (
 // ErrMaxSizeExceeded error.
 const LoadManyMaxSize int = 100
 
+// WithTimeout returns a copy of parent which is cancelled after d has elapsed,
+// as context.WithTimeout does. It is a convenience to set a deadline for a
+// single request without importing package context:
+//	ctx, cancel := profile.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	p, err := profile.Load(ctx, "nergalic")
+//
+// Callers must still call cancel as soon as the request has completed to
+// release the resources associated with the returned context.
+func WithTimeout(parent context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(parent, d)
+}
+
 // Load fetches the profile currently associated with username. ctx must be
 // non-nil. If no profile currently is associated with username, Load returns
-// ErrNoSuchProfile. If an error is returned, p will be nil.
+// ErrNoSuchProfile. If username is associated with a demo profile, Load returns
+// ErrDemoProfile. If an error is returned, p will be nil.
 func Load(ctx context.Context, username string) (p *Profile, err error) {
+	internal.CheckContext(ctx, "profile", "Load")
+
 	if username == "" {
 		return nil, ErrNoSuchProfile
 	}
-	endpoint := fmt.Sprintf(loadURL, username)
+	endpoint := profileURL(loadPath, username)
 	return loadByName(ctx, endpoint)
 }
 
+// LoadRaw is like Load, but also returns the raw JSON response body Mojang
+// returned for the profile, e.g. to persist the exact upstream payload and
+// parse it anew once this package supports more fields. ctx must be non-nil.
+// If an error is returned, p and raw will be nil.
+func LoadRaw(ctx context.Context, username string) (p *Profile, raw []byte, err error) {
+	internal.CheckContext(ctx, "profile", "LoadRaw")
+
+	if username == "" {
+		return nil, nil, ErrNoSuchProfile
+	}
+	endpoint := profileURL(loadPath, username)
+	return loadByNameRaw(ctx, endpoint)
+}
+
 // LoadAtTime fetches the profile associated with username at the specified
 // instant of time. ctx must be non-nil. If no profile was associated with
 // username at the specified instant of time, LoadAtTime returns
-// ErrNoSuchProfile. If an error is returned, p will be nil.
+// ErrNoSuchProfile. If username was associated with a demo profile, LoadAtTime
+// returns ErrDemoProfile. If an error is returned, p will be nil.
+//
+// Mojang resolves usernames at the granularity of seconds, so LoadAtTime
+// queries the Unix timestamp AtTimestamp(t). Errors reporting server
+// communication failures, i.e. *url.Error, include the timestamp in the
+// queried URL.
 func LoadAtTime(ctx context.Context, username string, t time.Time) (p *Profile, err error) {
+	internal.CheckContext(ctx, "profile", "LoadAtTime")
+
 	if username == "" {
 		return nil, ErrNoSuchProfile
 	}
-	endpoint := fmt.Sprintf(loadAtTimeURL, username, t.Unix())
+	endpoint := profileURL(loadAtTimePath, username, AtTimestamp(t))
 	return loadByName(ctx, endpoint)
 }
 
+// AtTimestamp returns the Unix timestamp which LoadAtTime queries Mojang's
+// servers with for the instant of time t, i.e. t as seconds since the Unix
+// epoch, with fractions of seconds discarded. Use AtTimestamp to log or debug
+// the exact instant a username was resolved at, e.g. around the time the
+// username was changed.
+func AtTimestamp(t time.Time) int64 {
+	return t.Unix()
+}
+
+// LoadAtTimeString is like LoadAtTime, but takes the instant of time as a
+// string, e.g. as received by a command-line tool or web API. ctx must be
+// non-nil. The following formats are accepted:
+//	1495800000                // Seconds since the Unix epoch
+//	2017-05-26T12:00:00Z      // RFC 3339, UTC
+//	2017-05-26T14:00:00+02:00 // RFC 3339, same instant as above
+// RFC 3339 times must state their offset from UTC, which only serves to locate
+// the instant of time; the time zone doesn't otherwise affect the lookup.
+// Since Mojang resolves usernames at the granularity of seconds, fractional
+// seconds are truncated. If tm is in neither format, LoadAtTimeString returns
+// ErrInvalidTime without contacting Mojang's servers.
+func LoadAtTimeString(ctx context.Context, username, tm string) (p *Profile, err error) {
+	internal.CheckContext(ctx, "profile", "LoadAtTimeString")
+
+	t, err := parseTimeString(tm)
+	if err != nil {
+		return nil, err
+	}
+	return LoadAtTime(ctx, username, t)
+}
+
+// parseTimeString parses s as described for LoadAtTimeString.
+func parseTimeString(s string) (time.Time, error) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(n, 0), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, ErrInvalidTime
+	}
+	return t, nil
+}
+
 // Common implementation used by Load and LoadAtTime.
 func loadByName(ctx context.Context, endpoint string) (p *Profile, err error) {
-	js, err := internal.FetchJSON(ctx, client, endpoint)
+	p, _, err = loadByNameRaw(ctx, endpoint)
+	return p, err
+}
+
+func loadByNameRaw(ctx context.Context, endpoint string) (p *Profile, raw []byte, err error) {
+	js, raw, err := internal.FetchRawJSON(ctx, clientFor(ctx, endpoint), endpoint)
 	if err != nil {
-		return nil, transformError(err)
+		if isNoProfileError(err) {
+			return nil, nil, ErrNoSuchProfile
+		}
+		return nil, nil, transformError(err)
+	}
+	if isNoProfileResponse(js) {
+		return nil, nil, ErrNoSuchProfile
 	}
 
 	defer func() { // If JSON data isn't structured as expected
 		if r := recover(); r != nil {
-			p = nil
-			err = &url.Error{Op: "Parse", URL: endpoint, Err: internal.ErrUnknownFormat}
+			p, raw = nil, nil
+			err = &url.Error{Op: "Parse", URL: endpoint, Err: ErrUnknownFormat}
 		}
 	}()
 
 	p = &Profile{}
 	if !fillProfile(p, js.(map[string]interface{})) {
-		return nil, ErrNoSuchProfile
+		return nil, nil, ErrDemoProfile
 	}
 
-	return p, nil
+	return p, raw, nil
 }
 
 // LoadByID fetches the profile identified by id. ctx must be non-nil. If no
 // profile is identified by id, LoadByID returns ErrNoSuchProfile. If an error
 // is returned, p will be nil.
 func LoadByID(ctx context.Context, id string) (p *Profile, err error) {
+	internal.CheckContext(ctx, "profile", "LoadByID")
+
 	return LoadWithNameHistory(ctx, id)
 }
 
+// Resolve fetches the profile identified by input, which may be either a
+// profile ID or a username, e.g. as given by the user of a CLI tool or chat
+// bot. ctx must be non-nil.
+//
+// An input of 32 hexadecimal digits, or 36 characters in the hyphenated
+// 8-4-4-4-12 form, is a profile ID and is loaded as by LoadByID. Since
+// usernames are at most 16 characters long, they can't be mistaken for IDs.
+// Any other input is loaded as a username by Load, unless it doesn't adhere to
+// Mojang's username rules (3-16 characters, only letters, digits and
+// underscores), in which case ErrInvalidUsername is returned without contacting
+// Mojang's servers. If an error is returned, p will be nil.
+func Resolve(ctx context.Context, input string) (p *Profile, err error) {
+	internal.CheckContext(ctx, "profile", "Resolve")
+
+	if id, err := uuid.ToUndashed(input); err == nil {
+		return LoadByID(ctx, id)
+	}
+	if !isValidUsername(input) {
+		return nil, ErrInvalidUsername
+	}
+	return Load(ctx, input)
+}
+
 // LoadWithNameHistory fetches the profile identified by id, incl. its name
 // history. ctx must be non-nil. If no profile is identified by id,
 // LoadWithNameHistory returns ErrNoSuchProfile. If an error is returned,
 // p will be nil.
 func LoadWithNameHistory(ctx context.Context, id string) (p *Profile, err error) {
+	internal.CheckContext(ctx, "profile", "LoadWithNameHistory")
+
 	if id == "" {
 		return nil, ErrNoSuchProfile
 	}
@@ -85,12 +207,15 @@ func LoadWithNameHistory(ctx context.Context, id string) (p *Profile, err error)
 
 // LoadWithProperties fetches the profile identified by id, incl. its
 // properties. ctx must be non-nil. If no profile is identified by id,
-// LoadWithProperties returns ErrNoSuchProfile. If an error is returned,
-// p will be nil.
+// LoadWithProperties returns ErrNoSuchProfile. If id identifies a demo profile,
+// LoadWithProperties returns ErrDemoProfile. If an error is returned, p will be
+// nil.
 //
 // NB! For each profile, profile properties may only be requested once per
-// minute.
+// minute. Use TrackPropertiesCooldown to avoid making requests which would fail.
 func LoadWithProperties(ctx context.Context, id string) (p *Profile, err error) {
+	internal.CheckContext(ctx, "profile", "LoadWithProperties")
+
 	if id == "" {
 		return nil, ErrNoSuchProfile
 	}
@@ -102,6 +227,30 @@ func LoadWithProperties(ctx context.Context, id string) (p *Profile, err error)
 	return &pr, nil
 }
 
+// LoadWithPropertiesForce is like LoadWithProperties, but requests the
+// properties from Mojang even if TrackPropertiesCooldown is enabled and reports
+// that they were requested less than PropertiesCooldown ago, e.g. to read a
+// skin which was changed just now. ctx must be non-nil. The request is still
+// recorded by the cooldown tracker.
+//
+// NB! Mojang doesn't lift its rate limit because the cooldown is bypassed. If
+// the properties of the profile were requested within the last minute, the
+// request is likely to fail with ErrTooManyRequests. Prefer LoadWithProperties
+// unless the properties must be requested anew at once.
+func LoadWithPropertiesForce(ctx context.Context, id string) (p *Profile, err error) {
+	internal.CheckContext(ctx, "profile", "LoadWithPropertiesForce")
+
+	if id == "" {
+		return nil, ErrNoSuchProfile
+	}
+	pr := Profile{ID: id}
+	_, err = pr.loadProperties(ctx, true, false)
+	if err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
 // LoadMany fetches multiple profiles by their currently associated usernames.
 // Usernames associated with no profile are ignored and absent from the
 // returned results. Duplicate usernames are only returned once, and ps will be
@@ -111,32 +260,22 @@ func LoadWithProperties(ctx context.Context, id string) (p *Profile, err error)
 // If more are attempted loaded in the same operation, an ErrMaxSizeExceeded
 // error is returned.
 func LoadMany(ctx context.Context, usernames ...string) (ps []*Profile, err error) {
-	if len(usernames) > LoadManyMaxSize {
-		return nil, ErrMaxSizeExceeded{len(usernames)}
-	}
-
-	c := 0
-	var users [LoadManyMaxSize]string
-	for _, u := range usernames {
-		// Remove empty usernames. They are not accepted by the Mojang API.
-		if u != "" {
-			users[c] = u
-			c++
-		}
-	}
+	internal.CheckContext(ctx, "profile", "LoadMany")
 
-	if c == 0 {
-		return nil, nil // No need to request anything
+	users, err := loadManyUsernames(usernames)
+	if err != nil || len(users) == 0 {
+		return nil, err // No need to request anything
 	}
 
-	js, err := internal.ExchangeJSON(ctx, client, loadManyURL, users[:c])
+	endpoint := profileURL(loadManyPath)
+	js, err := internal.ExchangeJSON(ctx, clientFor(ctx, endpoint), endpoint, users)
 	if err != nil {
 		return nil, transformError(err)
 	}
 
 	defer func() { // If JSON data isn't structured as expected
 		if r := recover(); r != nil {
-			err = &url.Error{Op: "Parse", URL: loadManyURL, Err: internal.ErrUnknownFormat}
+			err = &url.Error{Op: "Parse", URL: endpoint, Err: ErrUnknownFormat}
 			ps = nil
 		}
 	}()
@@ -158,13 +297,265 @@ func LoadMany(ctx context.Context, usernames ...string) (ps []*Profile, err erro
 	return ps, nil
 }
 
-var client = &http.Client{}
+// LoadManyStrict is like LoadMany, but returns ErrInvalidUsername without
+// contacting Mojang's servers if any of usernames is empty, rather than
+// silently ignoring it. Use it when empty usernames indicate a bug, e.g. an
+// off-by-one error when slicing the input, which LoadMany would hide by just
+// returning fewer profiles. ctx must be non-nil. If an error is returned, ps
+// will be nil.
+//
+// Like LoadMany, LoadManyStrict returns ErrMaxSizeExceeded if more than
+// LoadManyMaxSize usernames are passed.
+func LoadManyStrict(ctx context.Context, usernames ...string) (ps []*Profile, err error) {
+	internal.CheckContext(ctx, "profile", "LoadManyStrict")
+
+	if len(usernames) > LoadManyMaxSize {
+		return nil, ErrMaxSizeExceeded{len(usernames)}
+	}
+	for _, u := range usernames {
+		if u == "" {
+			return nil, ErrInvalidUsername
+		}
+	}
+	return LoadMany(ctx, usernames...)
+}
+
+// LoadManyOrdered is like LoadMany, but returns the profiles aligned to the
+// order of usernames, e.g. to join them against a parallel slice of input
+// data. ctx must be non-nil. The i'th profile returned is the profile
+// associated with usernames[i], or nil if no profile is associated with it,
+// it's held by a demo profile, or it's empty. Usernames are matched
+// case-insensitively, and duplicate usernames share the same *Profile. If an
+// error occurs, ps will be nil.
+//
+// Like LoadMany, LoadManyOrdered returns ErrMaxSizeExceeded if more than
+// LoadManyMaxSize usernames are passed.
+func LoadManyOrdered(ctx context.Context, usernames ...string) (ps []*Profile, err error) {
+	internal.CheckContext(ctx, "profile", "LoadManyOrdered")
+
+	loaded, err := LoadMany(ctx, usernames...)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*Profile, len(loaded))
+	for _, p := range loaded {
+		byName[strings.ToLower(p.Name)] = p
+	}
+	ps = make([]*Profile, len(usernames))
+	for i, u := range usernames {
+		ps[i] = byName[strings.ToLower(u)]
+	}
+	return ps, nil
+}
+
+// loadManyUsernames returns the usernames LoadMany requests when passed
+// usernames.
+func loadManyUsernames(usernames []string) ([]string, error) {
+	if len(usernames) > LoadManyMaxSize {
+		return nil, ErrMaxSizeExceeded{len(usernames)}
+	}
+
+	users := make([]string, 0, len(usernames))
+	for _, u := range usernames {
+		// Remove empty usernames. They are not accepted by the Mojang API.
+		if u != "" {
+			users = append(users, u)
+		}
+	}
+	return users, nil
+}
+
+// DefaultMaxIdleConnsPerHost is the number of idle connections to each of
+// Mojang's hosts the package's default transport keeps open for reuse, unless
+// changed by SetMaxIdleConnsPerHost.
+const DefaultMaxIdleConnsPerHost = 16
+
+// transport is the package's default transport. It is configured like
+// http.DefaultTransport, except it keeps more idle connections to each host,
+// so bulk operations such as DownloadHeads reuse connections rather than
+// opening new ones for most requests. Since DialContext is set, HTTP/2 must be
+// enabled explicitly.
+var transport = &http.Transport{
+	Proxy:                 http.ProxyFromEnvironment,
+	DialContext:           dialer.DialContext,
+	ForceAttemptHTTP2:     true,
+	MaxIdleConns:          100,
+	MaxIdleConnsPerHost:   DefaultMaxIdleConnsPerHost,
+	IdleConnTimeout:       90 * time.Second,
+	TLSHandshakeTimeout:   10 * time.Second,
+	ExpectContinueTimeout: 1 * time.Second,
+}
+
+var client = &http.Client{Transport: transport}
+
+var dialer = &net.Dialer{
+	Timeout:   30 * time.Second,
+	KeepAlive: 30 * time.Second,
+}
+
+// IPVersion selects which version of the Internet Protocol to connect over.
+type IPVersion byte
+
+const (
+	AnyIP IPVersion = iota // Connect over IPv4 or IPv6, as available.
+	IPv4                   // Only connect over IPv4.
+	IPv6                   // Only connect over IPv6.
+)
+
+// SetIPVersion sets which version of the Internet Protocol the package's
+// default transport connects to Mojang's servers over, e.g. to work around
+// API or skin download timeouts which some dual-stack networks experience over
+// IPv6 only. By default, AnyIP is used. SetIPVersion panics if v isn't one of
+// AnyIP, IPv4 or IPv6. It doesn't affect transports chosen by a transport
+// selector, nor clients passed to WithClient; see SetTransportSelector.
+//
+// SetIPVersion must not be called concurrently with other functions of this
+// package. Set it once before loading any profiles.
+func SetIPVersion(v IPVersion) {
+	var network string
+	switch v {
+	case AnyIP:
+	case IPv4:
+		network = "tcp4"
+	case IPv6:
+		network = "tcp6"
+	default:
+		panic("minecraft/profile: unknown IP version passed to SetIPVersion")
+	}
+
+	if network == "" {
+		transport.DialContext = dialer.DialContext
+	} else {
+		transport.DialContext = func(ctx context.Context, _, addr string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, addr)
+		}
+	}
+	transport.CloseIdleConnections() // Don't reuse connections of another version
+}
+
+// SetMaxIdleConnsPerHost sets the number of idle connections to each host
+// which the package's default transport keeps open for reuse. Raise it when
+// making more than DefaultMaxIdleConnsPerHost concurrent requests, e.g. when
+// calling DownloadHeads with a higher concurrency, such that connections
+// aren't closed and opened anew between requests. SetMaxIdleConnsPerHost
+// panics if n <= 0. It doesn't affect transports chosen by a transport
+// selector; see SetTransportSelector.
+//
+// SetMaxIdleConnsPerHost must not be called concurrently with other functions
+// of this package. Set it once before loading any profiles.
+func SetMaxIdleConnsPerHost(n int) {
+	if n <= 0 {
+		panic("minecraft/profile: non-positive n passed to SetMaxIdleConnsPerHost")
+	}
+	transport.MaxIdleConnsPerHost = n
+	if transport.MaxIdleConns < n {
+		transport.MaxIdleConns = n
+	}
+}
+
+var transportSelector func(endpoint string) http.RoundTripper
+
+// SetTransportSelector sets a function which chooses the http.RoundTripper to
+// use for each request made by this package, e.g. to spread requests across
+// multiple proxies. endpoint is the URL about to be requested. If selector is
+// nil or returns nil, the package's default transport is used. Responses are
+// requested gzip compressed and decompressed by this package, so transports
+// need not handle compression, e.g. if they disable it.
+//
+// SetTransportSelector must not be called concurrently with other functions
+// of this package. Set the selector once before loading any profiles.
+//
+// NB! Rate limits are enforced per IP address by Mojang. Be aware that
+// distributing requests across IP addresses to circumvent the rate limits may
+// violate Mojang's terms of service. Use this feature responsibly.
+func SetTransportSelector(selector func(endpoint string) http.RoundTripper) {
+	transportSelector = selector
+}
+
+// clientKey is the context key under which WithClient stores its client.
+type clientKey struct{}
+
+// WithClient returns a copy of parent which directs this package to send the
+// requests made with it using c, e.g. to give a single call a longer timeout
+// or route it through a special proxy, while other calls use the package's
+// default client.
+//
+// The client of the context takes precedence over both the package's default
+// client and any transport selector set by SetTransportSelector; c is used as
+// is for every request made with the returned context. If c is nil, the
+// returned context uses the default client as if WithClient wasn't called.
+func WithClient(parent context.Context, c *http.Client) context.Context {
+	return context.WithValue(parent, clientKey{}, c)
+}
+
+// clientFor returns the http.Client to use when requesting endpoint with ctx.
+func clientFor(ctx context.Context, endpoint string) *http.Client {
+	if c, ok := ctx.Value(clientKey{}).(*http.Client); ok && c != nil {
+		return c
+	}
+	if transportSelector != nil {
+		if t := transportSelector(endpoint); t != nil {
+			c := *client
+			c.Transport = t
+			return &c
+		}
+	}
+	return client
+}
+
+// isNoProfileError reports whether err, returned when requesting a profile by
+// username, signals that no profile is associated with the username. Besides
+// 204 No Content, Mojang at times responds 404 Not Found, or 200 OK with an
+// empty body or an error object reporting that the profile wasn't found. Other
+// error objects, e.g. reporting transient failures, don't count.
+func isNoProfileError(err error) bool {
+	if e, ok := internal.UnwrapFailedRequestError(err); ok {
+		switch e.StatusCode {
+		case 204, 404:
+			return true
+		case 200:
+			return e.ErrorCode == notFoundCode || e.ErrorCode == notFoundExceptionCode
+		}
+		return false
+	}
+	if e, ok := err.(*url.Error); ok {
+		return e.Op == "Parse" && e.Err == io.EOF
+	}
+	return false
+}
+
+// isNoProfileResponse reports whether js, a successful response to a request
+// for a profile by username, signals that no profile is associated with the
+// username: null or an empty array.
+func isNoProfileResponse(js interface{}) bool {
+	switch js := js.(type) {
+	case nil:
+		return true
+	case []interface{}:
+		return len(js) == 0
+	default:
+		return false
+	}
+}
+
+// tooManyRequestsCode is the error code of Mojang's rate limiting responses.
+const tooManyRequestsCode = "TooManyRequestsException"
+
+// The error codes of Mojang's responses reporting that no profile was found.
+const (
+	notFoundCode          = "Not Found"
+	notFoundExceptionCode = "NotFoundException"
+)
 
+// transformError translates src to the errors documented by this package. Since
+// Mojang error objects are reported as errors even when responded with 200 OK,
+// this also applies to rate limiting signalled by such responses.
 func transformError(src error) error {
 	if e, ok := internal.UnwrapFailedRequestError(src); ok {
 		if e.StatusCode == 204 {
 			return ErrNoSuchProfile
-		} else if e.ErrorCode == "TooManyRequestsException" {
+		} else if e.ErrorCode == tooManyRequestsCode {
 			return ErrTooManyRequests
 		}
 	}
@@ -1,11 +1,19 @@
 package profile
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"io"
+	"io/ioutil"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -34,11 +42,57 @@ var testLoadInput = [...]struct {
 		expProfile: nil,
 		expErr:     ErrNoSuchProfile,
 	},
+	{
+		username:   "doesNotExist",
+		transport:  responseTransport{status: 404, body: `{"path":"/users/profiles/minecraft/doesNotExist","errorMessage":"Couldn't find any profile with name doesNotExist"}`},
+		expProfile: nil,
+		expErr:     ErrNoSuchProfile,
+	},
+	{
+		username:   "doesNotExist",
+		transport:  responseTransport{status: 200, body: ``},
+		expProfile: nil,
+		expErr:     ErrNoSuchProfile,
+	},
+	{
+		username:   "doesNotExist",
+		transport:  responseTransport{status: 200, body: `null`},
+		expProfile: nil,
+		expErr:     ErrNoSuchProfile,
+	},
+	{
+		username:   "doesNotExist",
+		transport:  responseTransport{status: 200, body: `[]`},
+		expProfile: nil,
+		expErr:     ErrNoSuchProfile,
+	},
+	{
+		username:   "doesNotExist",
+		transport:  responseTransport{status: 200, body: `{"error":"Not Found","errorMessage":"Couldn't find any profile with name doesNotExist"}`},
+		expProfile: nil,
+		expErr:     ErrNoSuchProfile,
+	},
+	{
+		username:   "nergalic",
+		transport:  responseTransport{status: 200, body: `{"error":"TooManyRequestsException","errorMessage":"The client has sent too many requests within a certain amount of time"}`},
+		expProfile: nil,
+		expErr:     ErrTooManyRequests,
+	},
+	{
+		username:   "nergalic",
+		transport:  responseTransport{status: 200, body: `{"error":"InternalServerError"}`},
+		expProfile: nil,
+		expErr: &url.Error{
+			Op:  "Get",
+			URL: "https://api.mojang.com/users/profiles/minecraft/nergalic",
+			Err: &internal.FailedRequestError{StatusCode: 200, ErrorCode: "InternalServerError"},
+		},
+	},
 	{
 		username:   "demoAccount",
 		transport:  http.NewFileTransport(http.Dir("testdata")),
 		expProfile: nil,
-		expErr:     ErrNoSuchProfile,
+		expErr:     ErrDemoProfile,
 	},
 	{
 		username:   "unexpectedFormat",
@@ -96,6 +150,32 @@ func TestLoadContextUsed(t *testing.T) {
 	}
 }
 
+func TestLoadRaw(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	for _, tc := range testLoadInput {
+		client.Transport = tc.transport
+		profile, raw, err := LoadRaw(context.Background(), tc.username)
+		if !reflect.DeepEqual(profile, tc.expProfile) || !reflect.DeepEqual(err, tc.expErr) || (raw == nil) != (err != nil) {
+			t.Errorf(
+				"LoadRaw(ctx, %q)\n"+
+					" was: %#v, %q, %s\n"+
+					"want: %#v, <raw>, %s",
+				tc.username,
+				profile, raw, p(err),
+				tc.expProfile, p(tc.expErr),
+			)
+		}
+	}
+
+	client.Transport = http.NewFileTransport(http.Dir("testdata"))
+	_, raw, _ := LoadRaw(context.Background(), "nergalic")
+	if exp, _ := ioutil.ReadFile("testdata/users/profiles/minecraft/nergalic"); !bytes.Equal(raw, exp) {
+		t.Errorf("LoadRaw(ctx, \"nergalic\") returned raw body %q; want %q", raw, exp)
+	}
+}
+
 var testLoadAtTimeInput = [...]struct {
 	username   string
 	time       time.Time
@@ -169,6 +249,68 @@ func TestLoadAtTimeContextUsed(t *testing.T) {
 	}
 }
 
+var testAtTimestampInput = [...]struct {
+	time time.Time
+	exp  int64
+}{
+	{time: time.Unix(0, 0), exp: 0},
+	{time: time.Unix(1337, 999999999), exp: 1337},
+	{time: time.Date(2017, 05, 26, 14, 00, 00, 00, time.FixedZone("CEST", 2*60*60)), exp: 1495800000},
+	{time: time.Unix(-1, 500000000), exp: -1},
+}
+
+func TestAtTimestamp(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	for _, tc := range testAtTimestampInput {
+		if ts := AtTimestamp(tc.time); ts != tc.exp {
+			t.Errorf("AtTimestamp(%s) = %d; want %d", tc.time, ts, tc.exp)
+		}
+
+		rt := &urlStoreTransport{}
+		client.Transport = rt
+		LoadAtTime(context.Background(), "nergalic", tc.time)
+		if exp := "https://api.mojang.com/users/profiles/minecraft/nergalic?at=" + strconv.FormatInt(tc.exp, 10); rt.URL != exp {
+			t.Errorf("LoadAtTime(ctx, \"nergalic\", %s) requested %q; want %q", tc.time, rt.URL, exp)
+		}
+	}
+}
+
+var testLoadAtTimeStringInput = [...]struct {
+	time   string
+	expURL string
+	expErr error
+}{
+	{time: "1495800000", expURL: "https://api.mojang.com/users/profiles/minecraft/nergalic?at=1495800000"},
+	{time: "-1", expURL: "https://api.mojang.com/users/profiles/minecraft/nergalic?at=-1"},
+	{time: "2017-05-26T12:00:00Z", expURL: "https://api.mojang.com/users/profiles/minecraft/nergalic?at=1495800000"},
+	{time: "2017-05-26T14:00:00+02:00", expURL: "https://api.mojang.com/users/profiles/minecraft/nergalic?at=1495800000"},
+	{time: "2017-05-26T12:00:00.999Z", expURL: "https://api.mojang.com/users/profiles/minecraft/nergalic?at=1495800000"},
+	{time: "2017-05-26T12:00:00", expErr: ErrInvalidTime},
+	{time: "2017-05-26", expErr: ErrInvalidTime},
+	{time: "1495800000.5", expErr: ErrInvalidTime},
+	{time: "", expErr: ErrInvalidTime},
+}
+
+func TestLoadAtTimeString(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	for _, tc := range testLoadAtTimeStringInput {
+		rt := &urlStoreTransport{}
+		client.Transport = rt
+
+		_, err := LoadAtTimeString(context.Background(), "nergalic", tc.time)
+		if rt.URL != tc.expURL {
+			t.Errorf("LoadAtTimeString(ctx, \"nergalic\", %q) requested %q; want %q", tc.time, rt.URL, tc.expURL)
+		}
+		if tc.expErr != nil && err != tc.expErr {
+			t.Errorf("LoadAtTimeString(ctx, \"nergalic\", %q) returned error %s; want %s", tc.time, p(err), tc.expErr)
+		}
+	}
+}
+
 var testLoadWithNameHistoryInput = [...]struct {
 	id         string
 	transport  http.RoundTripper
@@ -199,8 +341,9 @@ var testLoadWithNameHistoryInput = [...]struct {
 			ID:   "087cc153c3434ff7ac497de1569affa1",
 			NameHistory: []PastName{
 				{
-					Name:  "GeneralSezuan",
-					Until: msToTime(1423047705000),
+					Name:     "GeneralSezuan",
+					Until:    msToTime(1423047705000),
+					Original: true,
 				},
 			},
 		},
@@ -243,6 +386,105 @@ func TestLoadWithNameHistoryContextUsed(t *testing.T) {
 	}
 }
 
+var testResolveInput = [...]struct {
+	input      string
+	transport  http.RoundTripper
+	expProfile *Profile
+	expErr     error
+}{
+	{
+		input:     "nergalic",
+		transport: http.NewFileTransport(http.Dir("testdata")),
+		expProfile: &Profile{
+			Name: "Nergalic",
+			ID:   "087cc153c3434ff7ac497de1569affa1",
+		},
+		expErr: nil,
+	},
+	{
+		input:     "087cc153c3434ff7ac497de1569affa1",
+		transport: http.NewFileTransport(http.Dir("testdata")),
+		expProfile: &Profile{
+			Name: "Nergalic",
+			ID:   "087cc153c3434ff7ac497de1569affa1",
+			NameHistory: []PastName{
+				{
+					Name:     "GeneralSezuan",
+					Until:    msToTime(1423047705000),
+					Original: true,
+				},
+			},
+		},
+		expErr: nil,
+	},
+	{
+		input:     "087CC153-C343-4FF7-AC49-7DE1569AFFA1",
+		transport: http.NewFileTransport(http.Dir("testdata")),
+		expProfile: &Profile{
+			Name: "Nergalic",
+			ID:   "087cc153c3434ff7ac497de1569affa1",
+			NameHistory: []PastName{
+				{
+					Name:     "GeneralSezuan",
+					Until:    msToTime(1423047705000),
+					Original: true,
+				},
+			},
+		},
+		expErr: nil,
+	},
+	{
+		input:      "demoAccount",
+		transport:  http.NewFileTransport(http.Dir("testdata")),
+		expProfile: nil,
+		expErr:     ErrDemoProfile,
+	},
+	{
+		input:      "",
+		transport:  nil,
+		expProfile: nil,
+		expErr:     ErrInvalidUsername,
+	},
+	{
+		input:      "not-valid",
+		transport:  nil,
+		expProfile: nil,
+		expErr:     ErrInvalidUsername,
+	},
+	{
+		input:      "087cc153c3434ff7ac497de1569affaz", // Not hexadecimal
+		transport:  nil,
+		expProfile: nil,
+		expErr:     ErrInvalidUsername,
+	},
+	{
+		input:      "087cc153_c343_4ff7_ac49_7de1569affa1", // Not hyphenated
+		transport:  nil,
+		expProfile: nil,
+		expErr:     ErrInvalidUsername,
+	},
+}
+
+func TestResolve(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	for _, tc := range testResolveInput {
+		client.Transport = tc.transport
+		profile, err := Resolve(context.Background(), tc.input)
+		if !reflect.DeepEqual(profile, tc.expProfile) || !reflect.DeepEqual(err, tc.expErr) {
+			t.Errorf(
+				"Resolve(ctx, %q)\n"+
+					" was: %#v, %s\n"+
+					"want: %#v, %s",
+				tc.input,
+				profile, p(err),
+				tc.expProfile, p(tc.expErr),
+			)
+		}
+	}
+}
+
 var testLoadWithPropertiesInput = [...]struct {
 	id         string
 	transport  http.RoundTripper
@@ -272,9 +514,11 @@ var testLoadWithPropertiesInput = [...]struct {
 			Name: "Nergalic",
 			ID:   "087cc153c3434ff7ac497de1569affa1",
 			Properties: &Properties{
-				SkinURL: "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
-				CapeURL: "",
-				Model:   Steve,
+				SkinURL:   "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
+				CapeURL:   "",
+				Model:     Steve,
+				Timestamp: nergalicTimestamp,
+				raw:       nergalicRaw,
 			},
 		},
 		expErr: nil,
@@ -283,7 +527,7 @@ var testLoadWithPropertiesInput = [...]struct {
 		id:         "fictiveDemo",
 		transport:  http.NewFileTransport(http.Dir("testdata")),
 		expProfile: nil,
-		expErr:     ErrNoSuchProfile,
+		expErr:     ErrDemoProfile,
 	},
 }
 
@@ -356,6 +600,26 @@ var testLoadManyInput = [...]struct {
 			Err: internal.ErrUnknownFormat,
 		},
 	},
+	{
+		ids:         []string{"dummy"},
+		transport:   responseTransport{status: 200, body: `{"error":"TooManyRequestsException"}`},
+		expProfiles: nil,
+		expErr:      ErrTooManyRequests,
+	},
+	{
+		ids:         []string{"dummy"},
+		transport:   responseTransport{status: 200, body: `{"error":"IllegalArgumentException","errorMessage":"Invalid payload."}`},
+		expProfiles: nil,
+		expErr: &url.Error{
+			Op:  "Post",
+			URL: "https://api.mojang.com/profiles/minecraft",
+			Err: &internal.FailedRequestError{
+				StatusCode:   200,
+				ErrorCode:    "IllegalArgumentException",
+				ErrorMessage: "Invalid payload.",
+			},
+		},
+	},
 	{
 		ids:       []string{"nergalic", "AxeLaw", "demo", "doesNotExist"},
 		transport: http.NewFileTransport(http.Dir("testdata/LoadMany/success")),
@@ -409,6 +673,374 @@ func TestLoadManyContextUsed(t *testing.T) {
 	}
 }
 
+var testLoadManyOrderedInput = [...]struct {
+	ids       []string
+	transport http.RoundTripper
+	expNames  []string
+	expErr    error
+}{
+	{
+		ids:       []string{},
+		transport: nil,
+		expNames:  []string{},
+		expErr:    nil,
+	},
+	{
+		ids:       []string{""},
+		transport: nil,
+		expNames:  []string{""},
+		expErr:    nil,
+	},
+	{
+		ids:       make([]string, LoadManyMaxSize+1, LoadManyMaxSize+1),
+		transport: nil,
+		expNames:  nil,
+		expErr:    ErrMaxSizeExceeded{LoadManyMaxSize + 1},
+	},
+	{
+		ids:       []string{"nergalic", "", "AxeLaw", "demo", "doesNotExist", "NERGALIC"},
+		transport: http.NewFileTransport(http.Dir("testdata/LoadMany/success")),
+		expNames:  []string{"Nergalic", "", "AxeLaw", "", "", "Nergalic"},
+		expErr:    nil,
+	},
+}
+
+func TestLoadManyOrdered(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	for _, tc := range testLoadManyOrderedInput {
+		client.Transport = tc.transport
+		profiles, err := LoadManyOrdered(context.Background(), tc.ids...)
+
+		var names []string
+		if profiles != nil {
+			names = make([]string, len(profiles))
+			for i, pr := range profiles {
+				if pr != nil {
+					names[i] = pr.Name
+				}
+			}
+		}
+		if !reflect.DeepEqual(names, tc.expNames) || !reflect.DeepEqual(err, tc.expErr) {
+			t.Errorf(
+				"LoadManyOrdered(ctx, %q)\n"+
+					" was: profiles named %q, %s\n"+
+					"want: profiles named %q, %s",
+				tc.ids,
+				names, p(err),
+				tc.expNames, p(tc.expErr),
+			)
+		}
+	}
+}
+
+var testLoadManyStrictInput = [...]struct {
+	ids       []string
+	transport http.RoundTripper
+	expNames  []string
+	expErr    error
+}{
+	{
+		ids:       []string{},
+		transport: nil,
+		expNames:  nil,
+		expErr:    nil,
+	},
+	{
+		ids:       []string{""},
+		transport: nil,
+		expNames:  nil,
+		expErr:    ErrInvalidUsername,
+	},
+	{
+		ids:       []string{"nergalic", "", "AxeLaw"},
+		transport: nil,
+		expNames:  nil,
+		expErr:    ErrInvalidUsername,
+	},
+	{
+		ids:       make([]string, LoadManyMaxSize+1, LoadManyMaxSize+1),
+		transport: nil,
+		expNames:  nil,
+		expErr:    ErrMaxSizeExceeded{LoadManyMaxSize + 1},
+	},
+	{
+		ids:       []string{"nergalic", "AxeLaw", "demo", "doesNotExist"},
+		transport: http.NewFileTransport(http.Dir("testdata/LoadMany/success")),
+		expNames:  []string{"AxeLaw", "Nergalic"},
+		expErr:    nil,
+	},
+}
+
+func TestLoadManyStrict(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	for _, tc := range testLoadManyStrictInput {
+		client.Transport = tc.transport
+		if client.Transport == nil {
+			client.Transport = errorTransport{errors.New("RoundTrip was called")}
+		}
+		profiles, err := LoadManyStrict(context.Background(), tc.ids...)
+
+		var names []string
+		for _, pr := range profiles {
+			names = append(names, pr.Name)
+		}
+		if !reflect.DeepEqual(names, tc.expNames) || !reflect.DeepEqual(err, tc.expErr) {
+			t.Errorf(
+				"LoadManyStrict(ctx, %q)\n"+
+					" was: profiles named %q, %s\n"+
+					"want: profiles named %q, %s",
+				tc.ids,
+				names, p(err),
+				tc.expNames, p(tc.expErr),
+			)
+		}
+	}
+}
+
+var testNilContextInput = [...]struct {
+	fn   string
+	call func(ctx context.Context)
+}{
+	{fn: "Load", call: func(ctx context.Context) { Load(ctx, "") }},
+	{fn: "LoadRaw", call: func(ctx context.Context) { LoadRaw(ctx, "") }},
+	{fn: "LoadAtTime", call: func(ctx context.Context) { LoadAtTime(ctx, "", time.Time{}) }},
+	{fn: "LoadAtTimeString", call: func(ctx context.Context) { LoadAtTimeString(ctx, "", "") }},
+	{fn: "Resolve", call: func(ctx context.Context) { Resolve(ctx, "") }},
+	{fn: "LoadByID", call: func(ctx context.Context) { LoadByID(ctx, "") }},
+	{fn: "LoadWithNameHistory", call: func(ctx context.Context) { LoadWithNameHistory(ctx, "") }},
+	{fn: "LoadWithProperties", call: func(ctx context.Context) { LoadWithProperties(ctx, "") }},
+	{fn: "LoadWithPropertiesForce", call: func(ctx context.Context) { LoadWithPropertiesForce(ctx, "") }},
+	{fn: "LoadMany", call: func(ctx context.Context) { LoadMany(ctx) }},
+	{fn: "LoadManyOrdered", call: func(ctx context.Context) { LoadManyOrdered(ctx) }},
+	{fn: "LoadManyStrict", call: func(ctx context.Context) { LoadManyStrict(ctx) }},
+	{fn: "NameStatus", call: func(ctx context.Context) { NameStatus(ctx, "") }},
+	{fn: "DetectRename", call: func(ctx context.Context) { DetectRename(ctx, "") }},
+	{fn: "FilterExisting", call: func(ctx context.Context) { FilterExisting(ctx, 1) }},
+	{fn: "ScanAvailability", call: func(ctx context.Context) { ScanAvailability(ctx, nil, 1, nil) }},
+	{fn: "APIStatus", call: func(ctx context.Context) { APIStatus(ctx) }},
+	{fn: "DownloadHeads", call: func(ctx context.Context) { DownloadHeads(ctx, nil, 8, 1) }},
+	{fn: "Cache.Load", call: func(ctx context.Context) { (&Cache{}).Load(ctx, "") }},
+	{fn: "Cache.LoadHit", call: func(ctx context.Context) { (&Cache{}).LoadHit(ctx, "") }},
+	{fn: "Profile.LoadNameHistory", call: func(ctx context.Context) { (&Profile{}).LoadNameHistory(ctx, false) }},
+	{fn: "Profile.LoadProperties", call: func(ctx context.Context) { (&Profile{}).LoadProperties(ctx, false) }},
+	{fn: "Properties.SkinReader", call: func(ctx context.Context) { (&Properties{Model: Model(255)}).SkinReader(ctx) }},
+	{fn: "Properties.FetchSkin", call: func(ctx context.Context) { (&Properties{}).FetchSkin(ctx) }},
+	{fn: "Properties.CapeReader", call: func(ctx context.Context) { (&Properties{}).CapeReader(ctx) }},
+	{fn: "Properties.FetchCape", call: func(ctx context.Context) { (&Properties{}).FetchCape(ctx) }},
+}
+
+func TestNilContext(t *testing.T) {
+	for _, tc := range testNilContextInput {
+		exp := "minecraft/profile: nil Context passed to " + tc.fn
+		func() {
+			defer func() {
+				if r := recover(); r != exp {
+					t.Errorf("%s(nil, ...) panicked with %#v; want %q", tc.fn, r, exp)
+				}
+			}()
+			tc.call(nil)
+		}()
+	}
+}
+
+func TestSetTransportSelector(t *testing.T) {
+	origTransport := client.Transport
+	defer func() {
+		client.Transport = origTransport
+		SetTransportSelector(nil)
+	}()
+
+	client.Transport = errorTransport{testError}
+
+	var endpoints []string
+	SetTransportSelector(func(endpoint string) http.RoundTripper {
+		endpoints = append(endpoints, endpoint)
+		if len(endpoints) == 1 {
+			return http.NewFileTransport(http.Dir("testdata"))
+		}
+		return nil // Use default transport
+	})
+
+	if _, err := Load(context.Background(), "nergalic"); err != nil {
+		t.Errorf("Load(ctx, \"nergalic\") didn't use selected transport; got error: %s", err)
+	}
+	if _, err := Load(context.Background(), "nergalic"); !reflect.DeepEqual(err, &url.Error{Op: "Get", URL: profileURL(loadPath, "nergalic"), Err: testError}) {
+		t.Errorf("Load(ctx, \"nergalic\") didn't use default transport when selector returned nil; got error: %s", p(err))
+	}
+
+	exp := []string{profileURL(loadPath, "nergalic"), profileURL(loadPath, "nergalic")}
+	if !reflect.DeepEqual(endpoints, exp) {
+		t.Errorf("transport selector was called with endpoints %q; want %q", endpoints, exp)
+	}
+}
+
+func TestWithClient(t *testing.T) {
+	origTransport := client.Transport
+	defer func() {
+		client.Transport = origTransport
+		SetTransportSelector(nil)
+	}()
+
+	client.Transport = errorTransport{testError}
+	SetTransportSelector(func(endpoint string) http.RoundTripper {
+		return errorTransport{testError}
+	})
+
+	c := &http.Client{Transport: http.NewFileTransport(http.Dir("testdata"))}
+	ctx := WithClient(context.Background(), c)
+	if _, err := Load(ctx, "nergalic"); err != nil {
+		t.Errorf("Load(WithClient(ctx, c), \"nergalic\") didn't use c; got error: %s", err)
+	}
+	if _, err := LoadWithNameHistory(ctx, "087cc153c3434ff7ac497de1569affa1"); err != nil {
+		t.Errorf("LoadWithNameHistory(WithClient(ctx, c), \"087cc153c3434ff7ac497de1569affa1\") didn't use c; got error: %s", err)
+	}
+
+	SetTransportSelector(nil)
+	exp := &url.Error{Op: "Get", URL: profileURL(loadPath, "nergalic"), Err: testError}
+	if _, err := Load(WithClient(context.Background(), nil), "nergalic"); !reflect.DeepEqual(err, exp) {
+		t.Errorf("Load(WithClient(ctx, nil), \"nergalic\") didn't use default client; got error: %s", p(err))
+	}
+}
+
+// Test that the default transport reuses connections, also when requests are
+// made concurrently
+func TestConnectionReuse(t *testing.T) {
+	origTransport := client.Transport
+	defer func() {
+		client.Transport = origTransport
+		Configure(Config{})
+	}()
+
+	var (
+		mu    sync.Mutex
+		conns int
+	)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"id":"087cc153c3434ff7ac497de1569affa1","name":"Nergalic"}`)
+	}))
+	srv.Config.ConnState = func(_ net.Conn, s http.ConnState) {
+		if s == http.StateNew {
+			mu.Lock()
+			conns++
+			mu.Unlock()
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	client.Transport = transport
+	Configure(Config{ProfileBaseURL: srv.URL})
+
+	for i := 0; i < 20; i++ {
+		if _, err := Load(context.Background(), "nergalic"); err != nil {
+			t.Fatalf("Load(ctx, \"nergalic\") failed: %s", err)
+		}
+	}
+	if mu.Lock(); conns != 1 {
+		t.Errorf("20 sequential calls to Load opened %d connections; want 1", conns)
+	}
+	mu.Unlock()
+
+	const workers = 8
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				Load(context.Background(), "nergalic")
+			}
+		}()
+	}
+	wg.Wait()
+	if mu.Lock(); conns > workers {
+		t.Errorf("%d goroutines calling Load concurrently opened %d connections; want at most %d", workers, conns, workers)
+	}
+	mu.Unlock()
+}
+
+func TestSetMaxIdleConnsPerHost(t *testing.T) {
+	defer func(n, m int) {
+		transport.MaxIdleConnsPerHost, transport.MaxIdleConns = n, m
+	}(transport.MaxIdleConnsPerHost, transport.MaxIdleConns)
+
+	SetMaxIdleConnsPerHost(32)
+	if n, m := transport.MaxIdleConnsPerHost, transport.MaxIdleConns; n != 32 || m != 100 {
+		t.Errorf("SetMaxIdleConnsPerHost(32) set MaxIdleConnsPerHost, MaxIdleConns to %d, %d; want 32, 100", n, m)
+	}
+	SetMaxIdleConnsPerHost(200)
+	if n, m := transport.MaxIdleConnsPerHost, transport.MaxIdleConns; n != 200 || m != 200 {
+		t.Errorf("SetMaxIdleConnsPerHost(200) set MaxIdleConnsPerHost, MaxIdleConns to %d, %d; want 200, 200", n, m)
+	}
+
+	const exp = "minecraft/profile: non-positive n passed to SetMaxIdleConnsPerHost"
+	defer func() {
+		if r := recover(); r != exp {
+			t.Errorf("SetMaxIdleConnsPerHost(0) panicked with %#v; want %q", r, exp)
+		}
+	}()
+	SetMaxIdleConnsPerHost(0)
+}
+
+func TestTransportHTTP2(t *testing.T) {
+	if !transport.ForceAttemptHTTP2 {
+		t.Error("The default transport doesn't attempt HTTP/2")
+	}
+}
+
+func TestSetIPVersion(t *testing.T) {
+	defer SetIPVersion(AnyIP)
+
+	ln, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	addr := ln.Addr().String()
+
+	for _, tc := range [...]struct {
+		v      IPVersion
+		expErr bool
+	}{
+		{v: IPv4, expErr: false},
+		{v: IPv6, expErr: true},
+		{v: AnyIP, expErr: false},
+	} {
+		SetIPVersion(tc.v)
+		conn, err := transport.DialContext(context.Background(), "tcp", addr)
+		if err == nil {
+			conn.Close()
+		}
+		if (err != nil) != tc.expErr {
+			t.Errorf("Dialing IPv4 address %s after SetIPVersion(%d) returned error %v; want error: %t", addr, tc.v, err, tc.expErr)
+		}
+		if !transport.ForceAttemptHTTP2 {
+			t.Errorf("The default transport doesn't attempt HTTP/2 after SetIPVersion(%d)", tc.v)
+		}
+	}
+
+	const exp = "minecraft/profile: unknown IP version passed to SetIPVersion"
+	defer func() {
+		if r := recover(); r != exp {
+			t.Errorf("SetIPVersion(99) panicked with %#v; want %q", r, exp)
+		}
+	}()
+	SetIPVersion(99)
+}
+
 /***************
 *  TEST UTILS  *
 ***************/
@@ -417,6 +1049,17 @@ var dummy struct{}
 
 var testError = errors.New("testError")
 
+// nergalicTimestamp is the timestamp of the textures property of the Nergalic
+// profile in testdata.
+var nergalicTimestamp = msToTime(1495799175553)
+
+var nergalicRaw = []RawProperty{
+	{
+		Name:  "textures",
+		Value: "eyJ0aW1lc3RhbXAiOjE0OTU3OTkxNzU1NTMsInByb2ZpbGVJZCI6IjA4N2NjMTUzYzM0MzRmZjdhYzQ5N2RlMTU2OWFmZmExIiwicHJvZmlsZU5hbWUiOiJOZXJnYWxpYyIsInRleHR1cmVzIjp7IlNLSU4iOnsidXJsIjoiaHR0cDovL3RleHR1cmVzLm1pbmVjcmFmdC5uZXQvdGV4dHVyZS81YjQwZjI1MWY3YzhkYjYwOTQzNDk1ZGI2YmY1NDM1MzEwMmQ2Y2FkMjBkMjI5OWQ1Zjk3M2YzNmI0ZjM2NzdlIn19fQ==",
+	},
+}
+
 func p(x interface{}) interface{} {
 	if x == nil {
 		return "<nil>"
@@ -436,6 +1079,22 @@ func (sot statusOverrideTransport) RoundTrip(req *http.Request) (resp *http.Resp
 	return
 }
 
+// responseTransport responds to every request with the given status code and
+// body.
+type responseTransport struct {
+	status int
+	body   string
+}
+
+func (rt responseTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: rt.status,
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(strings.NewReader(rt.body)),
+		Request:    req,
+	}, nil
+}
+
 type errorTransport struct {
 	err error
 }
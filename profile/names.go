@@ -0,0 +1,295 @@
+package profile
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PhilipBorgesen/minecraft/internal"
+)
+
+// Status represents whether a username may be registered.
+type Status byte
+
+const (
+	Unavailable Status = iota // Username can neither be used nor registered.
+	Taken                     // Username is used by a profile.
+	Available                 // Username is unused and may be registered.
+)
+
+// String returns a string representation of s.
+//	Unavailable.String() = "Unavailable"
+//	Taken.String()       = "Taken"
+//	Available.String()   = "Available"
+// String returns "???" for statuses not declared by this package.
+func (s Status) String() string {
+	switch s {
+	case Unavailable:
+		return "Unavailable"
+	case Taken:
+		return "Taken"
+	case Available:
+		return "Available"
+	default:
+		return "???"
+	}
+}
+
+// NameStatus reports whether username is taken by a profile, available for
+// registration, or unavailable. ctx must be non-nil.
+//
+// A username is Unavailable if it doesn't adhere to Mojang's username rules
+// (3-16 characters, only letters, digits and underscores) or if it is held by
+// a demo profile. A username which no profile is associated with is reported
+// Available, but be aware that Mojang may still reject registering it, e.g.
+// if it was released recently or has been blocked.
+//
+// If an error occurs, NameStatus returns Unavailable along with the error.
+func NameStatus(ctx context.Context, username string) (Status, error) {
+	internal.CheckContext(ctx, "profile", "NameStatus")
+
+	if !isValidUsername(username) {
+		return Unavailable, nil
+	}
+	switch _, err := Load(ctx, username); err {
+	case nil:
+		return Taken, nil
+	case ErrNoSuchProfile:
+		return Available, nil
+	case ErrDemoProfile:
+		return Unavailable, nil
+	default:
+		return Unavailable, err
+	}
+}
+
+// DetectRename determines the profile which used oldName as username, e.g. a
+// username stored by a system predating username changes, and reports whether
+// the profile since has been renamed. ctx must be non-nil.
+//
+// If oldName has changed owner, the profile which originally registered oldName
+// is returned, as reported by Mojang. Otherwise the profile currently using
+// oldName is returned and renamed is false. Usernames are compared
+// case-insensitively, so a change of capitalization isn't reported as a rename.
+//
+// If no profile has used oldName, DetectRename returns ErrNoSuchProfile. If an
+// error is returned, current will be nil.
+func DetectRename(ctx context.Context, oldName string) (current *Profile, renamed bool, err error) {
+	internal.CheckContext(ctx, "profile", "DetectRename")
+
+	// Mojang only reports the original owner of usernames which have changed
+	// owner at least once
+	p, err := LoadAtTime(ctx, oldName, time.Unix(0, 0))
+	if err == ErrNoSuchProfile {
+		p, err = Load(ctx, oldName)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return p, !strings.EqualFold(p.Name, oldName), nil
+}
+
+// FilterExisting returns the usernames of candidates which are associated with a
+// profile, e.g. to validate an imported list of usernames. Usernames are
+// returned as given and in the order given, so the result is nil if none of
+// candidates exist. Usernames held by demo profiles are considered not to
+// exist. At most concurrency usernames are looked up at the same time. ctx must
+// be non-nil. FilterExisting panics if concurrency <= 0.
+//
+// Only usernames for which Load returns ErrNoSuchProfile or ErrDemoProfile are
+// filtered out. If looking up a username fails otherwise, e.g. due to
+// ErrTooManyRequests, FilterExisting stops and returns the usernames found to
+// exist so far along with the error, so a transient failure never causes a
+// username to be reported nonexistent. If ctx is done before all usernames are
+// looked up, the usernames found so far are returned along with ctx.Err().
+//
+// NB! Each username looked up counts towards Mojang's rate limits. See
+// RateLimitFor.
+func FilterExisting(ctx context.Context, concurrency int, candidates ...string) ([]string, error) {
+	internal.CheckContext(ctx, "profile", "FilterExisting")
+	if concurrency <= 0 {
+		panic("minecraft/profile: non-positive concurrency passed to FilterExisting")
+	}
+
+	lookupCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		exists   = make([]bool, len(candidates))
+		firstErr error
+	)
+
+	work := make(chan int)
+	for i := 0; i < concurrency && i < len(candidates); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				switch _, err := Load(lookupCtx, candidates[i]); err {
+				case nil:
+					exists[i] = true
+				case ErrNoSuchProfile, ErrDemoProfile:
+				default:
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+						cancel() // Stop looking up the remaining usernames
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+feed:
+	for i := range candidates {
+		select {
+		case work <- i:
+		case <-lookupCtx.Done():
+			break feed
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	var names []string
+	for i, ok := range exists {
+		if ok {
+			names = append(names, candidates[i])
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return names, err
+	}
+	return names, firstErr
+}
+
+// The number of lookups ScanAvailability may start within any window of time
+// of length scanWindow, and the function it waits for the window to pass by.
+// They may be replaced by tests.
+var (
+	scanLimit  = LoadRateLimit
+	scanWindow = LoadRateWindow
+	scanAfter  = time.After
+)
+
+// scanLimiter throttles the lookups of ScanAvailability, such that at most
+// scanLimit lookups are started within any window of time of length
+// scanWindow.
+type scanLimiter struct {
+	mu     sync.Mutex
+	starts []time.Time // When the latest lookups were started, oldest first.
+}
+
+// wait blocks until another lookup may be started without exceeding the limit,
+// and records that it's started. If ctx is done first, ctx.Err() is returned.
+func (l *scanLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.starts) == scanLimit {
+		if d := l.starts[0].Add(scanWindow).Sub(now()); d > 0 {
+			select {
+			case <-scanAfter(d):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		l.starts = l.starts[1:]
+	}
+	l.starts = append(l.starts, now())
+	return nil
+}
+
+// ScanAvailability reports whether each of names is available for registration
+// as described for NameStatus, e.g. to plan the registration of many usernames.
+// For each name, onResult is called with the name, whether its status is
+// Available, and the error NameStatus returned, if any. onResult is called once
+// per name, though not necessarily in the order of names, and never
+// concurrently; ScanAvailability waits for onResult to return before reporting
+// more results, so keep it fast. At most concurrency names are looked up at the
+// same time. ctx must be non-nil. ScanAvailability panics if concurrency <= 0.
+//
+// To stay below Mojang's rate limit, ScanAvailability throttles itself such
+// that at most LoadRateLimit lookups are started within any LoadRateWindow:
+// The first LoadRateLimit names are looked up without delay, after which each
+// lookup waits until LoadRateWindow has passed since the lookup LoadRateLimit
+// lookups before it was started. Names which don't adhere to Mojang's username
+// rules are reported without being looked up. Since other requests made to
+// Mojang count towards the same rate limit, errors such as ErrTooManyRequests
+// may still be reported for some names; scanning then continues with the
+// remaining names.
+//
+// If ctx is done before all names are looked up, ScanAvailability returns
+// ctx.Err() without reporting the names not looked up yet. Otherwise it returns
+// nil once every name has been reported.
+func ScanAvailability(ctx context.Context, names []string, concurrency int, onResult func(name string, available bool, err error)) error {
+	internal.CheckContext(ctx, "profile", "ScanAvailability")
+	if concurrency <= 0 {
+		panic("minecraft/profile: non-positive concurrency passed to ScanAvailability")
+	}
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		lim scanLimiter
+	)
+	report := func(name string, available bool, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		onResult(name, available, err)
+	}
+
+	work := make(chan string)
+	for i := 0; i < concurrency && i < len(names); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range work {
+				if lim.wait(ctx) != nil {
+					continue // Don't report lookups aborted by ctx
+				}
+				s, err := NameStatus(ctx, name)
+				if ctx.Err() != nil {
+					continue // Don't report lookups aborted by ctx
+				}
+				report(name, s == Available, err)
+			}
+		}()
+	}
+
+feed:
+	for _, name := range names {
+		if !isValidUsername(name) {
+			report(name, false, nil) // No request needed
+			continue
+		}
+		select {
+		case work <- name:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+// isValidUsername reports whether name may be registered as a username.
+func isValidUsername(name string) bool {
+	if len(name) < 3 || len(name) > 16 {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		switch c := name[i]; {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,429 @@
+package profile
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"path"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PhilipBorgesen/minecraft/internal"
+)
+
+var testStatusStringInput = [...]struct {
+	status Status
+	expStr string
+}{
+	{
+		status: Unavailable,
+		expStr: "Unavailable",
+	},
+	{
+		status: Taken,
+		expStr: "Taken",
+	},
+	{
+		status: Available,
+		expStr: "Available",
+	},
+	{
+		status: Status(99),
+		expStr: "???",
+	},
+}
+
+func TestStatus_String(t *testing.T) {
+	for _, tc := range testStatusStringInput {
+		s := tc.status.String()
+		if s != tc.expStr {
+			t.Errorf(
+				"Status(%d).String() was %q; want %q",
+				byte(tc.status), s, tc.expStr,
+			)
+		}
+	}
+}
+
+var testNameStatusInput = [...]struct {
+	username  string
+	transport http.RoundTripper
+	expStatus Status
+	expErr    error
+}{
+	{
+		username:  "",
+		transport: nil,
+		expStatus: Unavailable,
+		expErr:    nil,
+	},
+	{
+		username:  "no",
+		transport: nil,
+		expStatus: Unavailable,
+		expErr:    nil,
+	},
+	{
+		username:  "seventeen_letters",
+		transport: nil,
+		expStatus: Unavailable,
+		expErr:    nil,
+	},
+	{
+		username:  "not-valid",
+		transport: nil,
+		expStatus: Unavailable,
+		expErr:    nil,
+	},
+	{
+		username:  "nergalic",
+		transport: http.NewFileTransport(http.Dir("testdata")),
+		expStatus: Taken,
+		expErr:    nil,
+	},
+	{
+		username: "doesNotExist",
+		transport: errorTransport{
+			&internal.FailedRequestError{
+				StatusCode: 204,
+			},
+		},
+		expStatus: Available,
+		expErr:    nil,
+	},
+	{
+		username:  "demoAccount",
+		transport: http.NewFileTransport(http.Dir("testdata")),
+		expStatus: Unavailable,
+		expErr:    nil,
+	},
+	{
+		username:  "transient",
+		transport: responseTransport{status: 200, body: `{"error":"InternalServerError"}`},
+		expStatus: Unavailable,
+		expErr: &url.Error{
+			Op:  "Get",
+			URL: "https://api.mojang.com/users/profiles/minecraft/transient",
+			Err: &internal.FailedRequestError{StatusCode: 200, ErrorCode: "InternalServerError"},
+		},
+	},
+	{
+		username:  "failing",
+		transport: errorTransport{testError},
+		expStatus: Unavailable,
+		expErr: &url.Error{
+			Op:  "Get",
+			URL: "https://api.mojang.com/users/profiles/minecraft/failing",
+			Err: testError,
+		},
+	},
+}
+
+func TestNameStatus(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	for _, tc := range testNameStatusInput {
+		client.Transport = tc.transport
+		status, err := NameStatus(context.Background(), tc.username)
+		if status != tc.expStatus || !reflect.DeepEqual(err, tc.expErr) {
+			t.Errorf(
+				"NameStatus(ctx, %q)\n"+
+					" was: %s, %s\n"+
+					"want: %s, %s",
+				tc.username,
+				status, p(err),
+				tc.expStatus, p(tc.expErr),
+			)
+		}
+	}
+}
+
+var testDetectRenameInput = [...]struct {
+	oldName    string
+	transport  http.RoundTripper
+	expProfile *Profile
+	expRenamed bool
+	expErr     error
+}{
+	{ // Renamed
+		oldName:    "GeneralSezuan",
+		transport:  atTransport{at: http.NewFileTransport(http.Dir("testdata")), now: errorTransport{&internal.FailedRequestError{StatusCode: 204}}},
+		expProfile: &Profile{ID: "087cc153c3434ff7ac497de1569affa1", Name: "Nergalic"},
+		expRenamed: true,
+	},
+	{ // Never changed owner
+		oldName:    "nergalic",
+		transport:  atTransport{at: errorTransport{&internal.FailedRequestError{StatusCode: 204}}, now: http.NewFileTransport(http.Dir("testdata"))},
+		expProfile: &Profile{ID: "087cc153c3434ff7ac497de1569affa1", Name: "Nergalic"},
+		expRenamed: false,
+	},
+	{ // Never used
+		oldName:   "doesNotExist",
+		transport: errorTransport{&internal.FailedRequestError{StatusCode: 204}},
+		expErr:    ErrNoSuchProfile,
+	},
+	{ // Demo profile
+		oldName:   "demoAccount",
+		transport: atTransport{at: errorTransport{&internal.FailedRequestError{StatusCode: 204}}, now: http.NewFileTransport(http.Dir("testdata"))},
+		expErr:    ErrDemoProfile,
+	},
+	{ // Request error
+		oldName:   "GeneralSezuan",
+		transport: errorTransport{testError},
+		expErr:    &url.Error{Op: "Get", URL: "https://api.mojang.com/users/profiles/minecraft/GeneralSezuan?at=0", Err: testError},
+	},
+}
+
+func TestDetectRename(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	for _, tc := range testDetectRenameInput {
+		client.Transport = tc.transport
+		profile, renamed, err := DetectRename(context.Background(), tc.oldName)
+		if !reflect.DeepEqual(profile, tc.expProfile) || renamed != tc.expRenamed || !reflect.DeepEqual(err, tc.expErr) {
+			t.Errorf(
+				"DetectRename(ctx, %q)\n"+
+					" was: %#v, %t, %s\n"+
+					"want: %#v, %t, %s",
+				tc.oldName,
+				profile, renamed, p(err),
+				tc.expProfile, tc.expRenamed, p(tc.expErr),
+			)
+		}
+	}
+}
+
+var testFilterExistingInput = [...]struct {
+	candidates  []string
+	concurrency int
+	transport   http.RoundTripper
+	expNames    []string
+	expErr      error
+}{
+	{ // No candidates
+		candidates:  nil,
+		concurrency: 1,
+		transport:   nil,
+		expNames:    nil,
+		expErr:      nil,
+	},
+	{
+		candidates:  []string{"doesNotExist", "nergalic", "demoAccount", "", "nergalic"},
+		concurrency: 3,
+		transport:   http.NewFileTransport(http.Dir("testdata")),
+		expNames:    []string{"nergalic", "nergalic"},
+		expErr:      nil,
+	},
+	{ // More workers than candidates
+		candidates:  []string{"nergalic"},
+		concurrency: 10,
+		transport:   http.NewFileTransport(http.Dir("testdata")),
+		expNames:    []string{"nergalic"},
+		expErr:      nil,
+	},
+	{ // Failures must not mark names as nonexistent
+		candidates:  []string{"nergalic", "failing", "doesNotExist"},
+		concurrency: 1,
+		transport:   failingTransport{name: "failing", transport: http.NewFileTransport(http.Dir("testdata"))},
+		expNames:    []string{"nergalic"},
+		expErr:      &url.Error{Op: "Get", URL: "https://api.mojang.com/users/profiles/minecraft/failing", Err: testError},
+	},
+}
+
+func TestFilterExisting(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	for _, tc := range testFilterExistingInput {
+		client.Transport = tc.transport
+		names, err := FilterExisting(context.Background(), tc.concurrency, tc.candidates...)
+		if !reflect.DeepEqual(names, tc.expNames) || !reflect.DeepEqual(err, tc.expErr) {
+			t.Errorf(
+				"FilterExisting(ctx, %d, %q...)\n"+
+					" was: %q, %s\n"+
+					"want: %q, %s",
+				tc.concurrency, tc.candidates,
+				names, p(err),
+				tc.expNames, p(tc.expErr),
+			)
+		}
+	}
+}
+
+func TestFilterExistingContextDone(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	client.Transport = http.NewFileTransport(http.Dir("testdata"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if names, err := FilterExisting(ctx, 2, "nergalic", "nergalic"); names != nil || err != context.Canceled {
+		t.Errorf("FilterExisting(cancelled ctx, 2, ...) was %q, %v; want [], %s", names, err, context.Canceled)
+	}
+}
+
+func TestFilterExistingPanic(t *testing.T) {
+	const exp = "minecraft/profile: non-positive concurrency passed to FilterExisting"
+	defer func() {
+		if r := recover(); r != exp {
+			t.Errorf("FilterExisting(ctx, 0) panicked with %#v; want %q", r, exp)
+		}
+	}()
+	FilterExisting(context.Background(), 0)
+}
+
+type scanResult struct {
+	available bool
+	err       error
+}
+
+func TestScanAvailability(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	client.Transport = failingTransport{name: "failing", transport: http.NewFileTransport(http.Dir("testdata"))}
+
+	names := []string{"nergalic", "doesNotExist", "x", "demoAccount", "failing"}
+	exp := map[string]scanResult{
+		"nergalic":     {available: false},
+		"doesNotExist": {available: true},
+		"x":            {available: false},
+		"demoAccount":  {available: false},
+		"failing":      {available: false, err: &url.Error{Op: "Get", URL: "https://api.mojang.com/users/profiles/minecraft/failing", Err: testError}},
+	}
+
+	res := make(map[string]scanResult)
+	err := ScanAvailability(context.Background(), names, 2, func(name string, available bool, err error) {
+		if _, dup := res[name]; dup {
+			t.Errorf("ScanAvailability(ctx, %q, 2, fn) reported %q more than once", names, name)
+		}
+		res[name] = scanResult{available, err}
+	})
+	if !reflect.DeepEqual(res, exp) || err != nil {
+		t.Errorf(
+			"ScanAvailability(ctx, %q, 2, fn)\n"+
+				" reported: %v, returned %v\n"+
+				"     want: %v, <nil>",
+			names, res, err, exp,
+		)
+	}
+}
+
+func TestScanAvailabilityThrottled(t *testing.T) {
+	origTransport := client.Transport
+	origLimit, origWindow, origAfter, origNow := scanLimit, scanWindow, scanAfter, now
+	defer func() {
+		client.Transport = origTransport
+		scanLimit, scanWindow, scanAfter, now = origLimit, origWindow, origAfter, origNow
+	}()
+
+	var (
+		mu     sync.Mutex
+		tm     = time.Date(2017, 05, 26, 12, 00, 00, 00, time.UTC)
+		starts []time.Time // When each request was made
+	)
+	now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return tm
+	}
+	scanAfter = func(d time.Duration) <-chan time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		tm = tm.Add(d)
+		c := make(chan time.Time, 1)
+		c <- tm
+		return c
+	}
+	client.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		starts = append(starts, tm)
+		mu.Unlock()
+		return http.NewFileTransport(http.Dir("testdata")).RoundTrip(req)
+	})
+	scanLimit, scanWindow = 3, 10*time.Minute
+
+	names := []string{"nergalic", "x", "nergalic", "nergalic", "nergalic", "nergalic", "nergalic", "nergalic", "nergalic"}
+	n := 0
+	ScanAvailability(context.Background(), names, 1, func(string, bool, error) { n++ })
+	if n != len(names) || len(starts) != len(names)-1 {
+		t.Fatalf("ScanAvailability(ctx, %q, 1, fn) reported %d names, made %d requests; want %d, %d", names, n, len(starts), len(names), len(names)-1)
+	}
+	for i := scanLimit; i < len(starts); i++ {
+		if d := starts[i].Sub(starts[i-scanLimit]); d < scanWindow {
+			t.Errorf("ScanAvailability(ctx, %q, 1, fn) made %d requests within %s; want at most %d per %s", names, scanLimit+1, d, scanLimit, scanWindow)
+		}
+	}
+	if d := starts[len(starts)-1].Sub(starts[0]); d != 2*scanWindow {
+		t.Errorf("ScanAvailability(ctx, %q, 1, fn) made its requests within %s; want %s", names, d, 2*scanWindow)
+	}
+}
+
+func TestScanAvailabilityContextDone(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	client.Transport = http.NewFileTransport(http.Dir("testdata"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := ScanAvailability(ctx, []string{"nergalic", "doesNotExist"}, 2, func(name string, _ bool, _ error) {
+		t.Errorf("ScanAvailability(cancelled ctx, ...) reported %q", name)
+	})
+	if err != context.Canceled {
+		t.Errorf("ScanAvailability(cancelled ctx, ...) returned %v; want %s", err, context.Canceled)
+	}
+}
+
+func TestScanAvailabilityPanic(t *testing.T) {
+	const exp = "minecraft/profile: non-positive concurrency passed to ScanAvailability"
+	defer func() {
+		if r := recover(); r != exp {
+			t.Errorf("ScanAvailability(ctx, nil, 0, fn) panicked with %#v; want %q", r, exp)
+		}
+	}()
+	ScanAvailability(context.Background(), nil, 0, nil)
+}
+
+/***************
+*  TEST UTILS  *
+***************/
+
+// roundTripFunc is an http.RoundTripper which calls itself to make requests.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// failingTransport fails requests for the profile currently associated with
+// name with testError and uses transport for all other requests.
+type failingTransport struct {
+	name      string
+	transport http.RoundTripper
+}
+
+func (ft failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if path.Base(req.URL.Path) == ft.name {
+		return nil, testError
+	}
+	return ft.transport.RoundTrip(req)
+}
+
+// atTransport uses at for requests with an "at" query parameter and now for
+// all other requests.
+type atTransport struct {
+	at, now http.RoundTripper
+}
+
+func (at atTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Query().Get("at") != "" {
+		return at.at.RoundTrip(req)
+	}
+	return at.now.RoundTrip(req)
+}
@@ -0,0 +1,61 @@
+package profile
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// profileJSON is the JSON representation of a profile in Mojang responses.
+type profileJSON struct {
+	ID     *string `json:"id"`
+	Name   *string `json:"name"`
+	Legacy bool    `json:"legacy"`
+	Demo   bool    `json:"demo"`
+}
+
+// ParseProfilesStream parses a JSON array of profiles from r, formatted like
+// the responses of the Mojang endpoint used by LoadMany, and calls fn for each
+// profile in order. Each profile is decoded only once fn has returned for the
+// previous one, so arbitrarily large arrays may be parsed using little memory,
+// e.g. when processing cached responses offline. As in the rest of this
+// package, demo profiles are skipped.
+//
+// If fn returns an error, parsing stops and the error is returned. If r
+// contains malformed JSON, the error reported by package encoding/json is
+// returned. If the JSON data isn't an array of profiles, ErrUnknownFormat is
+// returned.
+func ParseProfilesStream(r io.Reader, fn func(*Profile) error) error {
+	dec := json.NewDecoder(r)
+	if t, err := dec.Token(); err != nil {
+		return err
+	} else if t != json.Delim('[') {
+		return ErrUnknownFormat
+	}
+
+	for dec.More() {
+		var js profileJSON
+		if err := dec.Decode(&js); err != nil {
+			if _, ok := err.(*json.UnmarshalTypeError); ok {
+				return ErrUnknownFormat
+			}
+			return err
+		}
+		if js.ID == nil || js.Name == nil {
+			return ErrUnknownFormat
+		}
+		if js.Demo {
+			continue
+		}
+
+		p := &Profile{ID: *js.ID, Name: *js.Name}
+		if js.Legacy {
+			p.NameHistory = emptyHist
+		}
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+
+	_, err := dec.Token() // Closing ']'
+	return err
+}
@@ -0,0 +1,140 @@
+package profile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testParseProfilesStreamInput = [...]struct {
+	data        string
+	expProfiles []*Profile
+	expErr      error
+}{
+	{
+		data:        `[]`,
+		expProfiles: nil,
+	},
+	{
+		data: `[{"id":"cabefc91b5df4c87886a6c604da2e46f","name":"AxeLaw","legacy":true},{"id":"087cc153c3434ff7ac497de1569affa1","name":"Nergalic"},{"id":"0123456789abcdef886a6c604da2e46f","name":"demo","demo":true}]`,
+		expProfiles: []*Profile{
+			{ID: "cabefc91b5df4c87886a6c604da2e46f", Name: "AxeLaw", NameHistory: emptyHist},
+			{ID: "087cc153c3434ff7ac497de1569affa1", Name: "Nergalic"},
+		},
+	},
+	{
+		data:        `{}`,
+		expProfiles: nil,
+		expErr:      ErrUnknownFormat,
+	},
+	{
+		data: `[{"id":"087cc153c3434ff7ac497de1569affa1","name":"Nergalic"},42]`,
+		expProfiles: []*Profile{
+			{ID: "087cc153c3434ff7ac497de1569affa1", Name: "Nergalic"},
+		},
+		expErr: ErrUnknownFormat,
+	},
+	{
+		data:        `[{"id":"087cc153c3434ff7ac497de1569affa1"}]`,
+		expProfiles: nil,
+		expErr:      ErrUnknownFormat,
+	},
+	{
+		data:        `[{"id":"087cc153c3434ff7ac497de1569affa1","name":1337}]`,
+		expProfiles: nil,
+		expErr:      ErrUnknownFormat,
+	},
+}
+
+func TestParseProfilesStream(t *testing.T) {
+	for _, tc := range testParseProfilesStreamInput {
+		var ps []*Profile
+		err := ParseProfilesStream(strings.NewReader(tc.data), func(p *Profile) error {
+			ps = append(ps, p)
+			return nil
+		})
+		if !reflect.DeepEqual(ps, tc.expProfiles) || err != tc.expErr {
+			t.Errorf(
+				"ParseProfilesStream(%s, fn)\n"+
+					" was: %v, %v\n"+
+					"want: %v, %v",
+				tc.data,
+				ps, err,
+				tc.expProfiles, tc.expErr,
+			)
+		}
+	}
+}
+
+func TestParseProfilesStreamSyntaxError(t *testing.T) {
+	data := `[{"id":"087cc153c3434ff7ac497de1569affa1","name":"Nergalic"`
+	err := ParseProfilesStream(strings.NewReader(data), func(*Profile) error { return nil })
+	if err == nil || err == ErrUnknownFormat {
+		t.Errorf("ParseProfilesStream(%s, fn) returned error %v; want JSON syntax error", data, err)
+	}
+}
+
+func TestParseProfilesStreamCallbackError(t *testing.T) {
+	testError := errors.New("stop")
+	data := `[{"id":"a","name":"A"},{"id":"b","name":"B"}]`
+
+	calls := 0
+	err := ParseProfilesStream(strings.NewReader(data), func(*Profile) error {
+		calls++
+		return testError
+	})
+	if calls != 1 || err != testError {
+		t.Errorf("ParseProfilesStream(%s, fn) called fn %d times and returned error %v; want 1, %s", data, calls, err, testError)
+	}
+}
+
+func BenchmarkParseProfilesStream(b *testing.B) {
+	data := benchmarkProfiles(10000)
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ParseProfilesStream(bytes.NewReader(data), func(*Profile) error { return nil })
+	}
+}
+
+// BenchmarkParseProfilesNaive decodes the entire JSON array at once, as done
+// by LoadMany, for comparison with BenchmarkParseProfilesStream.
+func BenchmarkParseProfilesNaive(b *testing.B) {
+	data := benchmarkProfiles(10000)
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var js interface{}
+		json.NewDecoder(bytes.NewReader(data)).Decode(&js)
+		arr := js.([]interface{})
+		ps := make([]*Profile, 0, len(arr))
+		for _, m := range arr {
+			p := &Profile{}
+			if fillProfile(p, m.(map[string]interface{})) {
+				ps = append(ps, p)
+			}
+		}
+	}
+}
+
+/***************
+*  TEST UTILS  *
+***************/
+
+// benchmarkProfiles returns a JSON array of n profiles.
+func benchmarkProfiles(n int) []byte {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		fmt.Fprintf(&buf, `{"id":"%032x","name":"player%d"}`, i, i)
+	}
+	buf.WriteByte(']')
+	return buf.Bytes()
+}
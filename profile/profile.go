@@ -4,7 +4,8 @@
 //
 // Since Mojang's API historically have been inconsistent on whether demo profiles
 // are returned or not, to ensure consistency this package have been written never
-// to return those.
+// to return those. Where a demo profile is encountered instead of a profile,
+// ErrDemoProfile is returned.
 //
 // Please note that the public Mojang API is request rate limited, so if you expect
 // heavy usage you should cache the results.
@@ -14,13 +15,15 @@ package profile
 import (
 	"context"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
+	"path"
+	"sort"
 	"time"
 
-	"fmt"
-
 	"github.com/PhilipBorgesen/minecraft/internal"
+	"github.com/PhilipBorgesen/minecraft/uuid"
 )
 
 // Profile represents the profile of a Minecraft user account.
@@ -32,7 +35,7 @@ type Profile struct {
 	// NameHistory is the profile's past usernames incl. when each username
 	// stopped being used. The profile's last username is first, its original
 	// username is last. Unless explicitly loaded, NameHistory may be nil.
-	NameHistory []PastName
+	NameHistory NameHistory
 	// Properties contains the skin, model and cape used by the profile.
 	// Unless explicitly loaded, Properties may be nil.
 	Properties *Properties
@@ -45,6 +48,83 @@ func (p *Profile) String() string {
 	return p.Name
 }
 
+// AgeBucket returns a coarse estimate of the age of the profile's account, for
+// applications such as moderation dashboards which don't want exact dates. The
+// estimate is one of "< 1 month", "1-6 months", "6-12 months" or "> 1 year";
+// months are approximated as 30 days.
+//
+// The estimate is approximate at best. Mojang doesn't expose when accounts
+// were created, so the age is inferred from p.NameHistory: the account is at
+// least as old as the earliest time one of its past usernames stopped being
+// used. Hence the estimate is a lower bound, and AgeBucket returns "unknown"
+// unless p.NameHistory has been loaded and holds any past usernames. Load the
+// name history beforehand, e.g. using LoadWithNameHistory.
+func (p *Profile) AgeBucket() string {
+	if len(p.NameHistory) == 0 {
+		return "unknown"
+	}
+	earliest := p.NameHistory[0].Until
+	for _, n := range p.NameHistory[1:] {
+		if n.Until.Before(earliest) {
+			earliest = n.Until
+		}
+	}
+
+	const month = 30 * 24 * time.Hour
+	switch age := now().Sub(earliest); {
+	case age < month:
+		return "< 1 month"
+	case age < 6*month:
+		return "1-6 months"
+	case age < 12*month:
+		return "6-12 months"
+	default:
+		return "> 1 year"
+	}
+}
+
+// RecentlyRenamed reports whether the profile's current username was taken
+// into use within the last within, i.e. no earlier than
+// time.Now().Add(-within), e.g. to flag accounts which were renamed just before
+// joining a server. The boundary is inclusive; a username taken into use
+// exactly within ago counts as recently taken into use. The current username
+// was taken into use when the profile stopped using its last username, i.e. at
+// p.NameHistory[0].Until. RecentlyRenamed reports false for profiles which
+// never have been renamed, and for profiles whose name history hasn't been
+// loaded.
+func (p *Profile) RecentlyRenamed(within time.Duration) bool {
+	if len(p.NameHistory) == 0 {
+		return false
+	}
+	return now().Sub(p.NameHistory[0].Until) <= within
+}
+
+// RenameTimestamps returns the instants at which the profile's username
+// changed, sorted ascending, e.g. to correlate renames with other events. These
+// are the Until times of p.NameHistory; the username a profile was registered
+// with contributes no timestamp, as it wasn't the result of a change. Hence
+// RenameTimestamps returns nil for profiles which never have been renamed,
+// and for profiles whose name history hasn't been loaded. The returned slice
+// may be modified freely.
+func (p *Profile) RenameTimestamps() []time.Time {
+	if len(p.NameHistory) == 0 {
+		return nil
+	}
+	ts := make([]time.Time, len(p.NameHistory))
+	for i, n := range p.NameHistory {
+		ts[i] = n.Until
+	}
+	sort.Sort(chronological(ts))
+	return ts
+}
+
+// chronological sorts time instants in ascending order.
+type chronological []time.Time
+
+func (ts chronological) Len() int           { return len(ts) }
+func (ts chronological) Swap(i, j int)      { ts[i], ts[j] = ts[j], ts[i] }
+func (ts chronological) Less(i, j int) bool { return ts[i].Before(ts[j]) }
+
 // LoadNameHistory loads and returns p.NameHistory, which contains the
 // profile's past usernames. If force is true, p.NameHistory will be loaded
 // anew from the Mojang servers even though it already is present. If force
@@ -59,16 +139,18 @@ func (p *Profile) String() string {
 //
 // A profile which was loaded by LoadWithNameHistory has p.NameHistory
 // pre-loaded.
-func (p *Profile) LoadNameHistory(ctx context.Context, force bool) (hist []PastName, err error) {
+func (p *Profile) LoadNameHistory(ctx context.Context, force bool) (hist NameHistory, err error) {
+	internal.CheckContext(ctx, "profile", "Profile.LoadNameHistory")
+
 	if p.NameHistory == nil || force {
 		if p.ID == "" {
 			return p.NameHistory, ErrUnsetPlayerID
 		}
 
 		var js interface{}
-		endpoint := fmt.Sprintf(loadWithNameHistoryURL, p.ID)
+		endpoint := profileURL(loadWithNameHistoryPath, p.ID)
 
-		js, err = internal.FetchJSON(ctx, client, endpoint)
+		js, err = internal.FetchJSON(ctx, clientFor(ctx, endpoint), endpoint)
 		if err != nil {
 			return p.NameHistory, transformError(err)
 		}
@@ -76,7 +158,7 @@ func (p *Profile) LoadNameHistory(ctx context.Context, force bool) (hist []PastN
 		defer func() { // If JSON data isn't structured as expected
 			if r := recover(); r != nil {
 				hist = p.NameHistory
-				err = &url.Error{Op: "Parse", URL: endpoint, Err: internal.ErrUnknownFormat}
+				err = &url.Error{Op: "Parse", URL: endpoint, Err: ErrUnknownFormat}
 			}
 		}()
 
@@ -103,29 +185,58 @@ func (p *Profile) LoadNameHistory(ctx context.Context, force bool) (hist []PastN
 // A profile which was loaded by LoadWithProperties has p.Properties pre-loaded.
 //
 // NB! For each profile, profile properties may only be requested once per minute.
+// Use TrackPropertiesCooldown to avoid making requests which would fail.
 func (p *Profile) LoadProperties(ctx context.Context, force bool) (ps *Properties, err error) {
+	internal.CheckContext(ctx, "profile", "Profile.LoadProperties")
+	return p.loadProperties(ctx, force, true)
+}
+
+// loadProperties implements Profile.LoadProperties. If checkCooldown is false,
+// properties are requested even though the cooldown tracker, if enabled,
+// reports that the request would fail. The request is recorded regardless.
+func (p *Profile) loadProperties(ctx context.Context, force, checkCooldown bool) (ps *Properties, err error) {
 	if p.Properties == nil || force {
 		if p.ID == "" {
 			return p.Properties, ErrUnsetPlayerID
 		}
 
+		t := tracker
+		if t != nil && checkCooldown {
+			if d := t.remaining(p.ID); d > 0 {
+				return p.Properties, ErrPropertiesCooldown{d}
+			}
+		}
+
 		var js interface{}
-		endpoint := fmt.Sprintf(loadWithPropertiesURL, p.ID)
+		endpoint := sessionURL(loadWithPropertiesPath, p.ID)
 
-		js, err = internal.FetchJSON(ctx, client, endpoint)
+		js, err = internal.FetchJSON(ctx, clientFor(ctx, endpoint), endpoint)
 		if err != nil {
-			return p.Properties, transformError(err)
+			err = transformError(err)
+			if t != nil && err == ErrTooManyRequests {
+				t.record(p.ID)
+			}
+			return p.Properties, err
+		}
+		if t != nil {
+			t.record(p.ID)
 		}
 
 		defer func() { // If JSON data isn't structured as expected
 			if r := recover(); r != nil {
 				ps = p.Properties
-				err = &url.Error{Op: "Parse", URL: endpoint, Err: internal.ErrUnknownFormat}
+				err = &url.Error{Op: "Parse", URL: endpoint, Err: ErrUnknownFormat}
 			}
 		}()
 
+		// Tolerate responses without properties, e.g. from preview session
+		// servers, rather than failing to load the profile at all
 		m := js.(map[string]interface{})
-		ps, err = buildProperties(m["properties"].([]interface{}))
+		var props []interface{}
+		if v, present := m["properties"]; present {
+			props = v.([]interface{})
+		}
+		ps, err = buildProperties(props, p.ID)
 		if err != nil {
 			// Let the entire loading fail even if just property construction fails.
 			// May always be changed later if this is too drastic.
@@ -133,7 +244,7 @@ func (p *Profile) LoadProperties(ctx context.Context, force bool) (ps *Propertie
 		}
 
 		if !fillProfile(p, m) {
-			return p.Properties, ErrNoSuchProfile
+			return p.Properties, ErrDemoProfile
 		}
 
 		p.Properties = ps
@@ -159,15 +270,18 @@ type PastName struct {
 	// Prior past usernames may be consulted to determine when this username
 	// was taken into use.
 	Until time.Time
+	// Original is true if Name is the username the profile was registered
+	// with, i.e. if this is the last entry of the profile's name history.
+	Original bool
 
 	_ struct{} // Ensure PastName is constructed using named parameters.
 }
 
 // Equal reports whether p and q represents the same past username of a
-// profile, i.e. whether p.Name == q.Name and p and q were used until the same
-// time instant. Do not use == with PastName values.
+// profile, i.e. whether p.Name == q.Name, p.Original == q.Original and p and q
+// were used until the same time instant. Do not use == with PastName values.
 func (p PastName) Equal(q PastName) bool {
-	return p.Name == q.Name && p.Until.Equal(q.Until)
+	return p.Name == q.Name && p.Original == q.Original && p.Until.Equal(q.Until)
 }
 
 // String returns p.Name.
@@ -175,21 +289,202 @@ func (p PastName) String() string {
 	return p.Name
 }
 
+// FormatUUID returns the profile ID id in the canonical hyphenated 8-4-4-4-12
+// form of UUIDs, e.g. "069a79f4-44e9-4726-a5be-fca90e38aaf5", as expected by
+// many Minecraft tools. Profile IDs are undashed otherwise, such as Profile.ID.
+// If id isn't a valid profile ID, ErrInvalidID is returned.
+//
+// FormatUUID is a convenience for uuid.ToHyphenated. Like it, FormatUUID also
+// accepts hyphenated IDs and always returns lower case IDs.
+func FormatUUID(id string) (string, error) {
+	h, err := uuid.ToHyphenated(id)
+	if err != nil {
+		return "", ErrInvalidID
+	}
+	return h, nil
+}
+
+// NormalizeUUID returns the profile ID id in the undashed lower case form used
+// by this package, e.g. "069a79f444e94726a5befca90e38aaf5", such that IDs in
+// either form may be passed to functions like LoadByID. id may be undashed or
+// hyphenated, in upper or lower case. If id isn't a valid profile ID,
+// ErrInvalidID is returned.
+//
+// NormalizeUUID is a convenience for uuid.ToUndashed.
+func NormalizeUUID(id string) (string, error) {
+	u, err := uuid.ToUndashed(id)
+	if err != nil {
+		return "", ErrInvalidID
+	}
+	return u, nil
+}
+
 // Properties contains additional information associated with a Profile.
 type Properties struct {
 	// SkinURL is an URL to the profile's custom skin texture.
 	// If SkinURL == "", no skin texture has been set and the profile uses the
-	// default skin for Model.
+	// default skin for Model. Use SkinURLParsed to get it as a *url.URL.
 	SkinURL string
 	// CapeURL is an URL to the profile's cape texture.
-	// If CapeURL == "", no cape is associated with the profile.
+	// If CapeURL == "", no cape is associated with the profile. Use
+	// CapeURLParsed to get it as a *url.URL.
 	CapeURL string
 	// Model is the profile's player model type.
 	Model Model
+	// Timestamp is when Mojang's servers generated the textures information
+	// above. Since Mojang caches this information, it may be older than the
+	// time the properties were loaded. If unknown, Timestamp is the zero time.
+	Timestamp time.Time
+
+	raw []RawProperty
 
 	_ struct{} // Ensure Properties is constructed using named parameters.
 }
 
+// RawProperty is a profile property exactly as reported by Mojang.
+type RawProperty struct {
+	// Name is the name of the property, e.g. "textures".
+	Name string
+	// Value is the base64 encoded value of the property.
+	Value string
+	// Signature is the base64 encoded signature of Value, if signed.
+	Signature string
+}
+
+// SkinURLParsed returns p.SkinURL parsed as a URL, e.g. to direct requests for
+// the skin through another host, and true. If p.SkinURL is empty or malformed,
+// SkinURLParsed returns nil and false. Each call returns a new URL which the
+// client may modify freely.
+func (p *Properties) SkinURLParsed() (*url.URL, bool) {
+	return parseTextureURL(p.SkinURL)
+}
+
+// CapeURLParsed returns p.CapeURL parsed as a URL and true, as described for
+// SkinURLParsed. If p.CapeURL is empty or malformed, CapeURLParsed returns nil
+// and false.
+func (p *Properties) CapeURLParsed() (*url.URL, bool) {
+	return parseTextureURL(p.CapeURL)
+}
+
+func parseTextureURL(s string) (*url.URL, bool) {
+	if !isTextureURL(s) {
+		return nil, false
+	}
+	u, _ := url.Parse(s) // Error only occurs if s is malformed
+	return u, true
+}
+
+// OlderThan reports whether the textures information of p is older than d,
+// according to p.Timestamp. Use OlderThan to avoid requesting properties anew
+// while Mojang would serve the same cached information. If p.Timestamp is
+// unknown, OlderThan reports true.
+func (p *Properties) OlderThan(d time.Duration) bool {
+	return p.Timestamp.IsZero() || now().Sub(p.Timestamp) > d
+}
+
+// IsDefault reports whether p describes the default appearance of its model,
+// i.e. whether the profile has neither a custom skin nor a cape. Which default
+// skin is used is determined by p.Model.
+func (p *Properties) IsDefault() bool {
+	return p.SkinURL == "" && p.CapeURL == ""
+}
+
+// SameSkin reports whether p and other use the same custom skin, e.g. to detect
+// accounts sharing an identical skin. Since skin textures are content-addressed,
+// skins are compared by the texture hash ending their URLs, so the same skin is
+// recognized even if served from different hosts. Properties without a custom
+// skin are considered to have the same skin, regardless of their model, while a
+// custom skin never equals the lack of one. SameSkin reports false if other is
+// nil.
+func (p *Properties) SameSkin(other *Properties) bool {
+	if other == nil {
+		return false
+	}
+	return textureHash(p.SkinURL) == textureHash(other.SkinURL)
+}
+
+// textureHash returns the hash of the texture at textureURL, i.e. the last
+// element of its path. If textureURL is malformed, textureURL is returned.
+func textureHash(textureURL string) string {
+	u, ok := parseTextureURL(textureURL)
+	if !ok {
+		return textureURL
+	}
+	return path.Base(u.Path)
+}
+
+// ProfileDiff reports what changed between two snapshots of the same profile,
+// as determined by Diff.
+type ProfileDiff struct {
+	Renamed      bool // Whether the username changed.
+	SkinChanged  bool // Whether the custom skin changed, was set or was reset.
+	CapeChanged  bool // Whether the cape changed, was added or was removed.
+	ModelChanged bool // Whether the player model changed.
+
+	_ struct{} // Ensure ProfileDiff is constructed using named parameters.
+}
+
+// Changed reports whether any change was detected.
+func (d ProfileDiff) Changed() bool {
+	return d.Renamed || d.SkinChanged || d.CapeChanged || d.ModelChanged
+}
+
+// Diff reports what changed between a and b, two snapshots of the same profile
+// loaded at different times, e.g. to log renames and new skins of monitored
+// profiles. Skins and capes are compared by texture hash like SameSkin does,
+// so a texture served from another URL isn't reported as changed.
+//
+// Changes of skin, cape and model can only be detected if the properties of
+// both a and b are loaded. If a.Properties or b.Properties is nil, they are
+// reported unchanged. Diff doesn't check that a and b have the same ID.
+func Diff(a, b *Profile) ProfileDiff {
+	d := ProfileDiff{Renamed: a.Name != b.Name}
+	if pa, pb := a.Properties, b.Properties; pa != nil && pb != nil {
+		d.SkinChanged = !pa.SameSkin(pb)
+		d.CapeChanged = textureHash(pa.CapeURL) != textureHash(pb.CapeURL)
+		d.ModelChanged = pa.Model != pb.Model
+	}
+	return d
+}
+
+// Raw returns every property which p was loaded from, incl. properties this
+// package doesn't know how to parse, in the order reported by Mojang. Raw
+// returns nil when no raw properties are known, e.g. if p was constructed
+// manually or loaded from a response without properties.
+//
+// Raw allows clients to parse properties introduced by Mojang after the
+// release of this package without waiting for it to be updated.
+func (p *Properties) Raw() []RawProperty {
+	if p.raw == nil {
+		return nil
+	}
+	raw := make([]RawProperty, len(p.raw))
+	copy(raw, p.raw)
+	return raw
+}
+
+// RegisterPropertyParser registers parse as the parser of the profile property
+// called name, replacing any parser previously registered for the property.
+// When profile properties are loaded, parse is called with the base64 encoded
+// value of the property and the Properties being populated. If parse returns
+// an error, the loading fails. If parse is nil, the property called name will
+// no longer be parsed, but it remains available through Properties.Raw.
+//
+// By default only the "textures" property is parsed, populating SkinURL,
+// CapeURL and Model.
+//
+// RegisterPropertyParser is not safe for concurrent use. Register all parsers
+// before loading any profile properties.
+func RegisterPropertyParser(name string, parse func(value string, p *Properties) error) {
+	if parse == nil {
+		delete(propertyPopulators, name)
+	} else {
+		propertyPopulators[name] = func(value, _ string, p *Properties) error {
+			return parse(value, p)
+		}
+	}
+}
+
 // SkinReader is a convenience method for retrieving the skin texture at
 // p.SkinURL. ctx must be non-nil. If p.SkinURL == "", the default texture for
 // p.Model will be attempted to be retrieved instead.
@@ -197,6 +492,8 @@ type Properties struct {
 // It is the client's responsibility to close the ReadCloser. When an error is
 // returned, ReadCloser is nil.
 func (p *Properties) SkinReader(ctx context.Context) (io.ReadCloser, error) {
+	internal.CheckContext(ctx, "profile", "Properties.SkinReader")
+
 	url := p.SkinURL
 	if url == "" {
 		url = p.Model.defaultSkinURL()
@@ -207,6 +504,19 @@ func (p *Properties) SkinReader(ctx context.Context) (io.ReadCloser, error) {
 	return loadTexture(ctx, url)
 }
 
+// FetchSkin downloads the skin texture at p.SkinURL and returns its PNG data,
+// e.g. to save it to a file. ctx must be non-nil. Unlike SkinReader, FetchSkin
+// doesn't fall back to the default texture for p.Model; if p.SkinURL == "",
+// ErrNoSkin is returned as error.
+func (p *Properties) FetchSkin(ctx context.Context) ([]byte, error) {
+	internal.CheckContext(ctx, "profile", "Properties.FetchSkin")
+
+	if p.SkinURL == "" {
+		return nil, ErrNoSkin
+	}
+	return fetchTexture(ctx, p.SkinURL)
+}
+
 // CapeReader is a convenience method for retrieving the cape texture at
 // p.CapeURL. ctx must be non-nil. If p.CapeURL == "", ErrNoCape is returned as
 // error.
@@ -214,12 +524,40 @@ func (p *Properties) SkinReader(ctx context.Context) (io.ReadCloser, error) {
 // It is the client's responsibility to close the ReadCloser. When an error is
 // returned, ReadCloser is nil.
 func (p *Properties) CapeReader(ctx context.Context) (io.ReadCloser, error) {
+	internal.CheckContext(ctx, "profile", "Properties.CapeReader")
+
 	if p.CapeURL == "" {
 		return nil, ErrNoCape
 	}
 	return loadTexture(ctx, p.CapeURL)
 }
 
+// FetchCape downloads the cape texture at p.CapeURL and returns its PNG data,
+// e.g. to render the cape. ctx must be non-nil. If p.CapeURL == "", ErrNoCape
+// is returned as error.
+func (p *Properties) FetchCape(ctx context.Context) ([]byte, error) {
+	internal.CheckContext(ctx, "profile", "Properties.FetchCape")
+
+	if p.CapeURL == "" {
+		return nil, ErrNoCape
+	}
+	return fetchTexture(ctx, p.CapeURL)
+}
+
+func fetchTexture(ctx context.Context, endpoint string) ([]byte, error) {
+	rc, err := loadTexture(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	bs, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+	return bs, nil
+}
+
 func loadTexture(ctx context.Context, endpoint string) (io.ReadCloser, error) {
 	req, err := http.NewRequest("GET", endpoint, nil)
 	if err != nil {
@@ -227,12 +565,15 @@ func loadTexture(ctx context.Context, endpoint string) (io.ReadCloser, error) {
 	}
 	req = req.WithContext(ctx)
 
-	resp, err := client.Do(req)
+	resp, err := clientFor(ctx, endpoint).Do(req)
 	if err != nil {
 		return nil, err
 	}
 
 	if resp.StatusCode != 200 {
+		// Read the body to EOF so the connection can be reused
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
 		err = &url.Error{
 			Op:  "Get",
 			URL: endpoint,
@@ -267,6 +608,20 @@ func (m Model) String() string {
 	}
 }
 
+// DefaultModelForUUID returns the model of the default skin of the profile
+// identified by id, i.e. the model used by the profile if it has no custom
+// skin. Unlike Properties.Model, which requires loading the profile's
+// properties, the default model is derived from id alone without contacting
+// Mojang's servers. id may be undashed or hyphenated. If id isn't a valid
+// profile ID, ErrInvalidID is returned.
+func DefaultModelForUUID(id string) (Model, error) {
+	u, err := uuid.ToUndashed(id)
+	if err != nil {
+		return Steve, ErrInvalidID
+	}
+	return defaultModel(u), nil
+}
+
 // defaultSkinURL returns a URL to the default skin of m
 func (m Model) defaultSkinURL() string {
 	switch m {
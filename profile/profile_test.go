@@ -28,6 +28,106 @@ func TestProfile_String(t *testing.T) {
 	}
 }
 
+var testProfileAgeBucketInput = [...]struct {
+	hist      []PastName
+	expBucket string
+}{
+	{hist: nil, expBucket: "unknown"},
+	{hist: []PastName{}, expBucket: "unknown"},
+	{hist: []PastName{{Until: time.Unix(1e8-29*86400, 0)}}, expBucket: "< 1 month"},
+	{hist: []PastName{{Until: time.Unix(1e8-30*86400, 0)}}, expBucket: "1-6 months"},
+	{hist: []PastName{{Until: time.Unix(1e8-200*86400, 0)}}, expBucket: "6-12 months"},
+	{hist: []PastName{{Until: time.Unix(1e8-360*86400, 0)}}, expBucket: "> 1 year"},
+	{ // Earliest time counts no matter its position
+		hist: []PastName{
+			{Until: time.Unix(1e8-86400, 0)},
+			{Until: time.Unix(1e8-200*86400, 0)},
+			{Until: time.Unix(1e8-2*86400, 0)},
+		},
+		expBucket: "6-12 months",
+	},
+}
+
+func TestProfile_AgeBucket(t *testing.T) {
+	origNow := now
+	defer func() { now = origNow }()
+	now = func() time.Time { return time.Unix(1e8, 0) }
+
+	for _, tc := range testProfileAgeBucketInput {
+		pr := &Profile{NameHistory: tc.hist}
+		if b := pr.AgeBucket(); b != tc.expBucket {
+			t.Errorf("%#v.AgeBucket() was %q; want %q", pr, b, tc.expBucket)
+		}
+	}
+}
+
+var testProfileRecentlyRenamedInput = [...]struct {
+	hist       []PastName
+	within     time.Duration
+	expRenamed bool
+}{
+	{hist: nil, within: time.Hour, expRenamed: false},
+	{hist: []PastName{}, within: time.Hour, expRenamed: false},
+	{hist: []PastName{{Until: time.Unix(1e8-3600, 0)}}, within: time.Hour, expRenamed: true}, // Inclusive boundary
+	{hist: []PastName{{Until: time.Unix(1e8-3601, 999999999)}}, within: time.Hour, expRenamed: false},
+	{hist: []PastName{{Until: time.Unix(1e8-3601, 0)}}, within: time.Hour, expRenamed: false},
+	{ // Only the latest rename counts
+		hist: []PastName{
+			{Until: time.Unix(1e8-86400, 0)},
+			{Until: time.Unix(1e8-60, 0)},
+		},
+		within:     time.Hour,
+		expRenamed: false,
+	},
+}
+
+func TestProfile_RecentlyRenamed(t *testing.T) {
+	origNow := now
+	defer func() { now = origNow }()
+	now = func() time.Time { return time.Unix(1e8, 0) }
+
+	for _, tc := range testProfileRecentlyRenamedInput {
+		pr := &Profile{NameHistory: tc.hist}
+		if r := pr.RecentlyRenamed(tc.within); r != tc.expRenamed {
+			t.Errorf("%#v.RecentlyRenamed(%s) was %t; want %t", pr, tc.within, r, tc.expRenamed)
+		}
+	}
+}
+
+var testProfileRenameTimestampsInput = [...]struct {
+	hist  []PastName
+	expTs []time.Time
+}{
+	{hist: nil, expTs: nil},
+	{hist: []PastName{}, expTs: nil},
+	{
+		hist:  []PastName{{Name: "GeneralSezuan", Until: msToTime(1423047705000), Original: true}},
+		expTs: []time.Time{msToTime(1423047705000)},
+	},
+	{
+		hist: []PastName{
+			{Name: "C", Until: msToTime(1500000000000)},
+			{Name: "B", Until: msToTime(1423047705000)},
+			{Name: "A", Until: msToTime(1450000000000), Original: true}, // Out of order
+		},
+		expTs: []time.Time{msToTime(1423047705000), msToTime(1450000000000), msToTime(1500000000000)},
+	},
+}
+
+func TestProfile_RenameTimestamps(t *testing.T) {
+	for _, tc := range testProfileRenameTimestampsInput {
+		pr := &Profile{NameHistory: tc.hist}
+		if ts := pr.RenameTimestamps(); !reflect.DeepEqual(ts, tc.expTs) {
+			t.Errorf(
+				"%#v.RenameTimestamps()\n"+
+					" was: %v\n"+
+					"want: %v",
+				pr, ts, tc.expTs,
+			)
+		}
+	}
+}
+
 var testPastNameEqualInput = [...]struct {
 	pn1    PastName
 	pn2    PastName
@@ -58,6 +158,11 @@ var testPastNameEqualInput = [...]struct {
 		pn2:    PastName{Until: time.Unix(23, 42).In(time.FixedZone("Zone B", 7))},
 		equals: true,
 	},
+	{
+		pn1:    PastName{Name: "ABC", Until: time.Unix(52, 37), Original: true},
+		pn2:    PastName{Name: "ABC", Until: time.Unix(52, 37), Original: true},
+		equals: true,
+	},
 
 	{
 		pn1:    PastName{Name: "ABC", Until: time.Unix(52, 37)},
@@ -74,6 +179,11 @@ var testPastNameEqualInput = [...]struct {
 		pn2:    PastName{Name: "ABC", Until: time.Unix(23, 37)},
 		equals: false,
 	},
+	{
+		pn1:    PastName{Name: "ABC", Until: time.Unix(52, 37), Original: true},
+		pn2:    PastName{Name: "ABC", Until: time.Unix(52, 37)},
+		equals: false,
+	},
 }
 
 func TestPastName_Equal(t *testing.T) {
@@ -131,12 +241,33 @@ func TestModel_String(t *testing.T) {
 	}
 }
 
+var testDefaultModelForUUIDInput = [...]struct {
+	id       string
+	expModel Model
+	expErr   error
+}{
+	{id: "087cc153c3434ff7ac497de1569affa1", expModel: Steve},
+	{id: "3fe136c0cd434f7783fc94b9b86eed6d", expModel: Alex},
+	{id: "3FE136C0-CD43-4F77-83FC-94B9B86EED6D", expModel: Alex},
+	{id: "", expModel: Steve, expErr: ErrInvalidID},
+	{id: "3fe136c0cd434f7783fc94b9b86eed6", expModel: Steve, expErr: ErrInvalidID},
+	{id: "!BAD_ID!f3fd461daff5086b22154bce", expModel: Steve, expErr: ErrInvalidID},
+}
+
+func TestDefaultModelForUUID(t *testing.T) {
+	for _, tc := range testDefaultModelForUUIDInput {
+		if model, err := DefaultModelForUUID(tc.id); model != tc.expModel || err != tc.expErr {
+			t.Errorf("DefaultModelForUUID(%q) was %s, %v; want %s, %v", tc.id, model, err, tc.expModel, tc.expErr)
+		}
+	}
+}
+
 var testProfileLoadNameHistoryInput = [...]struct {
 	profile    *Profile
 	force      bool
 	transport  http.RoundTripper
 	expProfile *Profile
-	expHist    []PastName
+	expHist    NameHistory
 	expErr     error
 }{
 	{ // Load when history is unknown
@@ -150,15 +281,17 @@ var testProfileLoadNameHistoryInput = [...]struct {
 			ID:   "087cc153c3434ff7ac497de1569affa1",
 			NameHistory: []PastName{
 				{
-					Name:  "GeneralSezuan",
-					Until: msToTime(1423047705000),
+					Name:     "GeneralSezuan",
+					Until:    msToTime(1423047705000),
+					Original: true,
 				},
 			},
 		},
 		expHist: []PastName{
 			{
-				Name:  "GeneralSezuan",
-				Until: msToTime(1423047705000),
+				Name:     "GeneralSezuan",
+				Until:    msToTime(1423047705000),
+				Original: true,
 			},
 		},
 		expErr: nil,
@@ -210,15 +343,17 @@ var testProfileLoadNameHistoryInput = [...]struct {
 			ID:   "087cc153c3434ff7ac497de1569affa1",
 			NameHistory: []PastName{
 				{
-					Name:  "GeneralSezuan",
-					Until: msToTime(1423047705000),
+					Name:     "GeneralSezuan",
+					Until:    msToTime(1423047705000),
+					Original: true,
 				},
 			},
 		},
 		expHist: []PastName{
 			{
-				Name:  "GeneralSezuan",
-				Until: msToTime(1423047705000),
+				Name:     "GeneralSezuan",
+				Until:    msToTime(1423047705000),
+				Original: true,
 			},
 		},
 		expErr: nil,
@@ -320,15 +455,19 @@ var testProfileLoadPropertiesInput = [...]struct {
 			Name: "Nergalic",
 			ID:   "087cc153c3434ff7ac497de1569affa1",
 			Properties: &Properties{
-				SkinURL: "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
-				CapeURL: "",
-				Model:   Steve,
+				SkinURL:   "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
+				CapeURL:   "",
+				Model:     Steve,
+				Timestamp: nergalicTimestamp,
+				raw:       nergalicRaw,
 			},
 		},
 		expProps: &Properties{
-			SkinURL: "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
-			CapeURL: "",
-			Model:   Steve,
+			SkinURL:   "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
+			CapeURL:   "",
+			Model:     Steve,
+			Timestamp: nergalicTimestamp,
+			raw:       nergalicRaw,
 		},
 		expErr: nil,
 	},
@@ -374,15 +513,19 @@ var testProfileLoadPropertiesInput = [...]struct {
 			Name: "Nergalic",
 			ID:   "087cc153c3434ff7ac497de1569affa1",
 			Properties: &Properties{
-				SkinURL: "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
-				CapeURL: "",
-				Model:   Steve,
+				SkinURL:   "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
+				CapeURL:   "",
+				Model:     Steve,
+				Timestamp: nergalicTimestamp,
+				raw:       nergalicRaw,
 			},
 		},
 		expProps: &Properties{
-			SkinURL: "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
-			CapeURL: "",
-			Model:   Steve,
+			SkinURL:   "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
+			CapeURL:   "",
+			Model:     Steve,
+			Timestamp: nergalicTimestamp,
+			raw:       nergalicRaw,
 		},
 		expErr: nil,
 	},
@@ -412,12 +555,12 @@ var testProfileLoadPropertiesInput = [...]struct {
 		expErr: ErrUnsetPlayerID,
 	},
 	{ // Unforced: Old properties returned (but not updated) on error
-		profile:    &Profile{ID: "fictiveDemo"}, // Doesn't exist
+		profile:    &Profile{ID: "fictiveDemo"}, // Demo profile
 		force:      false,
 		transport:  http.NewFileTransport(http.Dir("testdata")),
 		expProfile: &Profile{ID: "fictiveDemo"},
 		expProps:   nil,
-		expErr:     ErrNoSuchProfile,
+		expErr:     ErrDemoProfile,
 	},
 	{
 		profile:    &Profile{ID: "noSkinAndBadUUID"},
@@ -441,6 +584,28 @@ var testProfileLoadPropertiesInput = [...]struct {
 			Err: base64.CorruptInputError(0),
 		},
 	},
+	{ // Responses without properties, e.g. from preview session servers
+		profile:   &Profile{ID: "087cc153c3434ff7ac497de1569affa1"},
+		transport: responseTransport{status: 200, body: `{"id":"087cc153c3434ff7ac497de1569affa1","name":"Nergalic","profileActions":[]}`},
+		expProfile: &Profile{
+			ID:         "087cc153c3434ff7ac497de1569affa1",
+			Name:       "Nergalic",
+			Properties: &Properties{},
+		},
+		expProps: &Properties{},
+		expErr:   nil,
+	},
+	{ // Properties of the wrong type aren't mistaken for missing properties
+		profile:    &Profile{ID: "087cc153c3434ff7ac497de1569affa1"},
+		transport:  responseTransport{status: 200, body: `{"id":"087cc153c3434ff7ac497de1569affa1","name":"Nergalic","properties":"x"}`},
+		expProfile: &Profile{ID: "087cc153c3434ff7ac497de1569affa1"},
+		expProps:   nil,
+		expErr: &url.Error{
+			Op:  "Parse",
+			URL: "https://sessionserver.mojang.com/session/minecraft/profile/087cc153c3434ff7ac497de1569affa1",
+			Err: internal.ErrUnknownFormat,
+		},
+	},
 	{
 		profile: &Profile{ID: "tooManyRequests"},
 		transport: statusOverrideTransport{
@@ -492,6 +657,247 @@ func TestProfile_LoadPropertiesContextUsed(t *testing.T) {
 	}
 }
 
+var testPropertiesIsDefaultInput = [...]struct {
+	props  *Properties
+	expDef bool
+}{
+	{props: &Properties{}, expDef: true},
+	{props: &Properties{Model: Alex}, expDef: true},
+	{props: &Properties{SkinURL: "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e"}, expDef: false},
+	{props: &Properties{CapeURL: "http://textures.minecraft.net/texture/ec80a225b145c812a6ef1ca29af0f3ebf02163874d1a66e53bac99965225e0"}, expDef: false},
+}
+
+func TestProperties_IsDefault(t *testing.T) {
+	for _, tc := range testPropertiesIsDefaultInput {
+		if def := tc.props.IsDefault(); def != tc.expDef {
+			t.Errorf("%#v.IsDefault() = %t; want %t", tc.props, def, tc.expDef)
+		}
+	}
+}
+
+var testPropertiesSameSkinInput = [...]struct {
+	props   *Properties
+	other   *Properties
+	expSame bool
+}{
+	{props: &Properties{}, other: nil, expSame: false},
+	{props: &Properties{}, other: &Properties{}, expSame: true},
+	{props: &Properties{Model: Steve}, other: &Properties{Model: Alex}, expSame: true},
+	{
+		props:   &Properties{SkinURL: "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e"},
+		other:   &Properties{},
+		expSame: false,
+	},
+	{
+		props:   &Properties{},
+		other:   &Properties{SkinURL: "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e"},
+		expSame: false,
+	},
+	{
+		props:   &Properties{SkinURL: "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e"},
+		other:   &Properties{SkinURL: "https://cdn.example.com/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e", Model: Alex},
+		expSame: true,
+	},
+	{
+		props:   &Properties{SkinURL: "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e"},
+		other:   &Properties{SkinURL: "http://textures.minecraft.net/texture/ec80a225b145c812a6ef1ca29af0f3ebf02163874d1a66e53bac99965225e0"},
+		expSame: false,
+	},
+	{props: &Properties{SkinURL: "http://%zz"}, other: &Properties{SkinURL: "http://%zz"}, expSame: true},
+}
+
+func TestProperties_SameSkin(t *testing.T) {
+	for _, tc := range testPropertiesSameSkinInput {
+		if same := tc.props.SameSkin(tc.other); same != tc.expSame {
+			t.Errorf("%#v.SameSkin(%#v) = %t; want %t", tc.props, tc.other, same, tc.expSame)
+		}
+	}
+}
+
+var testDiffInput = [...]struct {
+	a, b    *Profile
+	expDiff ProfileDiff
+}{
+	{
+		a:       &Profile{ID: "087cc153c3434ff7ac497de1569affa1", Name: "Nergalic"},
+		b:       &Profile{ID: "087cc153c3434ff7ac497de1569affa1", Name: "Nergalic"},
+		expDiff: ProfileDiff{},
+	},
+	{
+		a:       &Profile{Name: "GeneralSezuan"},
+		b:       &Profile{Name: "Nergalic"},
+		expDiff: ProfileDiff{Renamed: true},
+	},
+	{ // Capitalization changes are renames too
+		a:       &Profile{Name: "nergalic"},
+		b:       &Profile{Name: "Nergalic"},
+		expDiff: ProfileDiff{Renamed: true},
+	},
+	{ // Properties of only one snapshot are loaded
+		a:       &Profile{Name: "Nergalic"},
+		b:       &Profile{Name: "Nergalic", Properties: &Properties{SkinURL: "http://textures.minecraft.net/texture/5b40", Model: Alex}},
+		expDiff: ProfileDiff{},
+	},
+	{
+		a: &Profile{Name: "Nergalic", Properties: &Properties{
+			SkinURL: "http://textures.minecraft.net/texture/5b40",
+			CapeURL: "http://textures.minecraft.net/texture/ec80",
+		}},
+		b: &Profile{Name: "Nergalic", Properties: &Properties{
+			SkinURL: "https://cdn.example.com/texture/5b40",
+			CapeURL: "https://cdn.example.com/texture/ec80",
+		}},
+		expDiff: ProfileDiff{},
+	},
+	{
+		a: &Profile{Name: "GeneralSezuan", Properties: &Properties{
+			SkinURL: "http://textures.minecraft.net/texture/5b40",
+			CapeURL: "http://textures.minecraft.net/texture/ec80",
+			Model:   Steve,
+		}},
+		b: &Profile{Name: "Nergalic", Properties: &Properties{
+			SkinURL: "http://textures.minecraft.net/texture/6c51",
+			Model:   Alex,
+		}},
+		expDiff: ProfileDiff{Renamed: true, SkinChanged: true, CapeChanged: true, ModelChanged: true},
+	},
+	{ // Skin reset to the default skin
+		a:       &Profile{Properties: &Properties{SkinURL: "http://textures.minecraft.net/texture/5b40"}},
+		b:       &Profile{Properties: &Properties{}},
+		expDiff: ProfileDiff{SkinChanged: true},
+	},
+}
+
+func TestDiff(t *testing.T) {
+	for _, tc := range testDiffInput {
+		d := Diff(tc.a, tc.b)
+		if d != tc.expDiff || d.Changed() != (tc.expDiff != ProfileDiff{}) {
+			t.Errorf(
+				"Diff(%#v, %#v)\n"+
+					" was: %+v, Changed() = %t\n"+
+					"want: %+v",
+				tc.a, tc.b, d, d.Changed(), tc.expDiff,
+			)
+		}
+	}
+}
+
+var testPropertiesURLParsedInput = [...]struct {
+	url    string
+	expURL *url.URL
+	expOK  bool
+}{
+	{url: "", expURL: nil, expOK: false},
+	{url: "textures.minecraft.net/texture/5b40", expURL: nil, expOK: false},
+	{url: "http://%zz", expURL: nil, expOK: false},
+	{
+		url:    "http://textures.minecraft.net/texture/5b40",
+		expURL: &url.URL{Scheme: "http", Host: "textures.minecraft.net", Path: "/texture/5b40"},
+		expOK:  true,
+	},
+}
+
+func TestProperties_URLParsed(t *testing.T) {
+	for _, tc := range testPropertiesURLParsedInput {
+		props := &Properties{SkinURL: tc.url, CapeURL: tc.url}
+		if u, ok := props.SkinURLParsed(); !reflect.DeepEqual(u, tc.expURL) || ok != tc.expOK {
+			t.Errorf("Properties{SkinURL: %q}.SkinURLParsed() was %#v, %t; want %#v, %t", tc.url, u, ok, tc.expURL, tc.expOK)
+		}
+		if u, ok := props.CapeURLParsed(); !reflect.DeepEqual(u, tc.expURL) || ok != tc.expOK {
+			t.Errorf("Properties{CapeURL: %q}.CapeURLParsed() was %#v, %t; want %#v, %t", tc.url, u, ok, tc.expURL, tc.expOK)
+		}
+	}
+
+	props := &Properties{SkinURL: "http://textures.minecraft.net/texture/5b40"}
+	u, _ := props.SkinURLParsed()
+	u.Host = "cdn.example.com"
+	if u, _ := props.SkinURLParsed(); u.Host != "textures.minecraft.net" {
+		t.Errorf("Modifying URL returned by SkinURLParsed affected later calls; host was %q", u.Host)
+	}
+}
+
+var testPropertiesOlderThanInput = [...]struct {
+	timestamp time.Time
+	d         time.Duration
+	expOlder  bool
+}{
+	{timestamp: time.Time{}, d: time.Hour, expOlder: true},
+	{timestamp: time.Unix(1000, 0), d: time.Minute, expOlder: false},
+	{timestamp: time.Unix(1000, 0), d: 100 * time.Second, expOlder: false},
+	{timestamp: time.Unix(900, 0), d: time.Minute, expOlder: true},
+	{timestamp: time.Unix(1100, 0), d: 0, expOlder: false},
+}
+
+func TestProperties_OlderThan(t *testing.T) {
+	origNow := now
+	defer func() { now = origNow }()
+	now = func() time.Time { return time.Unix(1000, 0) }
+
+	for _, tc := range testPropertiesOlderThanInput {
+		props := &Properties{Timestamp: tc.timestamp}
+		if older := props.OlderThan(tc.d); older != tc.expOlder {
+			t.Errorf("Properties{Timestamp: %s}.OlderThan(%s) = %t; want %t", tc.timestamp, tc.d, older, tc.expOlder)
+		}
+	}
+}
+
+func TestProperties_Raw(t *testing.T) {
+	if raw := (&Properties{}).Raw(); raw != nil {
+		t.Errorf("Properties{}.Raw() was %#v; want nil", raw)
+	}
+
+	props := &Properties{raw: []RawProperty{{Name: "textures", Value: "dummy"}}}
+	raw := props.Raw()
+	if exp := props.raw; !reflect.DeepEqual(raw, exp) {
+		t.Errorf("%#v.Raw() was %#v; want %#v", props, raw, exp)
+	}
+
+	raw[0].Value = "modified"
+	if v := props.raw[0].Value; v != "dummy" {
+		t.Errorf("modifying result of Raw() modified Properties; Value was %q, want %q", v, "dummy")
+	}
+}
+
+var testUUIDFormInput = [...]struct {
+	id            string
+	expFormatted  string
+	expNormalized string
+	expErr        error
+}{
+	{
+		id:            "069a79f444e94726a5befca90e38aaf5",
+		expFormatted:  "069a79f4-44e9-4726-a5be-fca90e38aaf5",
+		expNormalized: "069a79f444e94726a5befca90e38aaf5",
+	},
+	{
+		id:            "069A79F4-44E9-4726-A5BE-FCA90E38AAF5",
+		expFormatted:  "069a79f4-44e9-4726-a5be-fca90e38aaf5",
+		expNormalized: "069a79f444e94726a5befca90e38aaf5",
+	},
+	{id: "", expErr: ErrInvalidID},
+	{id: "069a79f444e94726a5befca90e38aaf", expErr: ErrInvalidID},
+	{id: "069a79f444e94726a5befca90e38aafg", expErr: ErrInvalidID},
+	{id: "069a79f4-44e94726-a5be-fca90e38aaf5", expErr: ErrInvalidID},
+}
+
+func TestFormatUUID(t *testing.T) {
+	for _, tc := range testUUIDFormInput {
+		res, err := FormatUUID(tc.id)
+		if res != tc.expFormatted || err != tc.expErr {
+			t.Errorf("FormatUUID(%q) was %q, %s; want %q, %s", tc.id, res, p(err), tc.expFormatted, p(tc.expErr))
+		}
+	}
+}
+
+func TestNormalizeUUID(t *testing.T) {
+	for _, tc := range testUUIDFormInput {
+		res, err := NormalizeUUID(tc.id)
+		if res != tc.expNormalized || err != tc.expErr {
+			t.Errorf("NormalizeUUID(%q) was %q, %s; want %q, %s", tc.id, res, p(err), tc.expNormalized, p(tc.expErr))
+		}
+	}
+}
+
 var testPropertiesSkinReaderInput = [...]struct {
 	props      *Properties
 	transport  http.RoundTripper
@@ -614,6 +1020,93 @@ func TestProperties_SkinReaderContextUsed(t *testing.T) {
 	}
 }
 
+var testPropertiesFetchSkinInput = [...]struct {
+	props      *Properties
+	transport  http.RoundTripper
+	expTexture []byte
+	expErr     error
+}{
+	{
+		props: &Properties{
+			SkinURL: "",
+			Model:   Alex,
+		},
+		transport:  nil,
+		expTexture: nil,
+		expErr:     ErrNoSkin,
+	},
+	{
+		props: &Properties{
+			SkinURL: "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
+		},
+		transport: http.NewFileTransport(http.Dir("testdata")),
+		expTexture: (func() []byte {
+			b, _ := ioutil.ReadFile("testdata/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e")
+			return b
+		})(),
+	},
+	{
+		props: &Properties{
+			SkinURL: alexSkinURL,
+		},
+		transport: errorTransport{testError},
+		expErr: &url.Error{
+			Op:  "Get",
+			URL: alexSkinURL,
+			Err: testError,
+		},
+	},
+	{
+		props: &Properties{
+			SkinURL: "http://example.com/does/not/exist.png",
+		},
+		transport: http.NewFileTransport(http.Dir("testdata")),
+		expErr: &url.Error{
+			Op:  "Get",
+			URL: "http://example.com/does/not/exist.png",
+			Err: &internal.FailedRequestError{StatusCode: 404},
+		},
+	},
+}
+
+func TestProperties_FetchSkin(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	for _, tc := range testPropertiesFetchSkinInput {
+		client.Transport = tc.transport
+
+		texture, err := tc.props.FetchSkin(context.Background())
+		if !reflect.DeepEqual(texture, tc.expTexture) || !reflect.DeepEqual(err, tc.expErr) {
+			t.Errorf(
+				"%#v.FetchSkin(ctx)\n"+
+					" was: %#v, %s\n"+
+					"want: %#v, %s",
+				tc.props,
+				texture, p(err),
+				tc.expTexture, p(tc.expErr),
+			)
+		}
+	}
+}
+
+func TestProperties_FetchSkinContextUsed(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	ctx := context.WithValue(context.Background(), dummy, nil)
+	ct := CtxStoreTransport{}
+
+	client.Transport = &ct
+
+	props := Properties{SkinURL: alexSkinURL}
+	props.FetchSkin(ctx)
+
+	if ct.Context != ctx {
+		t.Error("Properties{SkinURL: ...}.FetchSkin(ctx) didn't pass context to underlying http.Client")
+	}
+}
+
 var testPropertiesCapeReaderInput = [...]struct {
 	props      *Properties
 	transport  http.RoundTripper
@@ -718,3 +1211,72 @@ func TestProperties_CapeReaderContextUsed(t *testing.T) {
 		t.Error("Properties{CapeURL: ...}.LoadProperties(ctx) didn't pass context to underlying http.Client")
 	}
 }
+
+var testPropertiesFetchCapeInput = [...]struct {
+	props      *Properties
+	transport  http.RoundTripper
+	expTexture []byte
+	expErr     error
+}{
+	{
+		props: &Properties{
+			CapeURL: "",
+		},
+		transport:  nil,
+		expTexture: nil,
+		expErr:     ErrNoCape,
+	},
+	{
+		props: &Properties{
+			CapeURL: "http://textures.minecraft.net/texture/ec80a225b145c812a6ef1ca29af0f3ebf02163874d1a66e53bac99965225e0",
+		},
+		transport: http.NewFileTransport(http.Dir("testdata")),
+		expTexture: (func() []byte {
+			b, _ := ioutil.ReadFile("testdata/texture/ec80a225b145c812a6ef1ca29af0f3ebf02163874d1a66e53bac99965225e0")
+			return b
+		})(),
+	},
+	{
+		props: &Properties{
+			CapeURL: alexSkinURL,
+		},
+		transport: errorTransport{testError},
+		expErr: &url.Error{
+			Op:  "Get",
+			URL: alexSkinURL,
+			Err: testError,
+		},
+	},
+	{
+		props: &Properties{
+			CapeURL: "http://example.com/does/not/exist.png",
+		},
+		transport: http.NewFileTransport(http.Dir("testdata")),
+		expErr: &url.Error{
+			Op:  "Get",
+			URL: "http://example.com/does/not/exist.png",
+			Err: &internal.FailedRequestError{StatusCode: 404},
+		},
+	},
+}
+
+func TestProperties_FetchCape(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	for _, tc := range testPropertiesFetchCapeInput {
+		client.Transport = tc.transport
+
+		texture, err := tc.props.FetchCape(context.Background())
+		if !reflect.DeepEqual(texture, tc.expTexture) || !reflect.DeepEqual(err, tc.expErr) {
+			t.Errorf(
+				"%#v.FetchCape(ctx)\n"+
+					" was: %#v, %s\n"+
+					"want: %#v, %s",
+				tc.props,
+				texture, p(err),
+				tc.expTexture, p(tc.expErr),
+			)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package profile
+
+import "time"
+
+// The rate limits enforced by Mojang for the load operations, at the time of
+// writing. The limits are shared by all load operations except those loading
+// profile properties, which are limited by PropertiesCooldown instead. The
+// values are updated as Mojang changes the limits.
+const (
+	LoadRateLimit  = 600              // Requests allowed per LoadRateWindow.
+	LoadRateWindow = 10 * time.Minute // Window over which LoadRateLimit applies.
+)
+
+// Operation identifies a group of operations of this package which share a
+// rate limit enforced by Mojang.
+type Operation byte
+
+const (
+	// OpLoad covers the functions which look up profiles and name histories,
+	// e.g. Load, LoadMany, LoadByID and Profile.LoadNameHistory.
+	OpLoad Operation = iota
+	// OpLoadProperties covers LoadWithProperties and Profile.LoadProperties.
+	OpLoadProperties
+)
+
+// String returns a string representation of op.
+//	OpLoad.String()           = "Load"
+//	OpLoadProperties.String() = "LoadProperties"
+// String returns "???" for operations not declared by this package.
+func (op Operation) String() string {
+	switch op {
+	case OpLoad:
+		return "Load"
+	case OpLoadProperties:
+		return "LoadProperties"
+	default:
+		return "???"
+	}
+}
+
+// RateLimit describes how many requests Mojang allows within a period of time.
+type RateLimit struct {
+	// Requests is the number of requests allowed per Per.
+	Requests int
+	// Per is the period of time within which at most Requests may be made.
+	Per time.Duration
+	// PerProfile is whether the limit applies to each profile separately
+	// rather than to all requests made, from the same IP address.
+	PerProfile bool
+
+	_ struct{} // Ensure RateLimit is constructed using named parameters.
+}
+
+// RateLimitFor returns the rate limit Mojang enforces for op, e.g. to throttle
+// requests before they would fail with ErrTooManyRequests:
+//	l := profile.RateLimitFor(profile.OpLoad)
+//	interval := l.Per / time.Duration(l.Requests) // 1s between requests
+// RateLimitFor returns a zero-value RateLimit for operations not declared by
+// this package.
+func RateLimitFor(op Operation) RateLimit {
+	switch op {
+	case OpLoad:
+		return RateLimit{Requests: LoadRateLimit, Per: LoadRateWindow}
+	case OpLoadProperties:
+		return RateLimit{Requests: 1, Per: PropertiesCooldown, PerProfile: true}
+	default:
+		return RateLimit{}
+	}
+}
@@ -0,0 +1,68 @@
+package profile
+
+import (
+	"testing"
+	"time"
+)
+
+var testOperationStringInput = [...]struct {
+	op     Operation
+	expStr string
+}{
+	{
+		op:     OpLoad,
+		expStr: "Load",
+	},
+	{
+		op:     OpLoadProperties,
+		expStr: "LoadProperties",
+	},
+	{
+		op:     Operation(99),
+		expStr: "???",
+	},
+}
+
+func TestOperation_String(t *testing.T) {
+	for _, tc := range testOperationStringInput {
+		s := tc.op.String()
+		if s != tc.expStr {
+			t.Errorf(
+				"Operation(%d).String() was %q; want %q",
+				byte(tc.op), s, tc.expStr,
+			)
+		}
+	}
+}
+
+var testRateLimitForInput = [...]struct {
+	op       Operation
+	expLimit RateLimit
+}{
+	{
+		op:       OpLoad,
+		expLimit: RateLimit{Requests: 600, Per: 10 * time.Minute},
+	},
+	{
+		op:       OpLoadProperties,
+		expLimit: RateLimit{Requests: 1, Per: time.Minute, PerProfile: true},
+	},
+	{
+		op:       Operation(99),
+		expLimit: RateLimit{},
+	},
+}
+
+func TestRateLimitFor(t *testing.T) {
+	for _, tc := range testRateLimitForInput {
+		l := RateLimitFor(tc.op)
+		if l != tc.expLimit {
+			t.Errorf(
+				"RateLimitFor(%s)\n"+
+					" was: %#v\n"+
+					"want: %#v",
+				tc.op, l, tc.expLimit,
+			)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package profile
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/PhilipBorgesen/minecraft/internal"
+)
+
+// The BuildXRequest functions return the request which the corresponding load
+// function would send to Mojang's servers, without sending it. They allow
+// clients to verify that requests are directed and encoded as expected, e.g.
+// after calling Configure, before going live against a custom backend. Each
+// request has the same method, URL, headers and body as the request sent by
+// the load function; its context is context.Background().
+//
+// If the load function would fail without making a request, e.g. because an
+// empty username is passed, its Build function returns nil along with the
+// error the load function would return.
+
+// BuildLoadRequest returns the request Load sends to fetch the profile
+// currently associated with username.
+func BuildLoadRequest(username string) (*http.Request, error) {
+	if username == "" {
+		return nil, ErrNoSuchProfile
+	}
+	return buildRequest("GET", profileURL(loadPath, username), nil)
+}
+
+// BuildLoadAtTimeRequest returns the request LoadAtTime sends to fetch the
+// profile associated with username at the instant of time t.
+func BuildLoadAtTimeRequest(username string, t time.Time) (*http.Request, error) {
+	if username == "" {
+		return nil, ErrNoSuchProfile
+	}
+	return buildRequest("GET", profileURL(loadAtTimePath, username, AtTimestamp(t)), nil)
+}
+
+// BuildLoadWithNameHistoryRequest returns the request LoadWithNameHistory,
+// LoadByID and Profile.LoadNameHistory send to fetch the name history of the
+// profile identified by id.
+func BuildLoadWithNameHistoryRequest(id string) (*http.Request, error) {
+	if id == "" {
+		return nil, ErrNoSuchProfile
+	}
+	return buildRequest("GET", profileURL(loadWithNameHistoryPath, id), nil)
+}
+
+// BuildLoadWithPropertiesRequest returns the request LoadWithProperties and
+// Profile.LoadProperties send to fetch the properties of the profile
+// identified by id.
+func BuildLoadWithPropertiesRequest(id string) (*http.Request, error) {
+	if id == "" {
+		return nil, ErrNoSuchProfile
+	}
+	return buildRequest("GET", sessionURL(loadWithPropertiesPath, id), nil)
+}
+
+// BuildLoadManyRequest returns the request LoadMany sends to fetch the
+// profiles currently associated with usernames, including its JSON body. If
+// LoadMany wouldn't send a request because no non-empty usernames are given,
+// BuildLoadManyRequest returns nil, nil.
+func BuildLoadManyRequest(usernames ...string) (*http.Request, error) {
+	users, err := loadManyUsernames(usernames)
+	if err != nil || len(users) == 0 {
+		return nil, err
+	}
+	return buildRequest("POST", profileURL(loadManyPath), users)
+}
+
+func buildRequest(method, endpoint string, data interface{}) (*http.Request, error) {
+	return internal.NewRequest(context.Background(), method, endpoint, data)
+}
@@ -0,0 +1,171 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testBuildRequestInput = [...]struct {
+	call      string
+	build     func() (*http.Request, error)
+	expMethod string
+	expURL    string
+	expBody   string
+	expErr    error
+}{
+	{
+		call:      `BuildLoadRequest("nergalic")`,
+		build:     func() (*http.Request, error) { return BuildLoadRequest("nergalic") },
+		expMethod: "GET",
+		expURL:    "https://api.mojang.com/users/profiles/minecraft/nergalic",
+	},
+	{
+		call:   `BuildLoadRequest("")`,
+		build:  func() (*http.Request, error) { return BuildLoadRequest("") },
+		expErr: ErrNoSuchProfile,
+	},
+	{
+		call:      `BuildLoadAtTimeRequest("nergalic", time.Unix(1423047705, 999))`,
+		build:     func() (*http.Request, error) { return BuildLoadAtTimeRequest("nergalic", time.Unix(1423047705, 999)) },
+		expMethod: "GET",
+		expURL:    "https://api.mojang.com/users/profiles/minecraft/nergalic?at=1423047705",
+	},
+	{
+		call:   `BuildLoadAtTimeRequest("", time.Time{})`,
+		build:  func() (*http.Request, error) { return BuildLoadAtTimeRequest("", time.Time{}) },
+		expErr: ErrNoSuchProfile,
+	},
+	{
+		call: `BuildLoadWithNameHistoryRequest("087cc153c3434ff7ac497de1569affa1")`,
+		build: func() (*http.Request, error) {
+			return BuildLoadWithNameHistoryRequest("087cc153c3434ff7ac497de1569affa1")
+		},
+		expMethod: "GET",
+		expURL:    "https://api.mojang.com/user/profiles/087cc153c3434ff7ac497de1569affa1/names",
+	},
+	{
+		call:   `BuildLoadWithNameHistoryRequest("")`,
+		build:  func() (*http.Request, error) { return BuildLoadWithNameHistoryRequest("") },
+		expErr: ErrNoSuchProfile,
+	},
+	{
+		call: `BuildLoadWithPropertiesRequest("087cc153c3434ff7ac497de1569affa1")`,
+		build: func() (*http.Request, error) {
+			return BuildLoadWithPropertiesRequest("087cc153c3434ff7ac497de1569affa1")
+		},
+		expMethod: "GET",
+		expURL:    "https://sessionserver.mojang.com/session/minecraft/profile/087cc153c3434ff7ac497de1569affa1",
+	},
+	{
+		call:   `BuildLoadWithPropertiesRequest("")`,
+		build:  func() (*http.Request, error) { return BuildLoadWithPropertiesRequest("") },
+		expErr: ErrNoSuchProfile,
+	},
+	{
+		call:      `BuildLoadManyRequest("nergalic", "", "GeneralSezuan")`,
+		build:     func() (*http.Request, error) { return BuildLoadManyRequest("nergalic", "", "GeneralSezuan") },
+		expMethod: "POST",
+		expURL:    "https://api.mojang.com/profiles/minecraft",
+		expBody:   `["nergalic","GeneralSezuan"]` + "\n",
+	},
+	{
+		call:  `BuildLoadManyRequest("")`,
+		build: func() (*http.Request, error) { return BuildLoadManyRequest("") },
+	},
+	{
+		call:   "BuildLoadManyRequest(101 usernames...)",
+		build:  func() (*http.Request, error) { return BuildLoadManyRequest(make([]string, 101)...) },
+		expErr: ErrMaxSizeExceeded{101},
+	},
+}
+
+func TestBuildRequest(t *testing.T) {
+	for _, tc := range testBuildRequestInput {
+		req, err := tc.build()
+		if !reflect.DeepEqual(err, tc.expErr) {
+			t.Errorf("%s returned error %v; want %v", tc.call, err, tc.expErr)
+			continue
+		}
+		if tc.expMethod == "" {
+			if req != nil {
+				t.Errorf("%s returned request %s %s; want nil", tc.call, req.Method, req.URL)
+			}
+			continue
+		}
+
+		var body string
+		if req.Body != nil {
+			bs, _ := ioutil.ReadAll(req.Body)
+			body = string(bs)
+		}
+		if req.Method != tc.expMethod || req.URL.String() != tc.expURL || body != tc.expBody {
+			t.Errorf(
+				"%s\n"+
+					" was: %s %s %q\n"+
+					"want: %s %s %q",
+				tc.call,
+				req.Method, req.URL, body,
+				tc.expMethod, tc.expURL, tc.expBody,
+			)
+		}
+		if enc := req.Header.Get("Accept-Encoding"); enc != "gzip" {
+			t.Errorf("%s returned request with Accept-Encoding %q; want \"gzip\"", tc.call, enc)
+		}
+	}
+}
+
+func TestBuildRequestConfigured(t *testing.T) {
+	defer Configure(Config{})
+	Configure(Config{ProfileBaseURL: "https://api-cache.example.com/"})
+
+	req, err := BuildLoadRequest("nergalic")
+	if exp := "https://api-cache.example.com/users/profiles/minecraft/nergalic"; err != nil || req.URL.String() != exp {
+		t.Errorf("BuildLoadRequest(\"nergalic\") after Configure returned request for %v, %v; want %s, <nil>", req.URL, err, exp)
+	}
+}
+
+// Test that built requests match those sent by the load functions
+func TestBuildRequestMatchesLoad(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	rt := &requestStoreTransport{}
+	client.Transport = rt
+	LoadMany(context.Background(), "nergalic", "GeneralSezuan")
+
+	req, _ := BuildLoadManyRequest("nergalic", "GeneralSezuan")
+	body, _ := ioutil.ReadAll(req.Body)
+	if req.Method != rt.Method || req.URL.String() != rt.URL || string(body) != rt.Body || !reflect.DeepEqual(req.Header, rt.Header) {
+		t.Errorf(
+			"BuildLoadManyRequest(\"nergalic\", \"GeneralSezuan\")\n"+
+				" was: %s %s %q %v\n"+
+				"want: %s %s %q %v",
+			req.Method, req.URL, body, req.Header,
+			rt.Method, rt.URL, rt.Body, rt.Header,
+		)
+	}
+}
+
+/*************
+* TEST UTILS *
+*************/
+
+// requestStoreTransport records the last request sent through it.
+type requestStoreTransport struct {
+	Method, URL, Body string
+	Header            http.Header
+}
+
+func (rt *requestStoreTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.Method, rt.URL, rt.Header = req.Method, req.URL.String(), req.Header
+	if req.Body != nil {
+		bs, _ := ioutil.ReadAll(req.Body)
+		rt.Body = string(bs)
+	}
+	return nil, errors.New("RoundTrip was called")
+}
@@ -0,0 +1,274 @@
+package profile
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/PhilipBorgesen/minecraft/uuid"
+)
+
+// whitelistEntry is an entry of a vanilla server's whitelist.json file.
+type whitelistEntry struct {
+	UUID string `json:"uuid"`
+	Name string `json:"name"`
+}
+
+// WriteWhitelist writes the profiles ps to w in the format of the whitelist.json
+// file of vanilla Minecraft servers, i.e. as a JSON array of objects holding the
+// hyphenated ID and current username of each profile.
+//
+// If a profile in ps is nil or has no ID, ErrUnsetPlayerID is returned. If its
+// ID isn't a valid profile ID, ErrInvalidID is returned. In both cases nothing
+// is written.
+func WriteWhitelist(w io.Writer, ps []*Profile) error {
+	es := make([]whitelistEntry, len(ps))
+	for i, p := range ps {
+		id, err := profileID(p)
+		if err != nil {
+			return err
+		}
+		es[i] = whitelistEntry{UUID: id, Name: p.Name}
+	}
+	return writeServerJSON(w, es)
+}
+
+// OpEntry is an entry of a vanilla server's ops.json file, i.e. a server
+// operator.
+type OpEntry struct {
+	// Profile is the operator's profile. Its ID and Name are written.
+	Profile *Profile
+	// Level is the operator's permission level from 1 to 4.
+	Level int
+	// BypassesPlayerLimit is whether the operator may join the server even
+	// when it is full.
+	BypassesPlayerLimit bool
+
+	_ struct{} // Ensure OpEntry is constructed using named parameters.
+}
+
+// opJSON is the JSON representation of an OpEntry.
+type opJSON struct {
+	UUID                string `json:"uuid"`
+	Name                string `json:"name"`
+	Level               int    `json:"level"`
+	BypassesPlayerLimit bool   `json:"bypassesPlayerLimit"`
+}
+
+// WriteOps writes the server operators es to w in the format of the ops.json
+// file of vanilla Minecraft servers.
+//
+// If the profile of an entry in es is nil or has no ID, ErrUnsetPlayerID is
+// returned. If its ID isn't a valid profile ID, ErrInvalidID is returned. In
+// both cases nothing is written.
+func WriteOps(w io.Writer, es []OpEntry) error {
+	js := make([]opJSON, len(es))
+	for i, e := range es {
+		id, err := profileID(e.Profile)
+		if err != nil {
+			return err
+		}
+		js[i] = opJSON{
+			UUID:                id,
+			Name:                e.Profile.Name,
+			Level:               e.Level,
+			BypassesPlayerLimit: e.BypassesPlayerLimit,
+		}
+	}
+	return writeServerJSON(w, js)
+}
+
+// BanEntry is an entry of a vanilla server's banned-players.json file.
+type BanEntry struct {
+	// Profile is the banned player's profile. Its ID and Name are written.
+	Profile *Profile
+	// Created is when the ban was issued. If Created is the zero time, the
+	// current time is written.
+	Created time.Time
+	// Source is who issued the ban. If Source == "", "Server" is written.
+	Source string
+	// Expires is when the ban is lifted. If Expires is the zero time, the ban
+	// is permanent.
+	Expires time.Time
+	// Reason is why the player was banned. If Reason == "", the default
+	// reason "Banned by an operator." is written.
+	Reason string
+
+	_ struct{} // Ensure BanEntry is constructed using named parameters.
+}
+
+// banJSON is the JSON representation of a BanEntry.
+type banJSON struct {
+	UUID    string `json:"uuid"`
+	Name    string `json:"name"`
+	Created string `json:"created"`
+	Source  string `json:"source"`
+	Expires string `json:"expires"`
+	Reason  string `json:"reason"`
+}
+
+// banTimeFormat is the time format used by banned-players.json and
+// usercache.json files.
+const banTimeFormat = "2006-01-02 15:04:05 -0700"
+
+// WriteBannedPlayers writes the bans es to w in the format of the
+// banned-players.json file of vanilla Minecraft servers.
+//
+// If the profile of an entry in es is nil or has no ID, ErrUnsetPlayerID is
+// returned. If its ID isn't a valid profile ID, ErrInvalidID is returned. In
+// both cases nothing is written.
+func WriteBannedPlayers(w io.Writer, es []BanEntry) error {
+	js := make([]banJSON, len(es))
+	for i, e := range es {
+		id, err := profileID(e.Profile)
+		if err != nil {
+			return err
+		}
+		b := banJSON{
+			UUID:    id,
+			Name:    e.Profile.Name,
+			Created: e.Created.Format(banTimeFormat),
+			Source:  e.Source,
+			Expires: "forever",
+			Reason:  e.Reason,
+		}
+		if e.Created.IsZero() {
+			b.Created = now().Format(banTimeFormat)
+		}
+		if b.Source == "" {
+			b.Source = "Server"
+		}
+		if !e.Expires.IsZero() {
+			b.Expires = e.Expires.Format(banTimeFormat)
+		}
+		if b.Reason == "" {
+			b.Reason = "Banned by an operator."
+		}
+		js[i] = b
+	}
+	return writeServerJSON(w, js)
+}
+
+// UserCacheEntry is an entry of a vanilla server's usercache.json file, in which
+// servers cache the profiles of the usernames they have looked up.
+type UserCacheEntry struct {
+	// Profile is the cached profile. Its ID and Name are written, and only
+	// those are set when read.
+	Profile *Profile
+	// ExpiresOn is when the server stops trusting the entry and looks up the
+	// username anew.
+	ExpiresOn time.Time
+
+	_ struct{} // Ensure UserCacheEntry is constructed using named parameters.
+}
+
+// userCacheJSON is the JSON representation of a UserCacheEntry.
+type userCacheJSON struct {
+	Name      string `json:"name"`
+	UUID      string `json:"uuid"`
+	ExpiresOn string `json:"expiresOn"`
+}
+
+// WriteUserCache writes the profiles ps to w in the format of the
+// usercache.json file of vanilla Minecraft servers, with every entry expiring
+// at expiry. Pre-seeding a server's user cache with profiles resolved using
+// this package, e.g. by LoadMany, saves the server from looking them up itself
+// at startup. Vanilla servers let entries expire a month after the lookup.
+//
+// If a profile in ps is nil or has no ID, ErrUnsetPlayerID is returned. If its
+// ID isn't a valid profile ID, ErrInvalidID is returned. In both cases nothing
+// is written.
+func WriteUserCache(w io.Writer, ps []*Profile, expiry time.Time) error {
+	js := make([]userCacheJSON, len(ps))
+	for i, p := range ps {
+		id, err := profileID(p)
+		if err != nil {
+			return err
+		}
+		js[i] = userCacheJSON{
+			Name:      p.Name,
+			UUID:      id,
+			ExpiresOn: expiry.Format(banTimeFormat),
+		}
+	}
+	return writeServerJSON(w, js)
+}
+
+// ReadUserCache reads the entries of a usercache.json file of a vanilla
+// Minecraft server from r, in the order listed. The IDs of the returned
+// profiles are undashed, like the IDs of profiles loaded from Mojang.
+//
+// If an entry's ID isn't a valid profile ID, ErrInvalidID is returned. If r
+// doesn't hold a JSON array of entries, or the expiry of an entry isn't
+// formatted like vanilla servers do, the parse error is returned.
+func ReadUserCache(r io.Reader) ([]UserCacheEntry, error) {
+	var js []userCacheJSON
+	if err := json.NewDecoder(r).Decode(&js); err != nil {
+		return nil, err
+	}
+	es := make([]UserCacheEntry, len(js))
+	for i, j := range js {
+		id, err := uuid.ToUndashed(j.UUID)
+		if err != nil {
+			return nil, ErrInvalidID
+		}
+		expiry, err := time.Parse(banTimeFormat, j.ExpiresOn)
+		if err != nil {
+			return nil, err
+		}
+		es[i] = UserCacheEntry{
+			Profile:   &Profile{ID: id, Name: j.Name},
+			ExpiresOn: expiry,
+		}
+	}
+	return es, nil
+}
+
+// OfflineUUID returns the profile ID which servers in offline mode assign to
+// the player with the given username, computed like vanilla Minecraft does as
+// the version 3 UUID of "OfflinePlayer:" + name. Since usernames aren't
+// verified in offline mode, the ID is unrelated to the player's Mojang profile,
+// and usernames differing only in case are assigned different IDs.
+//
+// Like other profile IDs, the returned ID is undashed. Use uuid.ToHyphenated
+// to obtain its hyphenated form, e.g. for the files of offline mode servers.
+func OfflineUUID(name string) string {
+	sum := md5.Sum([]byte("OfflinePlayer:" + name))
+	sum[6] = sum[6]&0x0f | 0x30 // Version 3
+	sum[8] = sum[8]&0x3f | 0x80 // RFC 4122 variant
+	return hex.EncodeToString(sum[:])
+}
+
+// writeServerJSON writes v to w as JSON indented like the files written by
+// vanilla Minecraft servers.
+func writeServerJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// profileID returns the ID of p in the hyphenated form of UUIDs used by vanilla
+// Minecraft servers, as described for hyphenate. If p is nil, e.g. because it
+// was returned by LoadManyOrdered for an unknown username, ErrUnsetPlayerID is
+// returned.
+func profileID(p *Profile) (string, error) {
+	if p == nil {
+		return "", ErrUnsetPlayerID
+	}
+	return hyphenate(p.ID)
+}
+
+// hyphenate returns the profile ID id in the hyphenated form of UUIDs used by
+// vanilla Minecraft servers.
+func hyphenate(id string) (string, error) {
+	if id == "" {
+		return "", ErrUnsetPlayerID
+	}
+	h, err := uuid.ToHyphenated(id)
+	if err != nil {
+		return "", ErrInvalidID
+	}
+	return h, nil
+}
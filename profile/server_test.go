@@ -0,0 +1,292 @@
+package profile
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PhilipBorgesen/minecraft/uuid"
+)
+
+var testWriteWhitelistInput = [...]struct {
+	profiles []*Profile
+	expOut   string
+	expErr   error
+}{
+	{
+		profiles: nil,
+		expOut:   "[]\n",
+	},
+	{
+		profiles: []*Profile{
+			{ID: "069a79f444e94726a5befca90e38aaf5", Name: "Notch"},
+			{ID: "087CC153C3434FF7AC497DE1569AFFA1", Name: "Nergalic"},
+		},
+		expOut: `[
+  {
+    "uuid": "069a79f4-44e9-4726-a5be-fca90e38aaf5",
+    "name": "Notch"
+  },
+  {
+    "uuid": "087cc153-c343-4ff7-ac49-7de1569affa1",
+    "name": "Nergalic"
+  }
+]
+`,
+	},
+	{
+		profiles: []*Profile{{Name: "Notch"}},
+		expErr:   ErrUnsetPlayerID,
+	},
+	{
+		profiles: []*Profile{{ID: "069a79f444e94726a5befca90e38aaf5", Name: "Notch"}, nil},
+		expErr:   ErrUnsetPlayerID,
+	},
+	{
+		profiles: []*Profile{{ID: "069a79f4-44e9-4726-a5be-fca90e38aaf5", Name: "Notch"}},
+		expOut: `[
+  {
+    "uuid": "069a79f4-44e9-4726-a5be-fca90e38aaf5",
+    "name": "Notch"
+  }
+]
+`,
+	},
+	{
+		profiles: []*Profile{{ID: "069a79f444e94726a5befca90e38aaf", Name: "Notch"}},
+		expErr:   ErrInvalidID,
+	},
+	{
+		profiles: []*Profile{{ID: "069a79f444e94726a5befca90e38aafg", Name: "Notch"}},
+		expErr:   ErrInvalidID,
+	},
+}
+
+func TestWriteWhitelist(t *testing.T) {
+	for _, tc := range testWriteWhitelistInput {
+		var buf bytes.Buffer
+		err := WriteWhitelist(&buf, tc.profiles)
+		if out := buf.String(); out != tc.expOut || err != tc.expErr {
+			t.Errorf(
+				"WriteWhitelist(w, %v)\n"+
+					" was: %q, %v\n"+
+					"want: %q, %v",
+				tc.profiles,
+				out, err,
+				tc.expOut, tc.expErr,
+			)
+		}
+	}
+}
+
+func TestWriteOps(t *testing.T) {
+	es := []OpEntry{
+		{Profile: &Profile{ID: "069a79f444e94726a5befca90e38aaf5", Name: "Notch"}, Level: 4, BypassesPlayerLimit: true},
+		{Profile: &Profile{ID: "087cc153c3434ff7ac497de1569affa1", Name: "Nergalic"}, Level: 2},
+	}
+	exp := `[
+  {
+    "uuid": "069a79f4-44e9-4726-a5be-fca90e38aaf5",
+    "name": "Notch",
+    "level": 4,
+    "bypassesPlayerLimit": true
+  },
+  {
+    "uuid": "087cc153-c343-4ff7-ac49-7de1569affa1",
+    "name": "Nergalic",
+    "level": 2,
+    "bypassesPlayerLimit": false
+  }
+]
+`
+
+	var buf bytes.Buffer
+	if err := WriteOps(&buf, es); buf.String() != exp || err != nil {
+		t.Errorf("WriteOps(w, es)\n"+
+			" was: %q, %v\n"+
+			"want: %q, <nil>",
+			buf.String(), err, exp)
+	}
+
+	buf.Reset()
+	es = append(es, OpEntry{Profile: &Profile{Name: "Unknown"}})
+	if err := WriteOps(&buf, es); buf.Len() != 0 || err != ErrUnsetPlayerID {
+		t.Errorf("WriteOps(w, es) with unset ID wrote %q, returned %v; want \"\", %s", buf.String(), err, ErrUnsetPlayerID)
+	}
+
+	buf.Reset()
+	es[len(es)-1].Profile = nil
+	if err := WriteOps(&buf, es); buf.Len() != 0 || err != ErrUnsetPlayerID {
+		t.Errorf("WriteOps(w, es) with nil profile wrote %q, returned %v; want \"\", %s", buf.String(), err, ErrUnsetPlayerID)
+	}
+}
+
+func TestWriteBannedPlayers(t *testing.T) {
+	origNow := now
+	defer func() { now = origNow }()
+	now = func() time.Time { return time.Date(2017, 01, 02, 03, 04, 05, 00, time.UTC) }
+
+	cet := time.FixedZone("CET", 60*60)
+	es := []BanEntry{
+		{Profile: &Profile{ID: "069a79f444e94726a5befca90e38aaf5", Name: "Notch"}},
+		{
+			Profile: &Profile{ID: "087cc153c3434ff7ac497de1569affa1", Name: "Nergalic"},
+			Created: time.Date(2016, 12, 24, 18, 00, 00, 00, cet),
+			Source:  "Santa",
+			Expires: time.Date(2016, 12, 26, 00, 00, 00, 00, cet),
+			Reason:  "Naughty",
+		},
+	}
+	exp := `[
+  {
+    "uuid": "069a79f4-44e9-4726-a5be-fca90e38aaf5",
+    "name": "Notch",
+    "created": "2017-01-02 03:04:05 +0000",
+    "source": "Server",
+    "expires": "forever",
+    "reason": "Banned by an operator."
+  },
+  {
+    "uuid": "087cc153-c343-4ff7-ac49-7de1569affa1",
+    "name": "Nergalic",
+    "created": "2016-12-24 18:00:00 +0100",
+    "source": "Santa",
+    "expires": "2016-12-26 00:00:00 +0100",
+    "reason": "Naughty"
+  }
+]
+`
+
+	var buf bytes.Buffer
+	if err := WriteBannedPlayers(&buf, es); buf.String() != exp || err != nil {
+		t.Errorf("WriteBannedPlayers(w, es)\n"+
+			" was: %q, %v\n"+
+			"want: %q, <nil>",
+			buf.String(), err, exp)
+	}
+
+	buf.Reset()
+	es = append(es, BanEntry{Profile: &Profile{ID: "invalid", Name: "Invalid"}})
+	if err := WriteBannedPlayers(&buf, es); buf.Len() != 0 || err != ErrInvalidID {
+		t.Errorf("WriteBannedPlayers(w, es) with invalid ID wrote %q, returned %v; want \"\", %s", buf.String(), err, ErrInvalidID)
+	}
+
+	buf.Reset()
+	es[len(es)-1].Profile = nil
+	if err := WriteBannedPlayers(&buf, es); buf.Len() != 0 || err != ErrUnsetPlayerID {
+		t.Errorf("WriteBannedPlayers(w, es) with nil profile wrote %q, returned %v; want \"\", %s", buf.String(), err, ErrUnsetPlayerID)
+	}
+}
+
+func TestWriteUserCache(t *testing.T) {
+	ps := []*Profile{
+		{ID: "069a79f444e94726a5befca90e38aaf5", Name: "Notch"},
+		{ID: "087cc153c3434ff7ac497de1569affa1", Name: "Nergalic"},
+	}
+	expiry := time.Date(2017, 02, 02, 03, 04, 05, 00, time.FixedZone("CET", 60*60))
+	exp := `[
+  {
+    "name": "Notch",
+    "uuid": "069a79f4-44e9-4726-a5be-fca90e38aaf5",
+    "expiresOn": "2017-02-02 03:04:05 +0100"
+  },
+  {
+    "name": "Nergalic",
+    "uuid": "087cc153-c343-4ff7-ac49-7de1569affa1",
+    "expiresOn": "2017-02-02 03:04:05 +0100"
+  }
+]
+`
+
+	var buf bytes.Buffer
+	if err := WriteUserCache(&buf, ps, expiry); buf.String() != exp || err != nil {
+		t.Errorf("WriteUserCache(w, ps, %s)\n"+
+			" was: %q, %v\n"+
+			"want: %q, <nil>",
+			expiry, buf.String(), err, exp)
+	}
+
+	buf.Reset()
+	ps = append(ps, &Profile{Name: "Unset"})
+	if err := WriteUserCache(&buf, ps, expiry); buf.Len() != 0 || err != ErrUnsetPlayerID {
+		t.Errorf("WriteUserCache(w, ps, %s) with unset ID wrote %q, returned %v; want \"\", %s", expiry, buf.String(), err, ErrUnsetPlayerID)
+	}
+
+	buf.Reset()
+	ps[len(ps)-1] = nil
+	if err := WriteUserCache(&buf, ps, expiry); buf.Len() != 0 || err != ErrUnsetPlayerID {
+		t.Errorf("WriteUserCache(w, ps, %s) with nil profile wrote %q, returned %v; want \"\", %s", expiry, buf.String(), err, ErrUnsetPlayerID)
+	}
+}
+
+func TestReadUserCache(t *testing.T) {
+	const in = `[
+		{"name":"Notch","uuid":"069a79f4-44e9-4726-a5be-fca90e38aaf5","expiresOn":"2017-02-02 03:04:05 +0100"},
+		{"name":"Nergalic","uuid":"087cc153-c343-4ff7-ac49-7de1569affa1","expiresOn":"2017-01-01 00:00:00 +0000"}
+	]`
+	exp := []struct {
+		id, name  string
+		expiresOn time.Time
+	}{
+		{"069a79f444e94726a5befca90e38aaf5", "Notch", time.Date(2017, 02, 02, 02, 04, 05, 00, time.UTC)},
+		{"087cc153c3434ff7ac497de1569affa1", "Nergalic", time.Date(2017, 01, 01, 00, 00, 00, 00, time.UTC)},
+	}
+
+	es, err := ReadUserCache(strings.NewReader(in))
+	if err != nil || len(es) != len(exp) {
+		t.Fatalf("ReadUserCache(r) returned %d entries, %v; want %d, <nil>", len(es), err, len(exp))
+	}
+	for i, e := range es {
+		if e.Profile.ID != exp[i].id || e.Profile.Name != exp[i].name || !e.ExpiresOn.Equal(exp[i].expiresOn) {
+			t.Errorf("ReadUserCache(r)[%d]\n"+
+				" was: %s, %s, %s\n"+
+				"want: %s, %s, %s",
+				i, e.Profile.ID, e.Profile.Name, e.ExpiresOn,
+				exp[i].id, exp[i].name, exp[i].expiresOn)
+		}
+	}
+
+	// Entries written must be read back unchanged
+	var buf bytes.Buffer
+	WriteUserCache(&buf, []*Profile{es[0].Profile}, es[0].ExpiresOn)
+	if rt, err := ReadUserCache(&buf); err != nil || len(rt) != 1 || !reflect.DeepEqual(rt[0].Profile, es[0].Profile) || !rt[0].ExpiresOn.Equal(es[0].ExpiresOn) {
+		t.Errorf("ReadUserCache(r) didn't read back entry written by WriteUserCache: %#v, %v", rt, err)
+	}
+}
+
+func TestReadUserCacheMalformed(t *testing.T) {
+	if es, err := ReadUserCache(strings.NewReader(`[{"name":"Invalid","uuid":"invalid","expiresOn":"2017-01-01 00:00:00 +0000"}]`)); es != nil || err != ErrInvalidID {
+		t.Errorf("ReadUserCache(r) with invalid ID was %v, %v; want <nil>, %s", es, err, ErrInvalidID)
+	}
+	if es, err := ReadUserCache(strings.NewReader(`[{"name":"Notch","uuid":"069a79f4-44e9-4726-a5be-fca90e38aaf5","expiresOn":"tomorrow"}]`)); es != nil || err == nil {
+		t.Errorf("ReadUserCache(r) with malformed expiry was %v, %v; want <nil>, error", es, err)
+	}
+	if es, err := ReadUserCache(strings.NewReader(`{"name":"Notch"}`)); es != nil || err == nil {
+		t.Errorf("ReadUserCache(r) with malformed JSON was %v, %v; want <nil>, error", es, err)
+	}
+}
+
+var testOfflineUUIDInput = [...]struct {
+	name      string
+	expID     string
+	expHyphen string
+}{
+	{name: "Notch", expID: "b50ad385829d3141a2167e7d7539ba7f", expHyphen: "b50ad385-829d-3141-a216-7e7d7539ba7f"},
+	{name: "notch", expID: "42653081a90e3475b3d63550cdb43f8e", expHyphen: "42653081-a90e-3475-b3d6-3550cdb43f8e"},
+	{name: "jeb_", expID: "a762f5604fce3236812ab80efff0b62b", expHyphen: "a762f560-4fce-3236-812a-b80efff0b62b"},
+	{name: "", expID: "fc5bc365aedf30a88b8904e462e29bde", expHyphen: "fc5bc365-aedf-30a8-8b89-04e462e29bde"},
+}
+
+func TestOfflineUUID(t *testing.T) {
+	for _, tc := range testOfflineUUIDInput {
+		id := OfflineUUID(tc.name)
+		if id != tc.expID {
+			t.Errorf("OfflineUUID(%q) was %q; want %q", tc.name, id, tc.expID)
+		}
+		if h, _ := uuid.ToHyphenated(id); h != tc.expHyphen {
+			t.Errorf("hyphenated OfflineUUID(%q) was %q; want %q", tc.name, h, tc.expHyphen)
+		}
+	}
+}
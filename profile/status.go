@@ -0,0 +1,135 @@
+package profile
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/PhilipBorgesen/minecraft/internal"
+)
+
+// Health represents whether a group of Mojang endpoints is operational.
+type Health byte
+
+const (
+	Up       Health = iota // Endpoints respond as expected.
+	Degraded               // Endpoints respond with server errors or time out.
+	Down                   // Endpoints can't be reached.
+)
+
+// String returns a string representation of h.
+//	Up.String()       = "Up"
+//	Degraded.String() = "Degraded"
+//	Down.String()     = "Down"
+// String returns "???" for health states not declared by this package.
+func (h Health) String() string {
+	switch h {
+	case Up:
+		return "Up"
+	case Degraded:
+		return "Degraded"
+	case Down:
+		return "Down"
+	default:
+		return "???"
+	}
+}
+
+// APIHealth summarizes the health of the Mojang endpoints used by this
+// package, as reported by APIStatus.
+type APIHealth struct {
+	// Profiles is the health of the endpoints used to look up profiles and
+	// name histories, located at Config.ProfileBaseURL.
+	Profiles Health
+	// Sessions is the health of the endpoints used to load profile
+	// properties, located at Config.SessionBaseURL.
+	Sessions Health
+
+	_ struct{} // Ensure APIHealth is constructed using named parameters.
+}
+
+// The profile requested when probing endpoints.
+const (
+	probeName = "Notch"
+	probeID   = "069a79f444e94726a5befca90e38aaf5"
+)
+
+// APIProbeTimeout is how long APIStatus waits for each probe to get a response
+// before reporting the probed endpoints Degraded.
+const APIProbeTimeout = 5 * time.Second
+
+// probeTimeout is the APIProbeTimeout used by APIStatus. It may be replaced by
+// tests.
+var probeTimeout = APIProbeTimeout
+
+// APIStatus probes the Mojang endpoints used by this package and reports their
+// health, e.g. to tell users that profile lookups are temporarily unavailable
+// during Mojang incidents rather than reporting a generic error. ctx must be
+// non-nil.
+//
+// An endpoint group is reported Up if its probe gets a response other than a
+// server error, even if Mojang rejects it, e.g. due to rate limiting. It is
+// reported Degraded if the probe gets a 5xx response, an unparsable response,
+// or gets no response within APIProbeTimeout, and Down if the endpoints can't
+// be reached at all.
+//
+// APIStatus only returns an error if ctx is done before all probes complete,
+// e.g. because its deadline is shorter than APIProbeTimeout, in which case
+// ctx.Err() is returned and the returned health is meaningless.
+//
+// NB! Each call to APIStatus makes requests which count towards Mojang's rate
+// limits, so don't poll it aggressively.
+func APIStatus(ctx context.Context) (APIHealth, error) {
+	internal.CheckContext(ctx, "profile", "APIStatus")
+
+	var (
+		wg sync.WaitGroup
+		h  APIHealth
+	)
+	probes := []struct {
+		endpoint string
+		health   *Health
+	}{
+		{profileURL(loadPath, probeName), &h.Profiles},
+		{sessionURL(loadWithPropertiesPath, probeID), &h.Sessions},
+	}
+	for _, pr := range probes {
+		wg.Add(1)
+		go func(endpoint string, health *Health) {
+			defer wg.Done()
+			pctx, cancel := context.WithTimeout(ctx, probeTimeout)
+			defer cancel()
+			_, err := internal.FetchJSON(pctx, clientFor(ctx, endpoint), endpoint)
+			if err != nil && pctx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+				*health = Degraded // The probe timed out
+				return
+			}
+			*health = healthOf(err)
+		}(pr.endpoint, pr.health)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return APIHealth{}, err
+	}
+	return h, nil
+}
+
+// healthOf returns the health of an endpoint which responded to a probe with
+// err.
+func healthOf(err error) Health {
+	if err == nil {
+		return Up
+	}
+	if e, ok := internal.UnwrapFailedRequestError(err); ok {
+		if e.StatusCode >= 500 {
+			return Degraded
+		}
+		return Up
+	}
+	if e, ok := err.(*url.Error); ok && (e.Op == "Parse" || e.Timeout()) {
+		return Degraded
+	}
+	return Down
+}
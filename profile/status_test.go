@@ -0,0 +1,163 @@
+package profile
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+var testHealthStringInput = [...]struct {
+	health Health
+	expStr string
+}{
+	{
+		health: Up,
+		expStr: "Up",
+	},
+	{
+		health: Degraded,
+		expStr: "Degraded",
+	},
+	{
+		health: Down,
+		expStr: "Down",
+	},
+	{
+		health: Health(99),
+		expStr: "???",
+	},
+}
+
+func TestHealth_String(t *testing.T) {
+	for _, tc := range testHealthStringInput {
+		s := tc.health.String()
+		if s != tc.expStr {
+			t.Errorf(
+				"Health(%d).String() was %q; want %q",
+				byte(tc.health), s, tc.expStr,
+			)
+		}
+	}
+}
+
+var testAPIStatusInput = [...]struct {
+	profiles    http.RoundTripper
+	sessions    http.RoundTripper
+	expProfiles Health
+	expSessions Health
+}{
+	{
+		profiles:    http.NewFileTransport(http.Dir("testdata")),
+		sessions:    responseTransport{200, `{"id":"069a79f444e94726a5befca90e38aaf5","name":"Notch","properties":[]}`},
+		expProfiles: Up, // 404 Not Found
+		expSessions: Up,
+	},
+	{
+		profiles:    responseTransport{204, ""},
+		sessions:    responseTransport{429, `{"error":"TooManyRequestsException"}`},
+		expProfiles: Up,
+		expSessions: Up,
+	},
+	{
+		profiles:    responseTransport{503, "Service Unavailable"},
+		sessions:    responseTransport{200, "<html>"},
+		expProfiles: Degraded,
+		expSessions: Degraded,
+	},
+	{
+		profiles:    errorTransport{timeoutError{}},
+		sessions:    errorTransport{testError},
+		expProfiles: Degraded,
+		expSessions: Down,
+	},
+}
+
+func TestAPIStatus(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	for _, tc := range testAPIStatusInput {
+		client.Transport = hostTransport{
+			"api.mojang.com":           tc.profiles,
+			"sessionserver.mojang.com": tc.sessions,
+		}
+		h, err := APIStatus(context.Background())
+		if h.Profiles != tc.expProfiles || h.Sessions != tc.expSessions || err != nil {
+			t.Errorf(
+				"APIStatus(ctx)\n"+
+					" was: {Profiles: %s, Sessions: %s}, %v\n"+
+					"want: {Profiles: %s, Sessions: %s}, <nil>",
+				h.Profiles, h.Sessions, err, tc.expProfiles, tc.expSessions,
+			)
+		}
+	}
+}
+
+func TestAPIStatusCancelled(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	client.Transport = responseTransport{200, "{}"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := APIStatus(ctx); err != context.Canceled {
+		t.Errorf("APIStatus(ctx) with cancelled ctx returned error %v; want %s", err, context.Canceled)
+	}
+}
+
+func TestAPIStatusProbeTimeout(t *testing.T) {
+	origTransport, origTimeout := client.Transport, probeTimeout
+	defer func() { client.Transport, probeTimeout = origTransport, origTimeout }()
+
+	probeTimeout = 10 * time.Millisecond
+	client.Transport = hostTransport{
+		"api.mojang.com":           blockingTransport{},
+		"sessionserver.mojang.com": responseTransport{200, `{"id":"069a79f444e94726a5befca90e38aaf5","name":"Notch","properties":[]}`},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	h, err := APIStatus(ctx)
+	if h.Profiles != Degraded || h.Sessions != Up || err != nil {
+		t.Errorf(
+			"APIStatus(ctx) with unresponsive profile endpoints\n"+
+				" was: {Profiles: %s, Sessions: %s}, %v\n"+
+				"want: {Profiles: %s, Sessions: %s}, <nil>",
+			h.Profiles, h.Sessions, err, Degraded, Up,
+		)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	probeTimeout = time.Minute
+	if _, err := APIStatus(ctx); err != context.DeadlineExceeded {
+		t.Errorf("APIStatus(ctx) with expiring ctx returned error %v; want %s", err, context.DeadlineExceeded)
+	}
+}
+
+/*************
+* TEST UTILS *
+*************/
+
+// hostTransport routes requests to the transport of the requested host.
+type hostTransport map[string]http.RoundTripper
+
+func (ht hostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return ht[req.URL.Host].RoundTrip(req)
+}
+
+// blockingTransport blocks requests until their context is done.
+type blockingTransport struct{}
+
+func (blockingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
@@ -0,0 +1,45 @@
+package skin
+
+import (
+	"image"
+	"image/draw"
+)
+
+// Head renders the face of the player wearing the skin img, i.e. the front of
+// the head with the hat drawn on top, as a size x size pixels image. The face
+// is scaled using nearest-neighbour sampling to keep the pixels of the skin
+// crisp. Like vanilla Minecraft, Head ignores the hat of a legacy skin if it's
+// fully opaque, since many legacy skins fill the unused hat with a colour.
+//
+// If img isn't 64x64 or 64x32 pixels, ErrDimensions is returned. Head panics if
+// size <= 0.
+func Head(img image.Image, size int) (image.Image, error) {
+	if size <= 0 {
+		panic("minecraft/skin: non-positive size passed to Head")
+	}
+	legacy, err := isLegacy(img)
+	if err != nil {
+		return nil, err
+	}
+
+	min := img.Bounds().Min
+	face := image.NewNRGBA(image.Rect(0, 0, head.w, head.h))
+	draw.Draw(face, face.Bounds(), img, min.Add(head.faces()[front].Min), draw.Src)
+	if !legacy || checkOpaque(img, []part{hat}) != nil {
+		draw.Draw(face, face.Bounds(), img, min.Add(hat.faces()[front].Min), draw.Over)
+	}
+	return scale(face, size), nil
+}
+
+// scale returns src scaled to size x size pixels using nearest-neighbour
+// sampling.
+func scale(src *image.NRGBA, size int) *image.NRGBA {
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dst := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			dst.SetNRGBA(x, y, src.NRGBAAt(x*w/size, y*h/size))
+		}
+	}
+	return dst
+}
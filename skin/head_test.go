@@ -0,0 +1,135 @@
+package skin
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+// faceColor is the colour of the images created by opaque.
+var (
+	faceColor = color.NRGBA{R: 0x80, G: 0x40, B: 0x20, A: 0xff}
+	hatColor  = color.NRGBA{R: 0x10, G: 0x20, B: 0x30, A: 0xff}
+	halfHat   = color.NRGBA{R: 0x10, G: 0x20, B: 0x30, A: 0x80}
+)
+
+var testHeadInput = [...]struct {
+	desc string
+	img  image.Image
+	size int
+	// expected colour of the face pixel (x, y) scaled to 8x8 size
+	exp func(x, y int) color.NRGBA
+}{
+	{
+		desc: "skin without hat",
+		img:  transparent(opaque(Width, Height), image.Rect(32, 0, 64, 16)),
+		size: 8,
+		exp:  func(x, y int) color.NRGBA { return faceColor },
+	},
+	{
+		desc: "skin with partial hat",
+		img:  withHat(transparent(opaque(Width, Height), image.Rect(32, 0, 64, 16)), hatColor),
+		size: 32,
+		exp: func(x, y int) color.NRGBA {
+			if y < 2 {
+				return hatColor
+			}
+			return faceColor
+		},
+	},
+	{
+		desc: "skin with translucent hat",
+		img:  withHat(transparent(opaque(Width, Height), image.Rect(32, 0, 64, 16)), halfHat),
+		size: 5,
+		exp: func(x, y int) color.NRGBA {
+			if y < 2 {
+				return color.NRGBAModel.Convert(over(halfHat, faceColor)).(color.NRGBA)
+			}
+			return faceColor
+		},
+	},
+	{
+		desc: "legacy skin with opaque hat",
+		img:  withHat(opaque(Width, LegacyHeight), hatColor),
+		size: 16,
+		exp:  func(x, y int) color.NRGBA { return faceColor },
+	},
+	{
+		desc: "legacy skin with partial hat",
+		img:  withHat(transparent(opaque(Width, LegacyHeight), image.Rect(32, 0, 64, 16)), hatColor),
+		size: 16,
+		exp: func(x, y int) color.NRGBA {
+			if y < 2 {
+				return hatColor
+			}
+			return faceColor
+		},
+	},
+	{
+		desc: "skin with offset bounds",
+		img:  opaqueAt(image.Rect(-5, 7, -5+Width, 7+Height)),
+		size: 8,
+		exp:  func(x, y int) color.NRGBA { return faceColor },
+	},
+}
+
+func TestHead(t *testing.T) {
+	for _, tc := range testHeadInput {
+		h, err := Head(tc.img, tc.size)
+		if err != nil {
+			t.Errorf("Head(%s, %d) failed: %s", tc.desc, tc.size, err)
+			continue
+		}
+		if b, exp := h.Bounds(), image.Rect(0, 0, tc.size, tc.size); b != exp {
+			t.Errorf("Head(%s, %d) has bounds %s; want %s", tc.desc, tc.size, b, exp)
+			continue
+		}
+		for y := 0; y < tc.size; y++ {
+			for x := 0; x < tc.size; x++ {
+				c := color.NRGBAModel.Convert(h.At(x, y)).(color.NRGBA)
+				if exp := tc.exp(x*8/tc.size, y*8/tc.size); c != exp {
+					t.Errorf("Head(%s, %d) has colour %v at (%d,%d); want %v", tc.desc, tc.size, c, x, y, exp)
+				}
+			}
+		}
+	}
+}
+
+func TestHeadDimensions(t *testing.T) {
+	if h, err := Head(opaque(32, 32), 8); h != nil || err != ErrDimensions {
+		t.Errorf("Head(32x32 image, 8) was %v, %v; want <nil>, %s", h, err, ErrDimensions)
+	}
+}
+
+func TestHeadPanic(t *testing.T) {
+	const exp = "minecraft/skin: non-positive size passed to Head"
+	defer func() {
+		if r := recover(); r != exp {
+			t.Errorf("Head(img, 0) panicked with %#v; want %q", r, exp)
+		}
+	}()
+	Head(opaque(Width, Height), 0)
+}
+
+/***************
+*  TEST UTILS  *
+***************/
+
+// withHat paints the top two rows of the hat's front face of img with c.
+func withHat(img *image.NRGBA, c color.NRGBA) *image.NRGBA {
+	for y := 8; y < 10; y++ {
+		for x := 40; x < 48; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+// over returns src composited over the opaque colour dst.
+func over(src, dst color.NRGBA) color.Color {
+	img := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+	img.Set(0, 0, dst)
+	draw.Draw(img, img.Bounds(), &image.Uniform{src}, image.ZP, draw.Over)
+	return img.At(0, 0)
+}
@@ -0,0 +1,127 @@
+package skin
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// Shading of the faces of the cube rendered by Isometric, conveying its depth.
+const (
+	topShade   = 1.0
+	frontShade = 0.85
+	sideShade  = 0.7
+)
+
+var (
+	sqrt2 = math.Sqrt(2)
+	sqrt6 = math.Sqrt(6)
+)
+
+// Isometric renders the head of the player wearing the skin img as a cube seen
+// in isometric projection from above and in front of the player's right side,
+// showing the top, front and right side of the head, as a size x size pixels
+// image. This is the cube avatar style used by many player lists. The faces are
+// shaded to convey depth, the top being the brightest and the side the darkest.
+//
+// The hat is drawn as a slightly larger cube around the head, like in vanilla
+// Minecraft, and the cube is scaled to fit the hat. The cube is horizontally
+// centered and, being a hexagon, about 0.87 times as wide as it is high; the
+// remaining pixels are transparent. Like Head, Isometric ignores the hat of a
+// legacy skin if it's fully opaque. Since both player models share the same
+// head, the result is the same for classic and slim-armed skins.
+//
+// If img isn't 64x64 or 64x32 pixels, ErrDimensions is returned. Isometric
+// panics if size <= 0.
+func Isometric(img image.Image, size int) (image.Image, error) {
+	if size <= 0 {
+		panic("minecraft/skin: non-positive size passed to Isometric")
+	}
+	legacy, err := isLegacy(img)
+	if err != nil {
+		return nil, err
+	}
+	withHat := !legacy || checkOpaque(img, []part{hat}) != nil
+
+	// The hat cube spans [o, o+s] on every axis, the head cube [0, 8]. Scale
+	// the projection of the hat cube to fill the height of the image.
+	const o, s = -0.5, 9.0
+	k := float64(size) / (4 * s / sqrt6) // Pixels per texel
+	offX := (float64(size) - s*sqrt2*k) / 2
+	minX, minY := 2*o/sqrt2, (-3*s-2*o)/sqrt6
+
+	dst := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for py := 0; py < size; py++ {
+		for px := 0; px < size; px++ {
+			x := minX + (float64(px)+0.5-offX)/k
+			y := minY + (float64(py)+0.5)/k
+			c, _ := cubeColor(img, head, 0, 8, x, y)
+			if withHat {
+				if h, ok := cubeColor(img, hat, o, s, x, y); ok {
+					c = composite(h, c)
+				}
+			}
+			dst.SetNRGBA(px, py, c)
+		}
+	}
+	return dst, nil
+}
+
+// cubeColor returns the shaded colour of the texel of img visible at the
+// projected coordinates (x, y) of a cube spanning [o, o+s] on every axis,
+// textured with the faces of p, and true. If the cube isn't visible at (x, y),
+// cubeColor returns a transparent colour and false.
+//
+// The cube is projected such that x = (cx+cz)/√2 and y = (cz-cx-2cy)/√6 for a
+// point (cx, cy, cz) of it, where cx increases towards the player's left, cy
+// upwards and cz towards the player's front.
+func cubeColor(img image.Image, p part, o, s, x, y float64) (color.NRGBA, bool) {
+	e := o + s // The visible top, front and right faces are at cy, cz, cx = e, e, o
+	var (
+		face  int
+		u, v  float64 // Coordinates within face, from o to e
+		shade float64
+	)
+	if cx, cz := (sqrt2*x-sqrt6*y-2*e)/2, (sqrt2*x+sqrt6*y+2*e)/2; within(cx, o, e) && within(cz, o, e) {
+		face, u, v, shade = top, cx, cz, topShade
+	} else if cx := sqrt2*x - e; within(cx, o, e) && within((e-cx-sqrt6*y)/2, o, e) {
+		face, u, v, shade = front, cx, e-(e-cx-sqrt6*y)/2+o, frontShade
+	} else if cz := sqrt2*x - o; within(cz, o, e) && within((cz-o-sqrt6*y)/2, o, e) {
+		face, u, v, shade = right, cz, e-(cz-o-sqrt6*y)/2+o, sideShade
+	} else {
+		return color.NRGBA{}, false
+	}
+
+	r := p.faces()[face]
+	tu := int(math.Min((u-o)*float64(r.Dx())/s, float64(r.Dx()-1)))
+	tv := int(math.Min((v-o)*float64(r.Dy())/s, float64(r.Dy()-1)))
+	c := color.NRGBAModel.Convert(img.At(img.Bounds().Min.X+r.Min.X+tu, img.Bounds().Min.Y+r.Min.Y+tv)).(color.NRGBA)
+	c.R = uint8(float64(c.R) * shade)
+	c.G = uint8(float64(c.G) * shade)
+	c.B = uint8(float64(c.B) * shade)
+	return c, true
+}
+
+// within reports whether min <= f < max.
+func within(f, min, max float64) bool {
+	return min <= f && f < max
+}
+
+// composite returns src composited over dst.
+func composite(src, dst color.NRGBA) color.NRGBA {
+	sa := uint32(src.A)
+	da := uint32(dst.A) * (0xff - sa) / 0xff
+	a := sa + da
+	if a == 0 {
+		return color.NRGBA{}
+	}
+	blend := func(s, d uint8) uint8 {
+		return uint8((uint32(s)*sa + uint32(d)*da) / a)
+	}
+	return color.NRGBA{
+		R: blend(src.R, dst.R),
+		G: blend(src.G, dst.G),
+		B: blend(src.B, dst.B),
+		A: uint8(a),
+	}
+}
@@ -0,0 +1,149 @@
+package skin
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+var (
+	topColor   = color.NRGBA{R: 0xff, A: 0xff}
+	frontColor = color.NRGBA{G: 0xff, A: 0xff}
+	sideColor  = color.NRGBA{B: 0xff, A: 0xff}
+)
+
+// Pixels of a 90x90 pixels cube which show the centres of its faces.
+var (
+	topCentre   = image.Pt(45, 25)
+	frontCentre = image.Pt(62, 55)
+	sideCentre  = image.Pt(28, 55)
+)
+
+var testIsometricInput = [...]struct {
+	desc string
+	img  image.Image
+	// expected colours of the pixels of a 90x90 pixels cube
+	exp map[image.Point]color.NRGBA
+}{
+	{
+		desc: "skin without hat",
+		img:  headFaces(transparent(opaque(Width, Height), image.Rect(32, 0, 64, 16))),
+		exp: map[image.Point]color.NRGBA{
+			topCentre:        shaded(topColor, topShade),
+			frontCentre:      shaded(frontColor, frontShade),
+			sideCentre:       shaded(sideColor, sideShade),
+			image.Pt(0, 0):   {},
+			image.Pt(89, 89): {},
+			image.Pt(2, 45):  {},
+		},
+	},
+	{
+		desc: "skin with hat",
+		img:  paint(headFaces(transparent(opaque(Width, Height), image.Rect(32, 0, 64, 16))), hat.faces()[front], hatColor),
+		exp: map[image.Point]color.NRGBA{
+			topCentre:   shaded(topColor, topShade),
+			frontCentre: shaded(hatColor, frontShade),
+			sideCentre:  shaded(sideColor, sideShade),
+		},
+	},
+	{
+		desc: "legacy skin with opaque hat",
+		img:  paint(headFaces(opaque(Width, LegacyHeight)), hat.faces()[front], hatColor),
+		exp: map[image.Point]color.NRGBA{
+			topCentre:   shaded(topColor, topShade),
+			frontCentre: shaded(frontColor, frontShade),
+			sideCentre:  shaded(sideColor, sideShade),
+		},
+	},
+}
+
+func TestIsometric(t *testing.T) {
+	for _, tc := range testIsometricInput {
+		img, err := Isometric(tc.img, 90)
+		if err != nil {
+			t.Errorf("Isometric(%s, 90) failed: %s", tc.desc, err)
+			continue
+		}
+		if b, exp := img.Bounds(), image.Rect(0, 0, 90, 90); b != exp {
+			t.Errorf("Isometric(%s, 90) has bounds %s; want %s", tc.desc, b, exp)
+			continue
+		}
+		for pt, exp := range tc.exp {
+			if c := color.NRGBAModel.Convert(img.At(pt.X, pt.Y)).(color.NRGBA); c != exp {
+				t.Errorf("Isometric(%s, 90) has colour %v at %s; want %v", tc.desc, c, pt, exp)
+			}
+		}
+	}
+}
+
+func TestIsometricDimensions(t *testing.T) {
+	if img, err := Isometric(opaque(32, 32), 8); img != nil || err != ErrDimensions {
+		t.Errorf("Isometric(32x32 image, 8) was %v, %v; want <nil>, %s", img, err, ErrDimensions)
+	}
+}
+
+func TestIsometricPanic(t *testing.T) {
+	const exp = "minecraft/skin: non-positive size passed to Isometric"
+	defer func() {
+		if r := recover(); r != exp {
+			t.Errorf("Isometric(img, 0) panicked with %#v; want %q", r, exp)
+		}
+	}()
+	Isometric(opaque(Width, Height), 0)
+}
+
+func TestComposite(t *testing.T) {
+	for _, tc := range [...]struct {
+		src, dst, exp color.NRGBA
+	}{
+		{src: hatColor, dst: faceColor, exp: hatColor},
+		{src: color.NRGBA{}, dst: faceColor, exp: faceColor},
+		{src: halfHat, dst: color.NRGBA{}, exp: halfHat},
+		{src: color.NRGBA{}, dst: color.NRGBA{}, exp: color.NRGBA{}},
+		{src: halfHat, dst: faceColor, exp: color.NRGBAModel.Convert(over(halfHat, faceColor)).(color.NRGBA)},
+	} {
+		if c := composite(tc.src, tc.dst); !closeTo(c, tc.exp) {
+			t.Errorf("composite(%v, %v) was %v; want %v", tc.src, tc.dst, c, tc.exp)
+		}
+	}
+}
+
+/***************
+*  TEST UTILS  *
+***************/
+
+// headFaces paints the top, front and right faces of the head of img with
+// topColor, frontColor and sideColor.
+func headFaces(img *image.NRGBA) *image.NRGBA {
+	fs := head.faces()
+	paint(img, fs[top], topColor)
+	paint(img, fs[front], frontColor)
+	return paint(img, fs[right], sideColor)
+}
+
+// paint paints the area r of img with c.
+func paint(img *image.NRGBA, r image.Rectangle, c color.NRGBA) *image.NRGBA {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+// shaded returns c with its colour channels multiplied by shade.
+func shaded(c color.NRGBA, shade float64) color.NRGBA {
+	return color.NRGBA{
+		R: uint8(float64(c.R) * shade),
+		G: uint8(float64(c.G) * shade),
+		B: uint8(float64(c.B) * shade),
+		A: c.A,
+	}
+}
+
+// closeTo reports whether the channels of a and b differ by at most 1, allowing
+// for rounding errors.
+func closeTo(a, b color.NRGBA) bool {
+	d := func(x, y uint8) bool { return x-y <= 1 || y-x <= 1 }
+	return d(a.R, b.R) && d(a.G, b.G) && d(a.B, b.B) && d(a.A, b.A)
+}
@@ -0,0 +1,51 @@
+package skin
+
+import (
+	"image"
+	"image/draw"
+)
+
+// BaseLayer returns the base layer of the skin img, i.e. the textures of the
+// body parts without their overlays, e.g. to edit the base layer independently.
+// The returned image has the dimensions of img and its bounds start at (0,0);
+// all areas of it not used by the base layer are fully transparent. The arm
+// regions of the classic player model are copied, which include those of the
+// slim-armed model.
+//
+// If img isn't 64x64 or 64x32 pixels, ErrDimensions is returned.
+func BaseLayer(img image.Image) (image.Image, error) {
+	legacy, err := isLegacy(img)
+	if err != nil {
+		return nil, err
+	}
+	return extract(img, baseLayer(legacy, false)), nil
+}
+
+// OverlayLayer returns the overlay of the skin img, i.e. the hat, jacket,
+// sleeves and pants drawn on top of the base layer, e.g. to edit the overlay
+// independently. The returned image has the dimensions of img and its bounds
+// start at (0,0); all areas of it not used by the overlay are fully
+// transparent. Since legacy skins only have the hat overlay, only the hat is
+// copied from them.
+//
+// If img isn't 64x64 or 64x32 pixels, ErrDimensions is returned.
+func OverlayLayer(img image.Image) (image.Image, error) {
+	legacy, err := isLegacy(img)
+	if err != nil {
+		return nil, err
+	}
+	return extract(img, overlay(legacy)), nil
+}
+
+// extract returns a copy of the parts ps of img on a transparent canvas of the
+// same size as img.
+func extract(img image.Image, ps []part) *image.NRGBA {
+	b := img.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	for _, p := range ps {
+		for _, r := range p.faces() {
+			draw.Draw(dst, r, img, b.Min.Add(r.Min), draw.Src)
+		}
+	}
+	return dst
+}
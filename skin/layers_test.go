@@ -0,0 +1,110 @@
+package skin
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+var testLayersInput = [...]struct {
+	desc string
+	img  image.Image
+	// pixels expected to be opaque in the base layer and the overlay
+	expBase, expOverlay []image.Point
+	// pixels expected to be transparent in both layers
+	expNeither []image.Point
+}{
+	{
+		desc:       "opaque skin",
+		img:        opaque(Width, Height),
+		expBase:    []image.Point{{8, 8}, {20, 20}, {44, 20}, {4, 20}, {36, 52}, {20, 52}},
+		expOverlay: []image.Point{{40, 8}, {20, 36}, {44, 36}, {4, 36}, {52, 52}, {4, 52}},
+		expNeither: []image.Point{{0, 0}, {60, 2}, {0, 16}, {60, 40}, {0, 48}},
+	},
+	{
+		desc:       "opaque legacy skin",
+		img:        opaque(Width, LegacyHeight),
+		expBase:    []image.Point{{8, 8}, {20, 20}, {44, 20}, {4, 20}},
+		expOverlay: []image.Point{{40, 8}},
+		expNeither: []image.Point{{0, 0}, {60, 2}, {0, 16}, {60, 20}},
+	},
+	{
+		desc:       "skin with offset bounds",
+		img:        opaqueAt(image.Rect(-5, 7, -5+Width, 7+Height)),
+		expBase:    []image.Point{{8, 8}, {36, 52}},
+		expOverlay: []image.Point{{40, 8}, {52, 52}},
+		expNeither: []image.Point{{0, 0}},
+	},
+}
+
+func TestLayers(t *testing.T) {
+	for _, tc := range testLayersInput {
+		base, err := BaseLayer(tc.img)
+		if err != nil {
+			t.Errorf("BaseLayer(%s) failed: %s", tc.desc, err)
+			continue
+		}
+		over, err := OverlayLayer(tc.img)
+		if err != nil {
+			t.Errorf("OverlayLayer(%s) failed: %s", tc.desc, err)
+			continue
+		}
+
+		size := tc.img.Bounds().Size()
+		for _, l := range [...]struct {
+			fn     string
+			img    image.Image
+			opaque []image.Point
+		}{
+			{"BaseLayer", base, tc.expBase},
+			{"OverlayLayer", over, tc.expOverlay},
+		} {
+			if b, exp := l.img.Bounds(), image.Rect(0, 0, size.X, size.Y); b != exp {
+				t.Errorf("%s(%s) has bounds %s; want %s", l.fn, tc.desc, b, exp)
+				continue
+			}
+			for _, pt := range l.opaque {
+				if c := l.img.At(pt.X, pt.Y); c != faceColor {
+					t.Errorf("%s(%s) has colour %v at %s; want %v", l.fn, tc.desc, c, pt, faceColor)
+				}
+			}
+			for _, pt := range tc.expNeither {
+				if _, _, _, a := l.img.At(pt.X, pt.Y).RGBA(); a != 0 {
+					t.Errorf("%s(%s) isn't transparent at %s", l.fn, tc.desc, pt)
+				}
+			}
+		}
+
+		// The layers must not overlap
+		for y := 0; y < size.Y; y++ {
+			for x := 0; x < size.X; x++ {
+				_, _, _, ba := base.At(x, y).RGBA()
+				_, _, _, oa := over.At(x, y).RGBA()
+				if ba != 0 && oa != 0 {
+					t.Errorf("BaseLayer(%s) and OverlayLayer(%s) both use (%d,%d)", tc.desc, tc.desc, x, y)
+				}
+			}
+		}
+	}
+}
+
+func TestLayersTransparency(t *testing.T) {
+	img := transparent(opaque(Width, Height), image.Rect(32, 0, 64, 16))
+	img.Set(41, 9, halfHat)
+	over, err := OverlayLayer(img)
+	if err != nil {
+		t.Fatalf("OverlayLayer(skin with translucent hat) failed: %s", err)
+	}
+	if c := color.NRGBAModel.Convert(over.At(41, 9)); c != halfHat {
+		t.Errorf("OverlayLayer(skin with translucent hat) has colour %v at (41,9); want %v", c, halfHat)
+	}
+}
+
+func TestLayersDimensions(t *testing.T) {
+	if l, err := BaseLayer(opaque(32, 32)); l != nil || err != ErrDimensions {
+		t.Errorf("BaseLayer(32x32 image) was %v, %v; want <nil>, %s", l, err, ErrDimensions)
+	}
+	if l, err := OverlayLayer(opaque(32, 32)); l != nil || err != ErrDimensions {
+		t.Errorf("OverlayLayer(32x32 image) was %v, %v; want <nil>, %s", l, err, ErrDimensions)
+	}
+}
@@ -0,0 +1,211 @@
+// Package skin works with Minecraft skin textures, e.g. to validate skins
+// before they are uploaded to Mojang.
+//
+// A skin is a 64x64 pixels image, or a 64x32 pixels image for legacy skins
+// created before Minecraft 1.8. The texture of each body part is laid out as
+// the unfolded faces of a cuboid. Every body part has a base layer, which is
+// drawn opaque, and an overlay, e.g. a hat or jacket, which may be transparent.
+// Legacy skins only have the hat overlay and store a single arm and leg, which
+// is mirrored for the left limbs.
+//
+// For more information on the skin format, see
+// http://minecraft.gamepedia.com/Skin.
+package skin
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+)
+
+const (
+	Width        = 64 // Width of skins in pixels.
+	Height       = 64 // Height of skins in pixels.
+	LegacyHeight = 32 // Height of legacy skins in pixels.
+)
+
+// ErrDimensions is returned when a skin isn't 64x64 or 64x32 pixels.
+var ErrDimensions = errors.New("minecraft/skin: skin must be 64x64 or 64x32 pixels")
+
+// ErrNotPNG is returned by Dimensions when its input isn't a PNG image.
+var ErrNotPNG = errors.New("minecraft/skin: not a PNG image")
+
+// TransparencyError is returned when a pixel of a skin's base layer isn't
+// fully opaque.
+type TransparencyError struct {
+	Part string // Body part with transparent base layer, e.g. "head".
+	X, Y int    // Skin coordinates of the transparent pixel.
+}
+
+func (e *TransparencyError) Error() string {
+	return fmt.Sprintf("minecraft/skin: base layer of %s must be opaque, but pixel (%d,%d) is transparent", e.Part, e.X, e.Y)
+}
+
+// Validate checks that img adheres to the rules Mojang enforces for skins:
+// img must be 64x64 or 64x32 pixels and the base layer of every body part must
+// be fully opaque. Overlays, and areas of img not used by any body part, may be
+// transparent.
+//
+// Since slim-armed skins use narrower arms than classic skins, Validate accepts
+// a skin if its base layer is opaque for either of the two player models. If
+// neither is, the error for the classic model is returned.
+//
+// If img has the wrong dimensions, ErrDimensions is returned. If the base layer
+// isn't opaque, a *TransparencyError is returned.
+func Validate(img image.Image) error {
+	legacy, err := isLegacy(img)
+	if err != nil {
+		return err
+	}
+
+	err = checkOpaque(img, baseLayer(legacy, false))
+	if err != nil && !legacy {
+		if checkOpaque(img, baseLayer(false, true)) == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// pngHeader is the signature of PNG files followed by the length and type of
+// the IHDR chunk, which is the first chunk of every PNG file.
+const pngHeader = "\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR"
+
+// Dimensions returns the width and height of the PNG image read from r, e.g.
+// to reject skins of the wrong size before decoding them. Only the first 24
+// bytes of r are read, which hold the dimensions; the image isn't decoded and
+// may thus be malformed beyond its header. A skin must be Width x Height or
+// Width x LegacyHeight pixels to be valid.
+//
+// If r doesn't start with a PNG header, ErrNotPNG is returned. Other errors
+// reading from r are returned as is.
+func Dimensions(r io.Reader) (w, h int, err error) {
+	var buf [len(pngHeader) + 8]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			err = ErrNotPNG
+		}
+		return 0, 0, err
+	}
+	if string(buf[:len(pngHeader)]) != pngHeader {
+		return 0, 0, ErrNotPNG
+	}
+	w = int(binary.BigEndian.Uint32(buf[len(pngHeader):]))
+	h = int(binary.BigEndian.Uint32(buf[len(pngHeader)+4:]))
+	return w, h, nil
+}
+
+// isLegacy reports whether img is a legacy skin. If img isn't a skin,
+// ErrDimensions is returned.
+func isLegacy(img image.Image) (bool, error) {
+	switch size := img.Bounds().Size(); {
+	case size.X == Width && size.Y == Height:
+		return false, nil
+	case size.X == Width && size.Y == LegacyHeight:
+		return true, nil
+	default:
+		return false, ErrDimensions
+	}
+}
+
+// checkOpaque returns a *TransparencyError for the first pixel of ps in img
+// which isn't fully opaque, or nil if all are.
+func checkOpaque(img image.Image, ps []part) error {
+	min := img.Bounds().Min
+	for _, p := range ps {
+		for _, r := range p.faces() {
+			for y := r.Min.Y; y < r.Max.Y; y++ {
+				for x := r.Min.X; x < r.Max.X; x++ {
+					if _, _, _, a := img.At(min.X+x, min.Y+y).RGBA(); a != 0xffff {
+						return &TransparencyError{Part: p.name, X: x, Y: y}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// part is the texture of a body part, laid out as the faces of a cuboid
+// w pixels wide, h pixels high and d pixels deep, unfolded with its top left
+// corner at (u, v):
+//
+//	        +-------+-------+
+//	        |  top  |bottom |
+//	+-------+-------+-------+-------+
+//	| right | front | left  | back  |
+//	+-------+-------+-------+-------+
+type part struct {
+	name    string
+	u, v    int
+	w, h, d int
+}
+
+// Face indices of the rectangles returned by part.faces.
+const (
+	top = iota
+	bottom
+	right
+	front
+	left
+	back
+)
+
+// faces returns the rectangles of the skin containing the faces of p.
+func (p part) faces() [6]image.Rectangle {
+	u, v, w, h, d := p.u, p.v, p.w, p.h, p.d
+	return [6]image.Rectangle{
+		top:    image.Rect(u+d, v, u+d+w, v+d),
+		bottom: image.Rect(u+d+w, v, u+d+w+w, v+d),
+		right:  image.Rect(u, v+d, u+d, v+d+h),
+		front:  image.Rect(u+d, v+d, u+d+w, v+d+h),
+		left:   image.Rect(u+d+w, v+d, u+d+w+d, v+d+h),
+		back:   image.Rect(u+d+w+d, v+d, u+d+w+d+w, v+d+h),
+	}
+}
+
+// The parts making up a player's head.
+var (
+	head = part{name: "head", u: 0, v: 0, w: 8, h: 8, d: 8}
+	hat  = part{name: "hat", u: 32, v: 0, w: 8, h: 8, d: 8}
+)
+
+// baseLayer returns the base layer parts of a skin. slim selects whether
+// the arms are those of the slim-armed player model.
+func baseLayer(legacy, slim bool) []part {
+	armW := 4
+	if slim {
+		armW = 3
+	}
+	ps := []part{
+		head,
+		{name: "body", u: 16, v: 16, w: 8, h: 12, d: 4},
+		{name: "right arm", u: 40, v: 16, w: armW, h: 12, d: 4},
+		{name: "right leg", u: 0, v: 16, w: 4, h: 12, d: 4},
+	}
+	if !legacy {
+		ps = append(ps,
+			part{name: "left arm", u: 32, v: 48, w: armW, h: 12, d: 4},
+			part{name: "left leg", u: 16, v: 48, w: 4, h: 12, d: 4},
+		)
+	}
+	return ps
+}
+
+// overlay returns the overlay parts of a skin, using the arms of the classic
+// player model, which cover those of the slim-armed model.
+func overlay(legacy bool) []part {
+	ps := []part{hat}
+	if !legacy {
+		ps = append(ps,
+			part{name: "jacket", u: 16, v: 32, w: 8, h: 12, d: 4},
+			part{name: "right sleeve", u: 40, v: 32, w: 4, h: 12, d: 4},
+			part{name: "right pants", u: 0, v: 32, w: 4, h: 12, d: 4},
+			part{name: "left sleeve", u: 48, v: 48, w: 4, h: 12, d: 4},
+			part{name: "left pants", u: 0, v: 48, w: 4, h: 12, d: 4},
+		)
+	}
+	return ps
+}
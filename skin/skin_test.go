@@ -0,0 +1,204 @@
+package skin
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"reflect"
+	"testing"
+)
+
+var testValidateInput = [...]struct {
+	desc   string
+	img    image.Image
+	expErr error
+}{
+	{
+		desc: "opaque skin",
+		img:  opaque(Width, Height),
+	},
+	{
+		desc: "opaque legacy skin",
+		img:  opaque(Width, LegacyHeight),
+	},
+	{
+		desc:   "too small image",
+		img:    opaque(32, 32),
+		expErr: ErrDimensions,
+	},
+	{
+		desc:   "too large image",
+		img:    opaque(128, 128),
+		expErr: ErrDimensions,
+	},
+	{
+		desc: "transparent hat",
+		img:  transparent(opaque(Width, Height), image.Rect(32, 0, 64, 16)),
+	},
+	{
+		desc: "transparent unused areas",
+		img:  transparent(opaque(Width, Height), image.Rect(0, 0, 8, 8), image.Rect(56, 16, 64, 20)),
+	},
+	{
+		desc:   "transparent head front",
+		img:    transparent(opaque(Width, Height), image.Rect(10, 12, 11, 13)),
+		expErr: &TransparencyError{Part: "head", X: 10, Y: 12},
+	},
+	{
+		desc:   "transparent body back",
+		img:    transparent(opaque(Width, Height), image.Rect(39, 31, 40, 32)),
+		expErr: &TransparencyError{Part: "body", X: 39, Y: 31},
+	},
+	{
+		desc:   "transparent left leg",
+		img:    transparent(opaque(Width, Height), image.Rect(20, 52, 24, 64)),
+		expErr: &TransparencyError{Part: "left leg", X: 20, Y: 52},
+	},
+	{
+		desc:   "transparent leg of legacy skin",
+		img:    transparent(opaque(Width, LegacyHeight), image.Rect(0, 16, 16, 32)),
+		expErr: &TransparencyError{Part: "right leg", X: 4, Y: 16},
+	},
+	{
+		desc: "slim skin",
+		img:  slim(opaque(Width, Height)),
+	},
+	{
+		desc:   "slim legacy skin",
+		img:    slim(opaque(Width, LegacyHeight)),
+		expErr: &TransparencyError{Part: "right arm", X: 50, Y: 16},
+	},
+	{
+		desc:   "slim skin with transparent left arm",
+		img:    transparent(slim(opaque(Width, Height)), image.Rect(36, 52, 39, 64)),
+		expErr: &TransparencyError{Part: "right arm", X: 50, Y: 16},
+	},
+	{
+		desc:   "transparent skin with offset bounds",
+		img:    image.NewNRGBA(image.Rect(10, 10, 10+Width, 10+Height)),
+		expErr: &TransparencyError{Part: "head", X: 8, Y: 0},
+	},
+	{
+		desc: "opaque skin with offset bounds",
+		img:  opaqueAt(image.Rect(-5, 7, -5+Width, 7+Height)),
+	},
+}
+
+func TestValidate(t *testing.T) {
+	for _, tc := range testValidateInput {
+		if err := Validate(tc.img); !reflect.DeepEqual(err, tc.expErr) {
+			t.Errorf(
+				"Validate(%s)\n"+
+					" was: %#v\n"+
+					"want: %#v",
+				tc.desc, err, tc.expErr,
+			)
+		}
+	}
+}
+
+var testDimensionsInput = [...]struct {
+	desc   string
+	data   []byte
+	expW   int
+	expH   int
+	expErr error
+}{
+	{desc: "skin", data: encodePNG(opaque(Width, Height)), expW: Width, expH: Height},
+	{desc: "legacy skin", data: encodePNG(opaque(Width, LegacyHeight)), expW: Width, expH: LegacyHeight},
+	{desc: "other image", data: encodePNG(opaque(300, 7)), expW: 300, expH: 7},
+	{desc: "truncated PNG", data: encodePNG(opaque(Width, Height))[:24], expW: Width, expH: Height},
+	{desc: "PNG header", data: encodePNG(opaque(Width, Height))[:20], expErr: ErrNotPNG},
+	{desc: "empty input", data: nil, expErr: ErrNotPNG},
+	{desc: "GIF image", data: []byte("GIF89a\x40\x00\x40\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00"), expErr: ErrNotPNG},
+}
+
+func TestDimensions(t *testing.T) {
+	for _, tc := range testDimensionsInput {
+		w, h, err := Dimensions(bytes.NewReader(tc.data))
+		if w != tc.expW || h != tc.expH || err != tc.expErr {
+			t.Errorf(
+				"Dimensions(%s)\n"+
+					" was: %d, %d, %v\n"+
+					"want: %d, %d, %v",
+				tc.desc, w, h, err, tc.expW, tc.expH, tc.expErr,
+			)
+		}
+	}
+}
+
+func TestDimensionsReadError(t *testing.T) {
+	testError := errors.New("read failed")
+	if w, h, err := Dimensions(errReader{testError}); w != 0 || h != 0 || err != testError {
+		t.Errorf("Dimensions(failing reader) was %d, %d, %v; want 0, 0, %s", w, h, err, testError)
+	}
+}
+
+func TestTransparencyError_Error(t *testing.T) {
+	err := &TransparencyError{Part: "right arm", X: 44, Y: 20}
+	exp := "minecraft/skin: base layer of right arm must be opaque, but pixel (44,20) is transparent"
+	if s := err.Error(); s != exp {
+		t.Errorf("%#v.Error() = %q; want %q", err, s, exp)
+	}
+}
+
+/***************
+*  TEST UTILS  *
+***************/
+
+// opaque returns a fully opaque image of the given dimensions.
+func opaque(w, h int) *image.NRGBA {
+	return opaqueAt(image.Rect(0, 0, w, h))
+}
+
+// opaqueAt returns a fully opaque image with bounds r.
+func opaqueAt(r image.Rectangle) *image.NRGBA {
+	img := image.NewNRGBA(r)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.Set(x, y, color.NRGBA{R: 0x80, G: 0x40, B: 0x20, A: 0xff})
+		}
+	}
+	return img
+}
+
+// transparent makes the areas rs of img fully transparent.
+func transparent(img *image.NRGBA, rs ...image.Rectangle) *image.NRGBA {
+	for _, r := range rs {
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				img.Set(x, y, color.Transparent)
+			}
+		}
+	}
+	return img
+}
+
+// slim makes the columns of img's arms which are only used by the classic
+// player model transparent.
+func slim(img *image.NRGBA) *image.NRGBA {
+	return transparent(img,
+		image.Rect(50, 16, 52, 20), image.Rect(54, 20, 56, 32),
+		image.Rect(42, 48, 44, 52), image.Rect(46, 52, 48, 64),
+	)
+}
+
+// encodePNG returns img encoded as PNG.
+func encodePNG(img image.Image) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}
+
+// errReader fails every read with err.
+type errReader struct {
+	err error
+}
+
+func (er errReader) Read(_ []byte) (int, error) {
+	return 0, er.err
+}
@@ -0,0 +1,90 @@
+// Package stats fetches Mojang's sales statistics of Minecraft and Mojang's
+// other games, i.e. how many copies have been sold in total and within the
+// last 24 hours. For example:
+//	s, err := stats.Load(context.TODO(), stats.ItemSoldMinecraft, stats.PrepaidCardRedeemedMinecraft)
+//	if err != nil {
+//		log.Fatal("Failed to fetch sales statistics: " + err.Error())
+//	}
+//	fmt.Printf("Minecraft has been sold %d times\n", s.Total)
+// For more information, see http://wiki.vg/Mojang_API#Statistics.
+package stats
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/PhilipBorgesen/minecraft/internal"
+)
+
+// The endpoint to fetch sales statistics from.
+// For test purposes a cached response should be downloaded to testdata/cached/<SERVER PATH>
+const statisticsURL = "https://api.mojang.com/orders/statistics"
+
+var client = &http.Client{}
+
+// Metric is a sales metric tracked by Mojang. The value of each metric is its
+// key in Mojang's API.
+type Metric string
+
+const (
+	ItemSoldMinecraft            Metric = "item_sold_minecraft"             // Copies of Minecraft sold
+	PrepaidCardRedeemedMinecraft Metric = "prepaid_card_redeemed_minecraft" // Minecraft prepaid cards redeemed
+	ItemSoldCobalt               Metric = "item_sold_cobalt"                // Copies of Cobalt sold
+	PrepaidCardRedeemedCobalt    Metric = "prepaid_card_redeemed_cobalt"    // Cobalt prepaid cards redeemed
+	ItemSoldScrolls              Metric = "item_sold_scrolls"               // Copies of Scrolls sold
+	ItemSoldDungeons             Metric = "item_sold_dungeons"              // Copies of Minecraft Dungeons sold
+)
+
+// ErrNoMetrics is returned by Load when passed no metrics.
+var ErrNoMetrics = errors.New("minecraft/stats: no metrics given")
+
+// Stats is the sales statistics reported by Mojang for a set of metrics.
+type Stats struct {
+	// Total is the sum of the metrics since Mojang started tracking them.
+	Total int64
+	// Last24h is the sum of the metrics within the last 24 hours.
+	Last24h int64
+	// SaleVelocityPerSecond is the average number of sales per second within
+	// the last 24 hours.
+	SaleVelocityPerSecond float64
+
+	_ struct{} // Ensure Stats is constructed using named parameters.
+}
+
+// Load fetches the sales statistics of metrics from Mojang's servers. ctx must
+// be non-nil. The statistics of multiple metrics are summed by Mojang, e.g. to
+// count copies of Minecraft sold incl. prepaid cards redeemed. If no metrics
+// are given, ErrNoMetrics is returned without contacting Mojang's servers.
+//
+// If an error occurs, s will be nil. Load reports Mojang server communication
+// failures using *url.Error.
+func Load(ctx context.Context, metrics ...Metric) (s *Stats, err error) {
+	internal.CheckContext(ctx, "stats", "Load")
+
+	if len(metrics) == 0 {
+		return nil, ErrNoMetrics
+	}
+
+	js, err := internal.ExchangeJSON(ctx, client, statisticsURL, struct {
+		MetricKeys []Metric `json:"metricKeys"`
+	}{metrics})
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { // If JSON data isn't structured as expected
+		if r := recover(); r != nil {
+			s = nil
+			err = &url.Error{Op: "Parse", URL: statisticsURL, Err: internal.ErrUnknownFormat}
+		}
+	}()
+
+	m := js.(map[string]interface{})
+	return &Stats{
+		Total:                 int64(m["total"].(float64)),
+		Last24h:               int64(m["last24h"].(float64)),
+		SaleVelocityPerSecond: m["saleVelocityPerSeconds"].(float64),
+	}, nil
+}
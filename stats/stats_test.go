@@ -0,0 +1,142 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/PhilipBorgesen/minecraft/internal"
+)
+
+var testLoadInput = [...]struct {
+	metrics   []Metric
+	transport http.RoundTripper
+	expStats  *Stats
+	expErr    error
+}{
+	{
+		metrics:   nil,
+		transport: errorTransport{errors.New("RoundTrip was called")},
+		expErr:    ErrNoMetrics,
+	},
+	{
+		metrics:   []Metric{ItemSoldMinecraft, PrepaidCardRedeemedMinecraft},
+		transport: http.NewFileTransport(http.Dir("testdata/cached")),
+		expStats:  &Stats{Total: 40159217, Last24h: 5826, SaleVelocityPerSecond: 0.06579248},
+	},
+	{
+		metrics:   []Metric{ItemSoldMinecraft},
+		transport: http.NewFileTransport(http.Dir("testdata/malstructured")),
+		expErr:    &url.Error{Op: "Parse", URL: statisticsURL, Err: internal.ErrUnknownFormat},
+	},
+	{
+		metrics:   []Metric{ItemSoldMinecraft},
+		transport: http.NewFileTransport(http.Dir("testdata/nonexisting")),
+		expErr:    &url.Error{Op: "Post", URL: statisticsURL, Err: &internal.FailedRequestError{StatusCode: 404}},
+	},
+	{
+		metrics:   []Metric{ItemSoldMinecraft},
+		transport: errorTransport{testError},
+		expErr:    &url.Error{Op: "Post", URL: statisticsURL, Err: testError},
+	},
+}
+
+func TestLoad(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	for _, tc := range testLoadInput {
+		client.Transport = tc.transport
+
+		s, err := Load(context.Background(), tc.metrics...)
+		if !reflect.DeepEqual(s, tc.expStats) || !reflect.DeepEqual(err, tc.expErr) {
+			t.Errorf(
+				"Load(ctx, %q)\n"+
+					" was: %#v, %v\n"+
+					"want: %#v, %v",
+				tc.metrics,
+				s, err,
+				tc.expStats, tc.expErr,
+			)
+		}
+	}
+}
+
+func TestLoadRequestBody(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	bt := &bodyStoreTransport{}
+	client.Transport = bt
+	Load(context.Background(), ItemSoldMinecraft, PrepaidCardRedeemedMinecraft)
+
+	const exp = `{"metricKeys":["item_sold_minecraft","prepaid_card_redeemed_minecraft"]}` + "\n"
+	if bt.body != exp {
+		t.Errorf("Load(ctx, ItemSoldMinecraft, PrepaidCardRedeemedMinecraft) sent body %q; want %q", bt.body, exp)
+	}
+}
+
+func TestLoadContextUsed(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	ctx := context.WithValue(context.Background(), dummy, nil)
+	ct := CtxStoreTransport{}
+
+	client.Transport = &ct
+	Load(ctx, ItemSoldMinecraft)
+
+	if ct.Context != ctx {
+		t.Error("Load(ctx, ItemSoldMinecraft) didn't pass context to underlying http.Client")
+	}
+}
+
+func TestLoadNilContext(t *testing.T) {
+	const exp = "minecraft/stats: nil Context passed to Load"
+	defer func() {
+		if r := recover(); r != exp {
+			t.Errorf("Load(nil) panicked with %#v; want %q", r, exp)
+		}
+	}()
+	Load(nil)
+}
+
+/*** TEST UTILS ***/
+
+var testError = errors.New("test error")
+
+var dummy struct{}
+
+type errorTransport struct {
+	err error
+}
+
+func (t errorTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, t.err
+}
+
+type bodyStoreTransport struct {
+	body string
+}
+
+func (bt *bodyStoreTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	bs, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	bt.body = string(bs)
+	return nil, errors.New("RoundTrip was called")
+}
+
+type CtxStoreTransport struct {
+	Context context.Context
+}
+
+func (ct *CtxStoreTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ct.Context = req.Context()
+	return nil, errors.New("RoundTrip was called")
+}
@@ -0,0 +1,110 @@
+package status
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+const (
+	// pingProtocolVersion is the protocol version sent in the handshake.
+	// By convention -1 is used when pinging to determine the server version.
+	pingProtocolVersion = -1
+	// statusState is the handshake's next state requesting the server status.
+	statusState = 1
+	// maxPacketLength bounds the size of accepted status responses.
+	maxPacketLength = 1 << 21
+)
+
+// requestStatus performs the Server List Ping handshake and status request on
+// conn and returns the JSON status response sent by the server.
+func requestStatus(conn io.ReadWriter, host string, port uint16) ([]byte, error) {
+	// Handshake
+	var hs bytes.Buffer
+	writeVarInt(&hs, 0x00) // Packet ID
+	writeVarInt(&hs, pingProtocolVersion)
+	writeString(&hs, host)
+	binary.Write(&hs, binary.BigEndian, port)
+	writeVarInt(&hs, statusState)
+
+	// Status request
+	var req bytes.Buffer
+	writeVarInt(&req, 0x00) // Packet ID
+
+	var buf bytes.Buffer
+	writePacket(&buf, hs.Bytes())
+	writePacket(&buf, req.Bytes())
+	if _, err := conn.Write(buf.Bytes()); err != nil {
+		return nil, err
+	}
+
+	// Status response
+	r := bufio.NewReader(conn)
+	n, err := readVarInt(r)
+	if err != nil {
+		return nil, err
+	}
+	if n <= 0 || n > maxPacketLength {
+		return nil, ErrInvalidResponse
+	}
+	packet := make([]byte, n)
+	if _, err := io.ReadFull(r, packet); err != nil {
+		return nil, err
+	}
+
+	pr := bytes.NewReader(packet)
+	if id, err := readVarInt(pr); err != nil || id != 0x00 {
+		return nil, ErrInvalidResponse
+	}
+	l, err := readVarInt(pr)
+	if err != nil || l < 0 || int(l) != pr.Len() {
+		return nil, ErrInvalidResponse
+	}
+	js := make([]byte, l)
+	pr.Read(js)
+	return js, nil
+}
+
+// writePacket writes data to buf prefixed by its length.
+func writePacket(buf *bytes.Buffer, data []byte) {
+	writeVarInt(buf, int32(len(data)))
+	buf.Write(data)
+}
+
+// writeString writes s to buf prefixed by its length.
+func writeString(buf *bytes.Buffer, s string) {
+	writeVarInt(buf, int32(len(s)))
+	buf.WriteString(s)
+}
+
+// writeVarInt writes v to buf using the protocol's variable-length encoding of
+// 7 bits per byte, least significant group first.
+func writeVarInt(buf *bytes.Buffer, v int32) {
+	u := uint32(v)
+	for u >= 0x80 {
+		buf.WriteByte(byte(u) | 0x80)
+		u >>= 7
+	}
+	buf.WriteByte(byte(u))
+}
+
+// readVarInt reads a value written by writeVarInt from r. ErrInvalidResponse
+// is returned if the encoded value exceeds 32 bits.
+func readVarInt(r io.ByteReader) (int32, error) {
+	var u uint32
+	for i := uint(0); i < 5; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			if err == io.EOF && i > 0 {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		u |= uint32(b&0x7F) << (7 * i)
+		if b&0x80 == 0 {
+			return int32(u), nil
+		}
+	}
+	return 0, ErrInvalidResponse
+}
@@ -0,0 +1,46 @@
+package status
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+var testVarIntInput = [...]struct {
+	value int32
+	enc   []byte
+}{
+	{value: 0, enc: []byte{0x00}},
+	{value: 1, enc: []byte{0x01}},
+	{value: 127, enc: []byte{0x7f}},
+	{value: 128, enc: []byte{0x80, 0x01}},
+	{value: 255, enc: []byte{0xff, 0x01}},
+	{value: 25565, enc: []byte{0xdd, 0xc7, 0x01}},
+	{value: 2147483647, enc: []byte{0xff, 0xff, 0xff, 0xff, 0x07}},
+	{value: -1, enc: []byte{0xff, 0xff, 0xff, 0xff, 0x0f}},
+	{value: -2147483648, enc: []byte{0x80, 0x80, 0x80, 0x80, 0x08}},
+}
+
+func TestWriteVarInt(t *testing.T) {
+	for _, tc := range testVarIntInput {
+		var buf bytes.Buffer
+		writeVarInt(&buf, tc.value)
+		if enc := buf.Bytes(); !reflect.DeepEqual(enc, tc.enc) {
+			t.Errorf("writeVarInt(buf, %d) wrote % x; want % x", tc.value, enc, tc.enc)
+		}
+	}
+}
+
+func TestReadVarInt(t *testing.T) {
+	for _, tc := range testVarIntInput {
+		v, err := readVarInt(bytes.NewReader(tc.enc))
+		if v != tc.value || err != nil {
+			t.Errorf("readVarInt(% x) was %d, %v; want %d, <nil>", tc.enc, v, err, tc.value)
+		}
+	}
+
+	tooLong := []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0x01}
+	if _, err := readVarInt(bytes.NewReader(tooLong)); err != ErrInvalidResponse {
+		t.Errorf("readVarInt(% x) returned error %v; want %s", tooLong, err, ErrInvalidResponse)
+	}
+}
@@ -0,0 +1,274 @@
+// Package status retrieves the status of Minecraft servers, i.e. their message
+// of the day and number of players online, the same way the game's multiplayer
+// server list does. It implements the Server List Ping protocol described at:
+// http://wiki.vg/Server_List_Ping.
+//
+// Server addresses are resolved like the game does: If no port is given, the
+// address' _minecraft._tcp SRV record is consulted before falling back to the
+// default port 25565.
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/PhilipBorgesen/minecraft/internal"
+	"github.com/PhilipBorgesen/minecraft/uuid"
+)
+
+// DefaultPort is the port Minecraft servers listen on when no port is given
+// and no SRV record exists for the server address.
+const DefaultPort = 25565
+
+// ErrInvalidResponse is returned when a server replies to a status request with
+// a response that doesn't adhere to the Server List Ping protocol.
+var ErrInvalidResponse = errors.New("minecraft/status: invalid server response")
+
+// ServerStatus is the status reported by a Minecraft server.
+type ServerStatus struct {
+	// Description is the server's message of the day with all formatting
+	// removed.
+	Description string
+	// PlayersOnline is the number of players currently playing on the server.
+	PlayersOnline int
+	// MaxPlayers is the maximum number of players which may play on the
+	// server at once.
+	MaxPlayers int
+	// VersionName is the name of the Minecraft version run by the server,
+	// e.g. "1.11.2". Servers are free to report any name.
+	VersionName string
+	// ProtocolVersion is the version of the network protocol spoken by the
+	// server. Clients can only join servers speaking their protocol version.
+	ProtocolVersion int
+	// Sample is a sample of the players currently playing on the server.
+	// Servers may omit the sample or fill it with made-up entries, e.g. to
+	// show text in the player list tooltip of the game.
+	Sample []PlayerRef
+
+	_ struct{} // Ensure ServerStatus is constructed using named parameters.
+}
+
+// PlayerRef refers to a player playing on a server.
+type PlayerRef struct {
+	// Name is the player's username.
+	Name string
+	// UUID is the ID of the player's profile without hyphens, as used by
+	// package github.com/PhilipBorgesen/minecraft/profile. If the server
+	// reported an invalid or nil UUID, as is common for made-up entries,
+	// UUID == "".
+	UUID string
+
+	_ struct{} // Ensure PlayerRef is constructed using named parameters.
+}
+
+var resolver *net.Resolver // nil means net.DefaultResolver.
+
+// SetResolver sets the resolver used to look up SRV records and host names of
+// server addresses. If r is nil, the default resolver of package net is used.
+// Use a custom resolver to query a specific DNS server or to use DNS-over-HTTPS
+// in environments where ordinary DNS queries are blocked.
+//
+// SetResolver must not be called concurrently with other functions of this
+// package. Set the resolver once before retrieving any server status.
+func SetResolver(r *net.Resolver) {
+	resolver = r
+}
+
+// Ping connects to the Minecraft server at address and retrieves its status.
+// address is a host name or IP address, optionally followed by a colon and a
+// port number. ctx must be non-nil and may be used to cancel the ping or set a
+// deadline for it.
+//
+// If the server responds with data which doesn't adhere to the Server List Ping
+// protocol, ErrInvalidResponse is returned.
+func Ping(ctx context.Context, address string) (*ServerStatus, error) {
+	internal.CheckContext(ctx, "status", "Ping")
+
+	host, port := resolve(ctx, address)
+
+	d := net.Dialer{Resolver: resolver}
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(int(port))))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// Abort any blocking reads or writes when ctx is done
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.SetDeadline(time.Unix(1, 0))
+		case <-done:
+		}
+	}()
+
+	js, err := requestStatus(conn, host, port)
+	if err != nil {
+		if ne, ok := err.(net.Error); ok && ne.Timeout() {
+			if deadline, ok := ctx.Deadline(); ok && !time.Now().Before(deadline) {
+				// The conn deadline may pass before ctx's timer fires
+				<-ctx.Done()
+			}
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+
+	var res response
+	if err := json.Unmarshal(js, &res); err != nil {
+		return nil, ErrInvalidResponse
+	}
+	return buildStatus(&res), nil
+}
+
+// IsOnline reports whether the Minecraft server at address responds to status
+// requests. It is a shorthand for IsOnlineErr which discards the error. ctx
+// must be non-nil.
+func IsOnline(ctx context.Context, address string) bool {
+	internal.CheckContext(ctx, "status", "IsOnline")
+
+	ok, _ := IsOnlineErr(ctx, address)
+	return ok
+}
+
+// IsOnlineErr reports whether the Minecraft server at address responds to
+// status requests, i.e. whether it can be pinged using Ping. If it can't, the
+// error returned by Ping is returned as well. ctx must be non-nil.
+func IsOnlineErr(ctx context.Context, address string) (bool, error) {
+	internal.CheckContext(ctx, "status", "IsOnlineErr")
+
+	if _, err := Ping(ctx, address); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// resolve determines the host and port of the server at address.
+// If address doesn't specify a port, its SRV record is looked up, unless
+// address is an IP address, possibly enclosed in square brackets. If no SRV
+// record exists, the host of address is returned along with DefaultPort.
+func resolve(ctx context.Context, address string) (host string, port uint16) {
+	if h, p, err := net.SplitHostPort(address); err == nil {
+		if n, err := strconv.ParseUint(p, 10, 16); err == nil {
+			return h, uint16(n)
+		}
+	}
+
+	if n := len(address); n > 1 && address[0] == '[' && address[n-1] == ']' {
+		address = address[1 : n-1]
+	}
+	if net.ParseIP(address) != nil {
+		return address, DefaultPort // IP addresses have no SRV records
+	}
+
+	_, addrs, err := resolver.LookupSRV(ctx, "minecraft", "tcp", address)
+	if err == nil && len(addrs) > 0 {
+		host := addrs[0].Target
+		if n := len(host); n > 0 && host[n-1] == '.' {
+			host = host[:n-1]
+		}
+		return host, addrs[0].Port
+	}
+	return address, DefaultPort
+}
+
+// response is the JSON status response of a server.
+type response struct {
+	Description json.RawMessage `json:"description"`
+	Players     struct {
+		Max    int             `json:"max"`
+		Online int             `json:"online"`
+		Sample json.RawMessage `json:"sample"`
+	} `json:"players"`
+	Version struct {
+		Name     string `json:"name"`
+		Protocol int    `json:"protocol"`
+	} `json:"version"`
+}
+
+func buildStatus(res *response) *ServerStatus {
+	return &ServerStatus{
+		Description:     plainText(res.Description),
+		PlayersOnline:   res.Players.Online,
+		MaxPlayers:      res.Players.Max,
+		VersionName:     res.Version.Name,
+		ProtocolVersion: res.Version.Protocol,
+		Sample:          buildSample(res.Players.Sample),
+	}
+}
+
+// buildSample returns the player sample of a status response. Malformed
+// entries are ignored.
+func buildSample(sample json.RawMessage) []PlayerRef {
+	var arr []json.RawMessage
+	if json.Unmarshal(sample, &arr) != nil {
+		return nil
+	}
+
+	var ps []PlayerRef
+	for _, e := range arr {
+		var p struct {
+			Name string `json:"name"`
+			ID   string `json:"id"`
+		}
+		if json.Unmarshal(e, &p) != nil || p.Name == "" {
+			continue
+		}
+		ps = append(ps, PlayerRef{Name: p.Name, UUID: undashedUUID(p.ID)})
+	}
+	return ps
+}
+
+const nilUUID = "00000000000000000000000000000000"
+
+// undashedUUID returns id in the undashed form used by package profile, or ""
+// if id isn't a UUID or is the nil UUID.
+func undashedUUID(id string) string {
+	u, err := uuid.ToUndashed(id)
+	if err != nil || u == nilUUID {
+		return ""
+	}
+	return u
+}
+
+// plainText returns the text of a JSON chat component with all formatting
+// removed. A chat component is either a string, an array of components, or an
+// object whose "text" value is followed by components in its "extra" array.
+// Malformed components are ignored.
+func plainText(component json.RawMessage) string {
+	var s string
+	if json.Unmarshal(component, &s) == nil {
+		return s
+	}
+
+	var arr []json.RawMessage
+	if json.Unmarshal(component, &arr) == nil {
+		for _, c := range arr {
+			s += plainText(c)
+		}
+		return s
+	}
+
+	var obj struct {
+		Text  string            `json:"text"`
+		Extra []json.RawMessage `json:"extra"`
+	}
+	if json.Unmarshal(component, &obj) == nil {
+		s = obj.Text
+		for _, c := range obj.Extra {
+			s += plainText(c)
+		}
+	}
+	return s
+}
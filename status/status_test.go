@@ -0,0 +1,275 @@
+package status
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+var testPingInput = [...]struct {
+	response  string
+	expStatus *ServerStatus
+	expErr    error
+}{
+	{
+		response: `{"version":{"name":"1.11.2","protocol":316},"players":{"max":20,"online":3},"description":"A Minecraft Server"}`,
+		expStatus: &ServerStatus{
+			Description:     "A Minecraft Server",
+			PlayersOnline:   3,
+			MaxPlayers:      20,
+			VersionName:     "1.11.2",
+			ProtocolVersion: 316,
+		},
+	},
+	{
+		response: `{"players":{"max":100,"online":0},"description":{"text":"Hello ","extra":[{"text":"world","bold":true},"!"]}}`,
+		expStatus: &ServerStatus{
+			Description:   "Hello world!",
+			PlayersOnline: 0,
+			MaxPlayers:    100,
+		},
+	},
+	{
+		response: `{"players":{"max":10,"online":2,"sample":[{"name":"Nergalic","id":"087cc153-c3db-4ee9-b6d3-2e6f2e8c9d1a"},{"name":"Notch","id":"069A79F4-44E9-4726-A5BE-FCA90E38AAF5"},{"name":"§aWelcome!","id":"00000000-0000-0000-0000-000000000000"},{"name":"Fake","id":"not a UUID"},{"id":"069a79f444e94726a5befca90e38aaf5"},42]},"description":""}`,
+		expStatus: &ServerStatus{
+			PlayersOnline: 2,
+			MaxPlayers:    10,
+			Sample: []PlayerRef{
+				{Name: "Nergalic", UUID: "087cc153c3db4ee9b6d32e6f2e8c9d1a"},
+				{Name: "Notch", UUID: "069a79f444e94726a5befca90e38aaf5"},
+				{Name: "§aWelcome!"},
+				{Name: "Fake"},
+			},
+		},
+	},
+	{
+		response: `{"players":{"max":10,"online":2,"sample":"nobody"},"description":""}`,
+		expStatus: &ServerStatus{
+			PlayersOnline: 2,
+			MaxPlayers:    10,
+		},
+	},
+	{
+		response:  `not JSON`,
+		expStatus: nil,
+		expErr:    ErrInvalidResponse,
+	},
+}
+
+func TestPing(t *testing.T) {
+	for _, tc := range testPingInput {
+		addr, hs := serve(t, []byte(tc.response))
+		status, err := Ping(context.Background(), addr)
+		if !reflect.DeepEqual(status, tc.expStatus) || err != tc.expErr {
+			t.Errorf(
+				"Ping(ctx, %q) with response %s\n"+
+					" was: %#v, %v\n"+
+					"want: %#v, %v",
+				addr, tc.response,
+				status, err,
+				tc.expStatus, tc.expErr,
+			)
+		}
+
+		host, port, _ := net.SplitHostPort(addr)
+		if h := <-hs; h.host != host || strconv.Itoa(int(h.port)) != port || h.version != pingProtocolVersion || h.state != statusState {
+			t.Errorf("Ping(ctx, %q) sent handshake %+v; want host %s, port %s, version %d and state %d",
+				addr, h, host, port, pingProtocolVersion, statusState)
+		}
+	}
+}
+
+func TestPingContextCancelled(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	go func() { // Accept, but never respond
+		conn, err := l.Accept()
+		if err == nil {
+			io.Copy(ioutil.Discard, conn)
+			conn.Close()
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	status, err := Ping(ctx, l.Addr().String())
+	if status != nil || err != context.DeadlineExceeded {
+		t.Errorf("Ping(ctx, %q) on unresponsive server was %#v, %v; want <nil>, %s",
+			l.Addr(), status, err, context.DeadlineExceeded)
+	}
+}
+
+func TestPingNilContext(t *testing.T) {
+	const exp = "minecraft/status: nil Context passed to Ping"
+	defer func() {
+		if r := recover(); r != exp {
+			t.Errorf("Ping(nil, \"localhost\") panicked with %#v; want %q", r, exp)
+		}
+	}()
+	Ping(nil, "localhost")
+}
+
+func TestIsOnline(t *testing.T) {
+	addr, _ := serve(t, []byte(`{"players":{"max":20,"online":0},"description":""}`))
+	if online, err := IsOnlineErr(context.Background(), addr); !online || err != nil {
+		t.Errorf("IsOnlineErr(ctx, %q) on online server was %t, %v; want true, <nil>", addr, online, err)
+	}
+
+	addr, _ = serve(t, []byte(`not JSON`))
+	if online, err := IsOnlineErr(context.Background(), addr); online || err != ErrInvalidResponse {
+		t.Errorf("IsOnlineErr(ctx, %q) on misbehaving server was %t, %v; want false, %s", addr, online, err, ErrInvalidResponse)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr = l.Addr().String()
+	l.Close() // Nothing listens on addr anymore
+	if online := IsOnline(context.Background(), addr); online {
+		t.Errorf("IsOnline(ctx, %q) on offline server was true; want false", addr)
+	}
+}
+
+func TestSetResolver(t *testing.T) {
+	defer SetResolver(nil)
+
+	errDial := errors.New("dial not permitted")
+	called := false
+	SetResolver(&net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			called = true
+			return nil, errDial
+		},
+	})
+
+	if _, err := Ping(context.Background(), "mc.example.com"); err == nil {
+		t.Error("Ping(ctx, \"mc.example.com\") succeeded even though DNS lookups fail")
+	}
+	if !called {
+		t.Error("Ping(ctx, \"mc.example.com\") didn't use resolver set by SetResolver")
+	}
+}
+
+var testResolveInput = [...]struct {
+	address string
+	expHost string
+	expPort uint16
+}{
+	{address: "127.0.0.1:25566", expHost: "127.0.0.1", expPort: 25566},
+	{address: "[::1]:1337", expHost: "::1", expPort: 1337},
+	{address: "localhost:80", expHost: "localhost", expPort: 80},
+	{address: "127.0.0.1", expHost: "127.0.0.1", expPort: DefaultPort},
+	{address: "[::1]", expHost: "::1", expPort: DefaultPort},
+	{address: "::1", expHost: "::1", expPort: DefaultPort},
+}
+
+func TestResolve(t *testing.T) {
+	defer SetResolver(nil)
+
+	looked := false
+	SetResolver(&net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			looked = true
+			return nil, errors.New("dial not permitted")
+		},
+	})
+
+	for _, tc := range testResolveInput {
+		looked = false
+		host, port := resolve(context.Background(), tc.address)
+		if host != tc.expHost || port != tc.expPort {
+			t.Errorf("resolve(ctx, %q) was %q, %d; want %q, %d", tc.address, host, port, tc.expHost, tc.expPort)
+		}
+		if looked {
+			t.Errorf("resolve(ctx, %q) looked up an SRV record; want none", tc.address)
+		}
+	}
+}
+
+/***************
+*  TEST UTILS  *
+***************/
+
+type handshake struct {
+	version int32
+	host    string
+	port    uint16
+	state   int32
+}
+
+// serve starts a server accepting a single status request, replying with
+// response. The handshake received is sent on the returned channel.
+func serve(t *testing.T, response []byte) (addr string, hs <-chan handshake) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := make(chan handshake, 1)
+	go func() {
+		defer l.Close()
+		conn, err := l.Accept()
+		if err != nil {
+			c <- handshake{}
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		h, _ := readHandshake(r)
+		c <- h
+		readPacket(r) // Status request
+
+		var data, buf bytes.Buffer
+		writeVarInt(&data, 0x00)
+		writeVarInt(&data, int32(len(response)))
+		data.Write(response)
+		writePacket(&buf, data.Bytes())
+		conn.Write(buf.Bytes())
+	}()
+
+	return l.Addr().String(), c
+}
+
+func readPacket(r *bufio.Reader) (*bytes.Reader, error) {
+	n, err := readVarInt(r)
+	if err != nil {
+		return nil, err
+	}
+	p := make([]byte, n)
+	_, err = io.ReadFull(r, p)
+	return bytes.NewReader(p), err
+}
+
+func readHandshake(r *bufio.Reader) (h handshake, err error) {
+	p, err := readPacket(r)
+	if err != nil {
+		return h, err
+	}
+	readVarInt(p) // Packet ID
+	h.version, _ = readVarInt(p)
+	l, _ := readVarInt(p)
+	host := make([]byte, l)
+	p.Read(host)
+	h.host = string(host)
+	binary.Read(p, binary.BigEndian, &h.port)
+	h.state, err = readVarInt(p)
+	return h, err
+}
@@ -0,0 +1,55 @@
+// Package uuid converts between the textual forms of the universally unique
+// identifiers used by Minecraft to identify profiles.
+//
+// Mojang's API identifies profiles by undashed UUIDs such as
+// "069a79f444e94726a5befca90e38aaf5", while the game, e.g. in the files of
+// Minecraft servers, uses the hyphenated 8-4-4-4-12 form such as
+// "069a79f4-44e9-4726-a5be-fca90e38aaf5". The functions of this package accept
+// either form, in upper or lower case, and always return lower case UUIDs, so
+// they may be applied to the same UUID repeatedly.
+package uuid
+
+import "errors"
+
+// ErrInvalid is returned when converting a string which isn't a UUID.
+var ErrInvalid = errors.New("minecraft/uuid: invalid UUID")
+
+// ToHyphenated returns id in the hyphenated form of UUIDs,
+// e.g. "069a79f4-44e9-4726-a5be-fca90e38aaf5". If id isn't a UUID, ErrInvalid
+// is returned.
+func ToHyphenated(id string) (string, error) {
+	u, err := ToUndashed(id)
+	if err != nil {
+		return "", err
+	}
+	return u[0:8] + "-" + u[8:12] + "-" + u[12:16] + "-" + u[16:20] + "-" + u[20:32], nil
+}
+
+// ToUndashed returns id in the undashed form of UUIDs used by Mojang's API,
+// e.g. "069a79f444e94726a5befca90e38aaf5". If id isn't a UUID, ErrInvalid is
+// returned.
+func ToUndashed(id string) (string, error) {
+	switch len(id) {
+	case 32:
+	case 36:
+		if id[8] != '-' || id[13] != '-' || id[18] != '-' || id[23] != '-' {
+			return "", ErrInvalid
+		}
+		id = id[0:8] + id[9:13] + id[14:18] + id[19:23] + id[24:36]
+	default:
+		return "", ErrInvalid
+	}
+
+	var buf [32]byte
+	for i := 0; i < len(buf); i++ {
+		switch c := id[i]; {
+		case c >= '0' && c <= '9', c >= 'a' && c <= 'f':
+			buf[i] = c
+		case c >= 'A' && c <= 'F':
+			buf[i] = c + 'a' - 'A'
+		default:
+			return "", ErrInvalid
+		}
+	}
+	return string(buf[:]), nil
+}
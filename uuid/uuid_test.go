@@ -0,0 +1,77 @@
+package uuid
+
+import "testing"
+
+var testConvertInput = [...]struct {
+	id        string
+	expHyph   string
+	expUndash string
+	expErr    error
+}{
+	{
+		id:        "069a79f444e94726a5befca90e38aaf5",
+		expHyph:   "069a79f4-44e9-4726-a5be-fca90e38aaf5",
+		expUndash: "069a79f444e94726a5befca90e38aaf5",
+	},
+	{ // Already hyphenated
+		id:        "069a79f4-44e9-4726-a5be-fca90e38aaf5",
+		expHyph:   "069a79f4-44e9-4726-a5be-fca90e38aaf5",
+		expUndash: "069a79f444e94726a5befca90e38aaf5",
+	},
+	{ // Upper case
+		id:        "069A79F444E94726A5BEFCA90E38AAF5",
+		expHyph:   "069a79f4-44e9-4726-a5be-fca90e38aaf5",
+		expUndash: "069a79f444e94726a5befca90e38aaf5",
+	},
+	{ // Upper case, hyphenated
+		id:        "069A79F4-44E9-4726-A5BE-FCA90E38AAF5",
+		expHyph:   "069a79f4-44e9-4726-a5be-fca90e38aaf5",
+		expUndash: "069a79f444e94726a5befca90e38aaf5",
+	},
+	{ // Empty
+		id:     "",
+		expErr: ErrInvalid,
+	},
+	{ // Too short
+		id:     "069a79f444e94726a5befca90e38aaf",
+		expErr: ErrInvalid,
+	},
+	{ // Too long
+		id:     "069a79f444e94726a5befca90e38aaf50",
+		expErr: ErrInvalid,
+	},
+	{ // Misplaced hyphens
+		id:     "069a79f44-4e9-4726-a5be-fca90e38aaf5",
+		expErr: ErrInvalid,
+	},
+	{ // Hyphens in undashed length
+		id:     "069a79f4-4e94726a5befca90e38aaf5",
+		expErr: ErrInvalid,
+	},
+	{ // Not hexadecimal
+		id:     "069a79f444e94726a5befca90e38aag5",
+		expErr: ErrInvalid,
+	},
+	{ // Extra hyphen in hyphenated length
+		id:     "069a79f4-44e9-4726-a5be-fca90e38a-f5",
+		expErr: ErrInvalid,
+	},
+}
+
+func TestToHyphenated(t *testing.T) {
+	for _, tc := range testConvertInput {
+		id, err := ToHyphenated(tc.id)
+		if id != tc.expHyph || err != tc.expErr {
+			t.Errorf("ToHyphenated(%q) was %q, %v; want %q, %v", tc.id, id, err, tc.expHyph, tc.expErr)
+		}
+	}
+}
+
+func TestToUndashed(t *testing.T) {
+	for _, tc := range testConvertInput {
+		id, err := ToUndashed(tc.id)
+		if id != tc.expUndash || err != tc.expErr {
+			t.Errorf("ToUndashed(%q) was %q, %v; want %q, %v", tc.id, id, err, tc.expUndash, tc.expErr)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package versions
+
+import (
+	"context"
+	"io"
+	"net/url"
+
+	"github.com/PhilipBorgesen/minecraft/internal"
+)
+
+// Details is the information held by a version's JSON manifest, describing
+// what a launcher needs to install and launch the version.
+type Details struct {
+	ID         string     // Version identifier, e.g. "1.11.2".
+	MainClass  string     // Java class to launch the game client by.
+	AssetIndex AssetIndex // Index of the game assets used by the version.
+	Libraries  []Library  // Libraries needed by the game client.
+	Downloads  Downloads  // Artifacts of the version which may be downloaded.
+
+	_ struct{} // Ensure Details is constructed using named parameters.
+}
+
+// Downloads holds the artifacts which may be downloaded for a version. An
+// artifact that isn't available for the version, e.g. server mappings of
+// versions before 1.14.4, is the zero Download.
+type Downloads struct {
+	Client         Download // The game client jar
+	Server         Download // The dedicated server jar
+	ClientMappings Download // Obfuscation mappings of the client
+	ServerMappings Download // Obfuscation mappings of the server
+}
+
+// Download is a file which may be downloaded.
+type Download struct {
+	URL  string // Where the file may be downloaded from.
+	SHA1 string // Hex-encoded SHA1 checksum of the file.
+	Size int64  // Size of the file in bytes.
+	// Path is where a library's file is stored, relative to the launcher's
+	// libraries directory. Path is empty for files which aren't libraries.
+	Path string
+}
+
+// Verify reads r until EOF and checks that the data read is the file d, i.e.
+// that it has the SHA1 checksum d.SHA1, as described for VerifyReader. If d
+// has no checksum, e.g. because it's the zero Download of an artifact not
+// available for a version, ErrNoDownload is returned without reading r.
+func (d Download) Verify(r io.Reader) error {
+	if d.SHA1 == "" {
+		return ErrNoDownload
+	}
+	return VerifyReader(r, d.SHA1)
+}
+
+// AssetIndex identifies the index of the game assets, e.g. sounds and
+// translations, used by a version.
+type AssetIndex struct {
+	ID string // Asset index identifier, e.g. "1.11".
+	Download
+	// TotalSize is the size in bytes of all the assets listed by the index.
+	TotalSize int64
+}
+
+// Library is a Java library needed by the game client.
+type Library struct {
+	// Name is the Maven coordinates of the library,
+	// e.g. "com.mojang:patchy:1.1".
+	Name string
+	// Artifact is the library's jar. It is the zero Download if the library
+	// only consists of native code.
+	Artifact Download
+	// Natives holds the library's native code, indexed by the operating
+	// system it's compiled for, e.g. "linux", "osx" or "windows". Natives is
+	// nil if the library has no native code.
+	Natives map[string]Download
+}
+
+// LoadDetails fetches the JSON manifest of v from Mojang's servers and returns
+// the details it lists. ctx must be non-nil. If v.URL is empty, e.g. because v
+// was constructed by the client, ErrNoManifest is returned. LoadDetails
+// reports Mojang server communication failures using *url.Error.
+//
+// Rules restricting libraries to certain operating systems aren't part of
+// Details, so Libraries lists the libraries needed on any operating system.
+// Native code for operating systems whose classifier depends on the processor
+// architecture, e.g. "natives-windows-${arch}", is left out of Natives.
+func (v Version) LoadDetails(ctx context.Context) (*Details, error) {
+	internal.CheckContext(ctx, "versions", "Version.LoadDetails")
+
+	if v.URL == "" {
+		return nil, ErrNoManifest
+	}
+	m, err := internal.FetchJSON(ctx, client, v.URL)
+	if err != nil {
+		return nil, err
+	}
+	return buildDetails(v.URL, m)
+}
+
+// VerifyClient reads r until EOF and checks that the data read is the game
+// client jar of the version, e.g. to detect a corrupted download before
+// installing it. It is the same as d.Downloads.Client.Verify(r).
+func (d *Details) VerifyClient(r io.Reader) error {
+	return d.Downloads.Client.Verify(r)
+}
+
+func buildDetails(manifestURL string, j interface{}) (d *Details, err error) {
+	defer func() { // If JSON data isn't structured as expected
+		if r := recover(); r != nil {
+			d = nil
+			err = &url.Error{
+				Op:  "Parse",
+				URL: manifestURL,
+				Err: internal.ErrUnknownFormat,
+			}
+		}
+	}()
+
+	m := j.(map[string]interface{})
+	d = &Details{
+		ID:        m["id"].(string),
+		MainClass: m["mainClass"].(string),
+	}
+
+	if ai, ok := m["assetIndex"]; ok {
+		aim := ai.(map[string]interface{})
+		d.AssetIndex.ID = aim["id"].(string)
+		d.AssetIndex.Download = buildDownload(aim)
+		if ts, ok := aim["totalSize"]; ok {
+			d.AssetIndex.TotalSize = int64(ts.(float64))
+		}
+	}
+
+	if ds, ok := m["downloads"]; ok {
+		dm := ds.(map[string]interface{})
+		for _, t := range [...]struct {
+			target DownloadTarget
+			dst    *Download
+		}{
+			{Client, &d.Downloads.Client},
+			{Server, &d.Downloads.Server},
+			{ClientMappings, &d.Downloads.ClientMappings},
+			{ServerMappings, &d.Downloads.ServerMappings},
+		} {
+			if dl, ok := dm[string(t.target)]; ok {
+				*t.dst = buildDownload(dl.(map[string]interface{}))
+			}
+		}
+	}
+
+	if ls, ok := m["libraries"]; ok {
+		arr := ls.([]interface{})
+		d.Libraries = make([]Library, len(arr))
+		for i, l := range arr {
+			d.Libraries[i] = buildLibrary(l.(map[string]interface{}))
+		}
+	}
+
+	return d, nil
+}
+
+func buildLibrary(m map[string]interface{}) Library {
+	l := Library{Name: m["name"].(string)}
+	ds, ok := m["downloads"]
+	if !ok {
+		return l
+	}
+	dm := ds.(map[string]interface{})
+	if a, ok := dm["artifact"]; ok {
+		l.Artifact = buildDownload(a.(map[string]interface{}))
+	}
+	if ns, ok := m["natives"]; ok {
+		cs, _ := dm["classifiers"].(map[string]interface{})
+		for system, c := range ns.(map[string]interface{}) {
+			cd, ok := cs[c.(string)]
+			if !ok {
+				continue // E.g. "natives-windows-${arch}"
+			}
+			if l.Natives == nil {
+				l.Natives = make(map[string]Download)
+			}
+			l.Natives[system] = buildDownload(cd.(map[string]interface{}))
+		}
+	}
+	return l
+}
+
+func buildDownload(m map[string]interface{}) Download {
+	d := Download{
+		URL:  m["url"].(string),
+		SHA1: m["sha1"].(string),
+		Size: int64(m["size"].(float64)),
+	}
+	if p, ok := m["path"]; ok {
+		d.Path = p.(string)
+	}
+	return d
+}
@@ -0,0 +1,158 @@
+package versions
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PhilipBorgesen/minecraft/internal"
+)
+
+var testLoadDetailsExpectation = &Details{
+	ID:        "1.11.2",
+	MainClass: "net.minecraft.client.main.Main",
+	AssetIndex: AssetIndex{
+		ID: "1.11",
+		Download: Download{
+			URL:  "https://launchermeta.mojang.com/mc/assets/1.11/e7f5a3bba1f0a4b4a1b0c8e07e3cab9bbf88e8a6/1.11.json",
+			SHA1: "e7f5a3bba1f0a4b4a1b0c8e07e3cab9bbf88e8a6",
+			Size: 143138,
+		},
+		TotalSize: 127062341,
+	},
+	Libraries: []Library{
+		{
+			Name: "com.mojang:patchy:1.1",
+			Artifact: Download{
+				URL:  "https://libraries.minecraft.net/com/mojang/patchy/1.1/patchy-1.1.jar",
+				SHA1: "aef610b34a1be37fa851825f12372b78424d8903",
+				Size: 15817,
+				Path: "com/mojang/patchy/1.1/patchy-1.1.jar",
+			},
+		},
+		{
+			Name: "org.lwjgl.lwjgl:lwjgl-platform:2.9.4-nightly-20150209",
+			Natives: map[string]Download{
+				"linux": {
+					URL:  "https://libraries.minecraft.net/org/lwjgl/lwjgl/lwjgl-platform/2.9.4-nightly-20150209/lwjgl-platform-2.9.4-nightly-20150209-natives-linux.jar",
+					SHA1: "931074f46c795d2f7b30ed6395df5715cfd7675b",
+					Size: 578680,
+					Path: "org/lwjgl/lwjgl/lwjgl-platform/2.9.4-nightly-20150209/lwjgl-platform-2.9.4-nightly-20150209-natives-linux.jar",
+				},
+			},
+		},
+	},
+	Downloads: Downloads{
+		Client: Download{
+			URL:  "https://launcher.mojang.com/mc/game/1.11.2/client/db5aa600f0b0bf508aaf579509b345c4e34087be/client.jar",
+			SHA1: "db5aa600f0b0bf508aaf579509b345c4e34087be",
+			Size: 9375049,
+		},
+		Server: Download{
+			URL:  "https://launcher.mojang.com/mc/game/1.11.2/server/f00c294a1576e03fddcac777c3cf4c7d404c4ba4/server.jar",
+			SHA1: "f00c294a1576e03fddcac777c3cf4c7d404c4ba4",
+			Size: 9481271,
+		},
+	},
+}
+
+var testLoadDetailsInput = [...]struct {
+	version    Version
+	transport  http.RoundTripper
+	expDetails *Details
+	expErr     error
+}{
+	{
+		version:    Version{ID: "1.11.2", URL: testManifestURL},
+		transport:  http.NewFileTransport(http.Dir("testdata/cached")),
+		expDetails: testLoadDetailsExpectation,
+	},
+	{
+		version:   Version{ID: "1.11.2"},
+		transport: http.NewFileTransport(http.Dir("testdata/cached")),
+		expErr:    ErrNoManifest,
+	},
+	{
+		version:   Version{ID: "1.11.2", URL: testManifestURL},
+		transport: http.NewFileTransport(http.Dir("testdata/nonexisting")),
+		expErr: &url.Error{
+			Op:  "Get",
+			URL: testManifestURL,
+			Err: &internal.FailedRequestError{StatusCode: 404},
+		},
+	},
+	{
+		version:   Version{ID: "1.11.2", URL: testManifestURL},
+		transport: http.NewFileTransport(http.Dir("testdata/malstructured")),
+		expErr: &url.Error{
+			Op:  "Parse",
+			URL: testManifestURL,
+			Err: internal.ErrUnknownFormat,
+		},
+	},
+}
+
+func TestVersionLoadDetails(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	for _, tc := range testLoadDetailsInput {
+		client.Transport = tc.transport
+		d, err := tc.version.LoadDetails(context.Background())
+
+		errOK := err == tc.expErr
+		if exp, ok := tc.expErr.(*url.Error); ok {
+			errOK = urlErrorAlike(exp, err)
+		}
+		if !reflect.DeepEqual(d, tc.expDetails) || !errOK {
+			t.Errorf("%s.LoadDetails(ctx)\n"+
+				" was: %#v, %v\n"+
+				"want: %#v, %v",
+				pVersion(tc.version),
+				d, err,
+				tc.expDetails, tc.expErr,
+			)
+		}
+	}
+}
+
+func TestVersionLoadDetailsNilContext(t *testing.T) {
+	const exp = "minecraft/versions: nil Context passed to Version.LoadDetails"
+	defer func() {
+		if r := recover(); r != exp {
+			t.Errorf("Version.LoadDetails(nil) panicked with %#v; want %q", r, exp)
+		}
+	}()
+	Version{URL: testManifestURL}.LoadDetails(nil)
+}
+
+func TestDownload_Verify(t *testing.T) {
+	d := Download{SHA1: testVerifySHA1}
+	if err := d.Verify(strings.NewReader(testVerifyData)); err != nil {
+		t.Errorf("Download{SHA1: %q}.Verify(%q) was %v; want <nil>", testVerifySHA1, testVerifyData, err)
+	}
+	exp := ErrChecksumMismatch{Expected: testVerifySHA1, Got: "d18631a03f728fe6b2e585a8b4911f54d119602a"}
+	if err := d.Verify(strings.NewReader(testVerifyData + "!")); err != exp {
+		t.Errorf("Download{SHA1: %q}.Verify(%q) was %v; want %v", testVerifySHA1, testVerifyData+"!", err, exp)
+	}
+	if err := (Download{}).Verify(errorReader{errors.New("Read was called")}); err != ErrNoDownload {
+		t.Errorf("Download{}.Verify(r) was %v; want %v", err, ErrNoDownload)
+	}
+}
+
+func TestDetails_VerifyClient(t *testing.T) {
+	d := &Details{Downloads: Downloads{
+		Client: Download{SHA1: testVerifySHA1},
+		Server: Download{SHA1: "d18631a03f728fe6b2e585a8b4911f54d119602a"},
+	}}
+	if err := d.VerifyClient(strings.NewReader(testVerifyData)); err != nil {
+		t.Errorf("Details.VerifyClient(%q) was %v; want <nil>", testVerifyData, err)
+	}
+	if err := d.VerifyClient(strings.NewReader(testVerifyData + "!")); err == nil {
+		t.Errorf("Details.VerifyClient(%q) was <nil>; want ErrChecksumMismatch", testVerifyData+"!")
+	}
+}
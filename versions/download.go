@@ -0,0 +1,117 @@
+package versions
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/PhilipBorgesen/minecraft/internal"
+)
+
+// DownloadTarget identifies one of the artifacts which may be downloaded for
+// a Minecraft version.
+type DownloadTarget string
+
+const (
+	Client         DownloadTarget = "client"          // The game client jar
+	Server         DownloadTarget = "server"          // The dedicated server jar
+	ClientMappings DownloadTarget = "client_mappings" // Obfuscation mappings of the client
+	ServerMappings DownloadTarget = "server_mappings" // Obfuscation mappings of the server
+)
+
+var (
+	// ErrNoManifest is returned by Version.DownloadURL and
+	// Version.LoadDetails when the version's manifest URL is unknown.
+	ErrNoManifest = errors.New("minecraft/versions: version manifest URL unknown")
+	// ErrNoDownload is returned by Version.DownloadURL when the requested
+	// artifact isn't available for the version, and when verifying a
+	// Download without checksum.
+	ErrNoDownload = errors.New("minecraft/versions: artifact not available for version")
+)
+
+// DownloadURL fetches the manifest of v from Mojang's servers and returns the
+// URL from where target can be downloaded, along with the hex-encoded SHA1
+// checksum of the artifact. ctx must be non-nil.
+//
+// If v.URL is empty, ErrNoManifest is returned. If the manifest doesn't list
+// target, e.g. because mappings weren't published for versions before 1.14.4,
+// ErrNoDownload is returned. DownloadURL reports Mojang server communication
+// failures using *url.Error.
+func (v Version) DownloadURL(ctx context.Context, target DownloadTarget) (string, string, error) {
+	internal.CheckContext(ctx, "versions", "Version.DownloadURL")
+
+	if v.URL == "" {
+		return "", "", ErrNoManifest
+	}
+	m, err := internal.FetchJSON(ctx, client, v.URL)
+	if err != nil {
+		return "", "", err
+	}
+	return findDownload(v.URL, m, target)
+}
+
+func findDownload(manifestURL string, j interface{}, target DownloadTarget) (u, sum string, err error) {
+	defer func() { // If JSON data isn't structured as expected
+		if r := recover(); r != nil {
+			u, sum = "", ""
+			err = &url.Error{
+				Op:  "Parse",
+				URL: manifestURL,
+				Err: internal.ErrUnknownFormat,
+			}
+		}
+	}()
+
+	ds := j.(map[string]interface{})["downloads"].(map[string]interface{})
+	d, ok := ds[string(target)]
+	if !ok {
+		return "", "", ErrNoDownload
+	}
+	dm := d.(map[string]interface{})
+	return dm["url"].(string), dm["sha1"].(string), nil
+}
+
+// An ErrChecksumMismatch error is returned when a downloaded artifact doesn't
+// match the SHA1 checksum it was expected to have.
+type ErrChecksumMismatch struct {
+	Expected string // Hex-encoded SHA1 checksum the artifact should have.
+	Got      string // Hex-encoded SHA1 checksum of the artifact.
+}
+
+func (e ErrChecksumMismatch) Error() string {
+	return fmt.Sprintf("minecraft/versions: SHA1 checksum mismatch: expected %s, got %s", e.Expected, e.Got)
+}
+
+// VerifyReader reads r until EOF and checks that the data read has the
+// hex-encoded SHA1 checksum expectedSHA1, e.g. as returned by
+// Version.DownloadURL. Case is ignored when comparing checksums. If the
+// checksums differ, an ErrChecksumMismatch error is returned. Errors reading
+// r are returned as is.
+func VerifyReader(r io.Reader, expectedSHA1 string) error {
+	h := sha1.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return err
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(got, expectedSHA1) {
+		return ErrChecksumMismatch{Expected: expectedSHA1, Got: got}
+	}
+	return nil
+}
+
+// VerifyFile checks that the file named path has the hex-encoded SHA1
+// checksum expectedSHA1, as described for VerifyReader. Errors opening or
+// reading the file are returned as is, i.e. as *os.PathError.
+func VerifyFile(path, expectedSHA1 string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return VerifyReader(f, expectedSHA1)
+}
@@ -0,0 +1,214 @@
+package versions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/PhilipBorgesen/minecraft/internal"
+)
+
+const testManifestURL = "https://launchermeta.mojang.com/mc/game/12f260fc1976f6dd688a211f1a906f956344abdd/1.11.2.json"
+
+var testDownloadURLInput = [...]struct {
+	version   Version
+	target    DownloadTarget
+	transport http.RoundTripper
+	expURL    string
+	expSHA1   string
+	expErr    error
+}{
+	{
+		version:   Version{ID: "1.11.2", URL: testManifestURL},
+		target:    Client,
+		transport: http.NewFileTransport(http.Dir("testdata/cached")),
+		expURL:    "https://launcher.mojang.com/mc/game/1.11.2/client/db5aa600f0b0bf508aaf579509b345c4e34087be/client.jar",
+		expSHA1:   "db5aa600f0b0bf508aaf579509b345c4e34087be",
+	},
+	{
+		version:   Version{ID: "1.11.2", URL: testManifestURL},
+		target:    Server,
+		transport: http.NewFileTransport(http.Dir("testdata/cached")),
+		expURL:    "https://launcher.mojang.com/mc/game/1.11.2/server/f00c294a1576e03fddcac777c3cf4c7d404c4ba4/server.jar",
+		expSHA1:   "f00c294a1576e03fddcac777c3cf4c7d404c4ba4",
+	},
+	{
+		version:   Version{ID: "1.11.2", URL: testManifestURL},
+		target:    ServerMappings,
+		transport: http.NewFileTransport(http.Dir("testdata/cached")),
+		expErr:    ErrNoDownload,
+	},
+	{
+		version:   Version{ID: "1.11.2"},
+		target:    Client,
+		transport: http.NewFileTransport(http.Dir("testdata/cached")),
+		expErr:    ErrNoManifest,
+	},
+	{
+		version:   Version{ID: "1.11.2", URL: testManifestURL},
+		target:    Client,
+		transport: http.NewFileTransport(http.Dir("testdata/nonexisting")),
+		expErr: &url.Error{
+			Op:  "Get",
+			URL: testManifestURL,
+			Err: &internal.FailedRequestError{StatusCode: 404},
+		},
+	},
+	{
+		version:   Version{ID: "1.11.2", URL: testManifestURL},
+		target:    Client,
+		transport: http.NewFileTransport(http.Dir("testdata/malstructured")),
+		expErr: &url.Error{
+			Op:  "Parse",
+			URL: testManifestURL,
+			Err: internal.ErrUnknownFormat,
+		},
+	},
+}
+
+func TestVersionDownloadURL(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	for _, tc := range testDownloadURLInput {
+		client.Transport = tc.transport
+		u, sha1, err := tc.version.DownloadURL(context.Background(), tc.target)
+
+		errOK := err == tc.expErr
+		if exp, ok := tc.expErr.(*url.Error); ok {
+			errOK = urlErrorAlike(exp, err)
+		}
+		if u != tc.expURL || sha1 != tc.expSHA1 || !errOK {
+			t.Errorf("%s.DownloadURL(ctx, %q)\n"+
+				" was: %q, %q, %v\n"+
+				"want: %q, %q, %v",
+				pVersion(tc.version), tc.target,
+				u, sha1, err,
+				tc.expURL, tc.expSHA1, tc.expErr,
+			)
+		}
+	}
+}
+
+func TestVersionDownloadURLNilContext(t *testing.T) {
+	const exp = "minecraft/versions: nil Context passed to Version.DownloadURL"
+	defer func() {
+		if r := recover(); r != exp {
+			t.Errorf("Version.DownloadURL(nil, Client) panicked with %#v; want %q", r, exp)
+		}
+	}()
+	Version{URL: testManifestURL}.DownloadURL(nil, Client)
+}
+
+func TestLoadVersionURL(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	client.Transport = http.NewFileTransport(http.Dir("testdata/cached"))
+	vs, err := Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load(ctx) failed to fetch a version listing: %s", err)
+	}
+	v := vs.Versions["1.11.2"]
+	if v.URL != testManifestURL {
+		t.Errorf("Load(ctx).Versions[\"1.11.2\"].URL was %q; want %q", v.URL, testManifestURL)
+	}
+
+	data, err := json.Marshal(v)
+	var u Version
+	if err == nil {
+		err = json.Unmarshal(data, &u)
+	}
+	if u.URL != v.URL || err != nil {
+		t.Errorf("json.Unmarshal(json.Marshal(v)) has URL %q, error %v; want %q, <nil>", u.URL, err, v.URL)
+	}
+}
+
+const (
+	testVerifyData = "minecraft"
+	testVerifySHA1 = "624c22a8c8f8c93f18fe5ecd4713100c8d754507"
+)
+
+var testVerifyReaderInput = [...]struct {
+	data     string
+	expected string
+	expErr   error
+}{
+	{data: testVerifyData, expected: testVerifySHA1},
+	{data: testVerifyData, expected: strings.ToUpper(testVerifySHA1)},
+	{
+		data:     testVerifyData + "!",
+		expected: testVerifySHA1,
+		expErr:   ErrChecksumMismatch{Expected: testVerifySHA1, Got: "d18631a03f728fe6b2e585a8b4911f54d119602a"},
+	},
+	{
+		data:     "",
+		expected: testVerifySHA1,
+		expErr:   ErrChecksumMismatch{Expected: testVerifySHA1, Got: "da39a3ee5e6b4b0d3255bfef95601890afd80709"},
+	},
+}
+
+func TestVerifyReader(t *testing.T) {
+	for _, tc := range testVerifyReaderInput {
+		if err := VerifyReader(strings.NewReader(tc.data), tc.expected); err != tc.expErr {
+			t.Errorf("VerifyReader(%q, %q) was %v; want %v", tc.data, tc.expected, err, tc.expErr)
+		}
+	}
+
+	testErr := errors.New("read failed")
+	if err := VerifyReader(errorReader{testErr}, testVerifySHA1); err != testErr {
+		t.Errorf("VerifyReader(r, %q) with failing r was %v; want %s", testVerifySHA1, err, testErr)
+	}
+}
+
+func TestVerifyFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "versions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(testVerifyData)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := VerifyFile(f.Name(), testVerifySHA1); err != nil {
+		t.Errorf("VerifyFile(%q, %q) was %v; want <nil>", f.Name(), testVerifySHA1, err)
+	}
+	exp := ErrChecksumMismatch{Expected: "da39a3ee5e6b4b0d3255bfef95601890afd80709", Got: testVerifySHA1}
+	if err := VerifyFile(f.Name(), exp.Expected); err != exp {
+		t.Errorf("VerifyFile(%q, %q) was %v; want %v", f.Name(), exp.Expected, err, exp)
+	}
+	if err := VerifyFile(f.Name()+".nonexisting", testVerifySHA1); !os.IsNotExist(err) {
+		t.Errorf("VerifyFile of nonexisting file was %v; want file not found error", err)
+	}
+}
+
+func TestErrChecksumMismatch_Error(t *testing.T) {
+	err := ErrChecksumMismatch{Expected: "aa", Got: "bb"}
+	exp := "minecraft/versions: SHA1 checksum mismatch: expected aa, got bb"
+	if s := err.Error(); s != exp {
+		t.Errorf("%#v.Error() = %q; want %q", err, s, exp)
+	}
+}
+
+/*************
+* TEST UTILS *
+*************/
+
+type errorReader struct {
+	err error
+}
+
+func (er errorReader) Read(_ []byte) (int, error) {
+	return 0, er.err
+}
@@ -32,3 +32,24 @@ func ExampleLoad() {
 	// b1.0       beta     2010-12-19 22:00:00 +0000 UTC
 	// rd-132211  alpha    2009-05-13 20:11:00 +0000 UTC
 }
+
+// The following example demonstrates how to find where the dedicated server of
+// the latest Minecraft release can be downloaded from.
+func ExampleVersion_DownloadURL() {
+	ctx := context.TODO()
+
+	vs, err := versions.Load(ctx)
+	if err != nil {
+		log.Fatal("Failed to fetch versions listing: " + err.Error())
+	}
+
+	url, sha1, err := vs.LatestRelease().DownloadURL(ctx, versions.Server)
+	if err != nil {
+		log.Fatal("Failed to fetch version manifest: " + err.Error())
+	}
+	fmt.Println(url)
+	fmt.Println("SHA1:", sha1)
+	// output:
+	// https://launcher.mojang.com/mc/game/1.11.2/server/f00c294a1576e03fddcac777c3cf4c7d404c4ba4/server.jar
+	// SHA1: f00c294a1576e03fddcac777c3cf4c7d404c4ba4
+}
@@ -0,0 +1,173 @@
+package versions
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/PhilipBorgesen/minecraft/internal"
+)
+
+// DefaultFetcherTTL is how long a CachedFetcher caches the versions listing
+// unless configured otherwise.
+const DefaultFetcherTTL = 10 * time.Minute
+
+// now returns the current time. It may be replaced by tests.
+var now = time.Now
+
+// A CachedFetcher loads the versions listing like Load does, but caches the
+// listing loaded, such that Mojang's servers are only contacted when the
+// cached listing is older than TTL. Since the listing rarely changes, this
+// avoids downloading it repeatedly, e.g. every time a launcher is asked what
+// the latest version is.
+//
+// Once the cached listing is older than TTL, it's revalidated by a conditional
+// request: if Mojang's servers report the listing unchanged since it was
+// fetched, the cached listing is kept without being downloaded and parsed
+// anew. Poll reports whether that was the case.
+//
+// A CachedFetcher must not be copied after first use. Its methods are safe for
+// concurrent use by multiple goroutines, which share the cached listing, but
+// its fields must not be modified after first use.
+type CachedFetcher struct {
+	// TTL is how long a loaded listing is cached. If zero, DefaultFetcherTTL
+	// is used.
+	TTL time.Duration
+	// Path is the name of a file in which to persist the cached listing,
+	// e.g. to reuse it across runs of an application. If empty, the listing
+	// is only cached in memory.
+	Path string
+	// Client is the client used to fetch the listing, as passed to LoadWith.
+	// If nil, the package's default client is used.
+	Client *http.Client
+
+	mu         sync.Mutex
+	listing    Listing
+	fetched    time.Time           // When listing was fetched; zero if nothing is cached.
+	validators internal.Validators // Validators of the response listing was parsed from.
+
+	_ struct{} // Ensure CachedFetcher is constructed using named parameters.
+}
+
+// cacheFile is the JSON representation of a listing persisted to a file.
+type cacheFile struct {
+	Fetched      time.Time `json:"fetched"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	Listing      Listing   `json:"listing"`
+}
+
+// Load returns the cached versions listing if it's younger than f.TTL.
+// Otherwise the listing is fetched from Mojang's servers as described for the
+// package-level LoadWith function using f.Client, and cached, unless Mojang's
+// servers report the cached listing unchanged. ctx must be non-nil. If another
+// goroutine is fetching the listing already, Load waits for it to finish and
+// shares its result.
+//
+// If f.Path is set and nothing is cached in memory, the listing persisted in
+// the file is used, provided it's younger than f.TTL. A missing or malformed
+// file is ignored. When a listing is fetched, it's written to the file. If the
+// file can't be written, the fetched listing is returned along with the error.
+// If fetching fails, nothing is cached and a zero-value Listing is returned
+// along with the error, so the next call fetches the listing anew.
+//
+// The returned listing is a copy which the client may modify freely.
+func (f *CachedFetcher) Load(ctx context.Context) (Listing, error) {
+	internal.CheckContext(ctx, "versions", "CachedFetcher.Load")
+	l, _, err := f.poll(ctx)
+	return l, err
+}
+
+// Poll is like Load, but also reports whether the returned listing was served
+// from cache rather than downloaded, i.e. whether it was younger than f.TTL or
+// reported unchanged by Mojang's servers. This allows frequent pollers to skip
+// processing a listing they have seen already.
+func (f *CachedFetcher) Poll(ctx context.Context) (l Listing, cached bool, err error) {
+	internal.CheckContext(ctx, "versions", "CachedFetcher.Poll")
+	return f.poll(ctx)
+}
+
+func (f *CachedFetcher) poll(ctx context.Context) (Listing, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.fetched.IsZero() && f.Path != "" {
+		f.readFile()
+	}
+	if !f.fetched.IsZero() && now().Sub(f.fetched) < f.ttl() {
+		return copyListing(f.listing), true, nil
+	}
+
+	c := f.Client
+	if c == nil {
+		c = client
+	}
+	var v internal.Validators
+	if !f.fetched.IsZero() {
+		v = f.validators
+	}
+	m, v, modified, err := internal.FetchJSONIfModified(ctx, c, versionsURL, v)
+	if err != nil {
+		return Listing{}, false, err
+	}
+	if modified {
+		var l Listing
+		if err = initialize(&l, versionsURL, m); err != nil {
+			return Listing{}, false, err
+		}
+		f.listing = l
+	}
+	f.fetched, f.validators = now(), v
+	if f.Path != "" {
+		err = f.writeFile()
+	}
+	return copyListing(f.listing), !modified, err
+}
+
+func (f *CachedFetcher) ttl() time.Duration {
+	if f.TTL == 0 {
+		return DefaultFetcherTTL
+	}
+	return f.TTL
+}
+
+// readFile caches the listing persisted at f.Path, if any.
+func (f *CachedFetcher) readFile() {
+	bs, err := ioutil.ReadFile(f.Path)
+	if err != nil {
+		return
+	}
+	var c cacheFile
+	if json.Unmarshal(bs, &c) != nil || c.Listing.Versions == nil {
+		return
+	}
+	f.listing, f.fetched = c.Listing, c.Fetched
+	f.validators = internal.Validators{ETag: c.ETag, LastModified: c.LastModified}
+}
+
+// writeFile persists the cached listing at f.Path.
+func (f *CachedFetcher) writeFile() error {
+	bs, err := json.Marshal(cacheFile{
+		Fetched:      f.fetched,
+		ETag:         f.validators.ETag,
+		LastModified: f.validators.LastModified,
+		Listing:      f.listing,
+	})
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(f.Path, bs, 0644)
+}
+
+// copyListing returns a copy of l which doesn't share its Versions map.
+func copyListing(l Listing) Listing {
+	vs := make(map[string]Version, len(l.Versions))
+	for id, v := range l.Versions {
+		vs[id] = v
+	}
+	l.Versions = vs
+	return l
+}
@@ -0,0 +1,230 @@
+package versions
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCachedFetcher_Load(t *testing.T) {
+	origTransport := client.Transport
+	origNow := now
+	defer func() {
+		client.Transport = origTransport
+		now = origNow
+	}()
+
+	tm := time.Date(2017, 05, 26, 12, 00, 00, 00, time.UTC)
+	now = func() time.Time { return tm }
+
+	st := &sequenceTransport{rts: []http.RoundTripper{http.NewFileTransport(http.Dir("testdata/cached"))}}
+	client.Transport = st
+
+	f := &CachedFetcher{TTL: time.Hour}
+	l, err := f.Load(context.Background())
+	if err != nil {
+		t.Fatalf("CachedFetcher.Load(ctx) failed: %s", err)
+	}
+	if l.Latest.Release != "1.11.2" {
+		t.Errorf("CachedFetcher.Load(ctx) returned listing with latest release %q; want \"1.11.2\"", l.Latest.Release)
+	}
+	delete(l.Versions, "1.11.2") // Must not affect the cached listing
+
+	tm = tm.Add(59 * time.Minute)
+	l, err = f.Load(context.Background())
+	if n := st.requests(); n != 1 {
+		t.Errorf("CachedFetcher.Load(ctx) within TTL made %d requests in total; want 1", n)
+	}
+	if _, ok := l.Versions["1.11.2"]; !ok || err != nil {
+		t.Errorf("CachedFetcher.Load(ctx) within TTL returned %d versions without \"1.11.2\", %v; want cached listing", len(l.Versions), err)
+	}
+
+	tm = tm.Add(time.Minute)
+	if _, err = f.Load(context.Background()); err != nil || st.requests() != 2 {
+		t.Errorf("CachedFetcher.Load(ctx) after TTL made %d requests in total, %v; want 2, <nil>", st.requests(), err)
+	}
+}
+
+func TestCachedFetcher_LoadError(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	testError := errors.New("fetch failed")
+	st := &sequenceTransport{rts: []http.RoundTripper{
+		errorTransport{testError},
+		http.NewFileTransport(http.Dir("testdata/cached")),
+	}}
+	client.Transport = st
+
+	f := &CachedFetcher{}
+	if l, err := f.Load(context.Background()); l.Versions != nil || !errors.Is(err, testError) {
+		t.Errorf("CachedFetcher.Load(ctx) returned %d versions, %v; want 0, %s", len(l.Versions), err, testError)
+	}
+	if _, err := f.Load(context.Background()); err != nil || st.requests() != 2 {
+		t.Errorf("CachedFetcher.Load(ctx) after failure made %d requests in total, %v; want 2, <nil>", st.requests(), err)
+	}
+}
+
+func TestCachedFetcher_LoadClient(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	client.Transport = errorTransport{errors.New("RoundTrip was called")}
+	f := &CachedFetcher{Client: &http.Client{Transport: http.NewFileTransport(http.Dir("testdata/cached"))}}
+	if l, err := f.Load(context.Background()); err != nil || l.Latest.Release != "1.11.2" {
+		t.Errorf("CachedFetcher{Client: c}.Load(ctx) returned latest release %q, %v; want \"1.11.2\", <nil>", l.Latest.Release, err)
+	}
+}
+
+func TestCachedFetcher_LoadFile(t *testing.T) {
+	origTransport := client.Transport
+	origNow := now
+	defer func() {
+		client.Transport = origTransport
+		now = origNow
+	}()
+
+	tm := time.Date(2017, 05, 26, 12, 00, 00, 00, time.UTC)
+	now = func() time.Time { return tm }
+
+	dir, err := ioutil.TempDir("", "versions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "listing.json")
+
+	client.Transport = http.NewFileTransport(http.Dir("testdata/cached"))
+	if _, err := (&CachedFetcher{Path: path}).Load(context.Background()); err != nil {
+		t.Fatalf("CachedFetcher{Path: %q}.Load(ctx) failed: %s", path, err)
+	}
+
+	// Another fetcher must use the persisted listing while within TTL
+	client.Transport = errorTransport{errors.New("RoundTrip was called")}
+	tm = tm.Add(DefaultFetcherTTL - time.Second)
+	l, err := (&CachedFetcher{Path: path}).Load(context.Background())
+	if err != nil {
+		t.Fatalf("CachedFetcher{Path: %q}.Load(ctx) didn't use persisted listing: %s", path, err)
+	}
+	if v, ok := l.Versions["1.11.2"]; !ok || v.Type != Release || l.Latest.Release != "1.11.2" {
+		t.Errorf("CachedFetcher{Path: %q}.Load(ctx) returned malformed persisted listing: %s", path, pVersion(v))
+	}
+
+	// Once expired, the listing must be fetched anew
+	tm = tm.Add(time.Second)
+	if _, err := (&CachedFetcher{Path: path}).Load(context.Background()); err == nil {
+		t.Errorf("CachedFetcher{Path: %q}.Load(ctx) used expired persisted listing", path)
+	}
+
+	// Malformed files are ignored
+	if err := ioutil.WriteFile(path, []byte("{malformed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	client.Transport = http.NewFileTransport(http.Dir("testdata/cached"))
+	if _, err := (&CachedFetcher{Path: path}).Load(context.Background()); err != nil {
+		t.Errorf("CachedFetcher{Path: %q}.Load(ctx) with malformed file failed: %s", path, err)
+	}
+}
+
+func TestCachedFetcher_LoadUnwritableFile(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	client.Transport = http.NewFileTransport(http.Dir("testdata/cached"))
+
+	path := filepath.Join("testdata", "nonexisting", "listing.json")
+	l, err := (&CachedFetcher{Path: path}).Load(context.Background())
+	if _, ok := err.(*os.PathError); !ok || l.Latest.Release != "1.11.2" {
+		t.Errorf("CachedFetcher{Path: %q}.Load(ctx) returned latest release %q, %v; want \"1.11.2\", *os.PathError", path, l.Latest.Release, err)
+	}
+}
+
+func TestCachedFetcher_Poll(t *testing.T) {
+	origTransport := client.Transport
+	origNow := now
+	defer func() {
+		client.Transport = origTransport
+		now = origNow
+	}()
+
+	tm := time.Date(2017, 05, 26, 12, 00, 00, 00, time.UTC)
+	now = func() time.Time { return tm }
+
+	st := &sequenceTransport{rts: []http.RoundTripper{
+		etagTransport{`"v1"`},
+		etagTransport{`"v1"`},
+		etagTransport{`"v2"`},
+	}}
+	client.Transport = st
+
+	f := &CachedFetcher{TTL: time.Hour}
+	poll := func(expCached bool, expRequests int) {
+		l, cached, err := f.Poll(context.Background())
+		if cached != expCached || err != nil || st.requests() != expRequests {
+			t.Errorf("CachedFetcher.Poll(ctx) at %s was cached=%t, %v after %d requests in total; want %t, <nil> after %d",
+				tm, cached, err, st.requests(), expCached, expRequests)
+		} else if l.Latest.Release != "1.11.2" {
+			t.Errorf("CachedFetcher.Poll(ctx) at %s returned listing with latest release %q; want \"1.11.2\"", tm, l.Latest.Release)
+		}
+	}
+
+	poll(false, 1)
+	tm = tm.Add(59 * time.Minute)
+	poll(true, 1) // Within TTL
+	tm = tm.Add(time.Minute)
+	poll(true, 2) // Not modified
+	tm = tm.Add(59 * time.Minute)
+	poll(true, 2) // Within TTL of revalidated listing
+	tm = tm.Add(time.Minute)
+	poll(false, 3) // Modified
+}
+
+func TestCachedFetcher_PollNilContext(t *testing.T) {
+	const exp = "minecraft/versions: nil Context passed to CachedFetcher.Poll"
+	defer func() {
+		if r := recover(); r != exp {
+			t.Errorf("CachedFetcher.Poll(nil) panicked with %#v; want %q", r, exp)
+		}
+	}()
+	(&CachedFetcher{}).Poll(nil)
+}
+
+func TestCachedFetcher_LoadNilContext(t *testing.T) {
+	const exp = "minecraft/versions: nil Context passed to CachedFetcher.Load"
+	defer func() {
+		if r := recover(); r != exp {
+			t.Errorf("CachedFetcher.Load(nil) panicked with %#v; want %q", r, exp)
+		}
+	}()
+	(&CachedFetcher{}).Load(nil)
+}
+
+/*** TEST UTILS ***/
+
+// etagTransport serves testdata/cached, tagged etag. It responds 304 Not
+// Modified to requests with an If-None-Match header of etag.
+type etagTransport struct {
+	etag string
+}
+
+func (et etagTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("If-None-Match") == et.etag {
+		return &http.Response{
+			StatusCode: 304,
+			Header:     http.Header{"Etag": {et.etag}},
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+			Request:    req,
+		}, nil
+	}
+	resp, err := http.NewFileTransport(http.Dir("testdata/cached")).RoundTrip(req)
+	if err == nil {
+		resp.Header.Set("ETag", et.etag)
+	}
+	return resp, err
+}
@@ -0,0 +1,57 @@
+//go:build go1.18
+// +build go1.18
+
+package versions
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+// FuzzLoad feeds arbitrary response bodies to Load and checks that malformed
+// versions listings are reported as errors rather than crashing the process.
+func FuzzLoad(f *testing.F) {
+	for _, file := range []string{
+		"testdata/cached/mc/game/version_manifest.json",
+		"testdata/cached/mc/game/version_manifest_v2.json",
+		"testdata/malstructured/mc/game/version_manifest.json",
+	} {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(b)
+	}
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		origTransport := client.Transport
+		defer func() { client.Transport = origTransport }()
+
+		client.Transport = bodyTransport(body)
+		vs, err := Load(context.Background())
+		if err != nil {
+			if _, ok := err.(*url.Error); !ok {
+				t.Errorf("Load(ctx) returned unexpected error: %#v", err)
+			}
+			if !reflect.DeepEqual(vs, Listing{}) {
+				t.Errorf("Load(ctx) returned non-zero Listing along with error: %s", err)
+			}
+		}
+	})
+}
+
+type bodyTransport []byte
+
+func (bt bodyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: 200,
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(bytes.NewReader(bt)),
+		Request:    req,
+	}, nil
+}
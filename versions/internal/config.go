@@ -2,4 +2,4 @@ package internal
 
 // VersionsURL is the endpoint to fetch version information from.
 // For test purposes a cached response should be downloaded to ../testdata/cached/<SERVER PATH>
-const VersionsURL = "https://launchermeta.mojang.com/mc/game/version_manifest.json"
+const VersionsURL = "https://launchermeta.mojang.com/mc/game/version_manifest_v2.json"
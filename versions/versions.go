@@ -9,8 +9,9 @@
 // 		log.Fatal("Failed to fetch versions listing: " + err.Error())
 //	}
 //
-//	if latest := vs.Latest.Release; latest != currentVersion {
-// 		url := fmt.Sprintf("http://s3.amazonaws.com/Minecraft.Download/versions/%s/%s.jar", latest, latest)
+//	if latest := vs.LatestRelease(); latest.ID != currentVersion {
+//		url, sha1, err := latest.DownloadURL(context.TODO(), versions.Client)
+//		...
 //		resp, err := http.Get(url)
 //		...
 //	}
@@ -19,8 +20,13 @@ package versions
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/PhilipBorgesen/minecraft/internal"
@@ -39,10 +45,61 @@ type Listing struct {
 // be non-nil. If an error occurs, a zero-value Listing will be returned. Load
 // reports Mojang server communication failures using *url.Error.
 func Load(ctx context.Context) (Listing, error) {
+	internal.CheckContext(ctx, "versions", "Load")
+	return load(ctx, client, versionsURL)
+}
+
+// LoadWith is like Load, but sends the request using c rather than the
+// package's default client, e.g. to reuse the connection pool of a service or
+// to set a request timeout. If c is nil, the default client is used.
+func LoadWith(ctx context.Context, c *http.Client) (Listing, error) {
+	internal.CheckContext(ctx, "versions", "LoadWith")
+	if c == nil {
+		c = client
+	}
+	return load(ctx, c, versionsURL)
+}
+
+// HTTPStatus returns the HTTP status code of the response which caused err, and
+// true, if err was returned because the server responded with a status other
+// than 200 OK, e.g. 404 Not Found from a mirror passed to LoadFrom. Otherwise
+// HTTPStatus returns 0 and false. Errors returned by the profile package are
+// inspected the same way using profile.HTTPStatus.
+func HTTPStatus(err error) (status int, ok bool) {
+	return internal.HTTPStatus(err)
+}
+
+// ErrInvalidURL is returned, wrapped in a *url.Error, when LoadFrom is passed
+// a URL which isn't an absolute HTTP or HTTPS URL.
+var ErrInvalidURL = errors.New("minecraft/versions: URL must be an absolute HTTP or HTTPS URL")
+
+// LoadFrom is like Load, but fetches the listing of Minecraft versions from
+// the given URL rather than from Mojang's servers, e.g. from a mirror or a
+// pinned copy of the listing. The listing must be in the format of Mojang's
+// version_manifest.json or version_manifest_v2.json.
+//
+// If rawurl can't be parsed, or isn't an absolute HTTP or HTTPS URL, a
+// *url.Error is returned without contacting any server; in the latter case it
+// wraps ErrInvalidURL. Like Load, LoadFrom reports communication failures
+// using *url.Error.
+func LoadFrom(ctx context.Context, rawurl string) (Listing, error) {
+	internal.CheckContext(ctx, "versions", "LoadFrom")
+
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return Listing{}, err
+	}
+	if s := u.Scheme; (s != "http" && s != "https") || u.Host == "" {
+		return Listing{}, &url.Error{Op: "parse", URL: rawurl, Err: ErrInvalidURL}
+	}
+	return load(ctx, client, rawurl)
+}
+
+func load(ctx context.Context, c *http.Client, endpoint string) (Listing, error) {
 	var res Listing
-	m, err := internal.FetchJSON(ctx, client, versionsURL)
+	m, err := internal.FetchJSON(ctx, c, endpoint)
 	if err == nil {
-		err = initialize(&res, m)
+		err = initialize(&res, endpoint, m)
 		if err != nil {
 			res = Listing{}
 		}
@@ -60,6 +117,243 @@ func (l Listing) LatestRelease() Version {
 	panic("minecraft/versions: Listing.Versions does not contain Listing.Latest.Release ('" + l.Latest.Release + "')")
 }
 
+// LatestSnapshot returns the version information for the latest development
+// snapshot. It is the same as l.Versions[l.Latest.Snapshot], except
+// LatestSnapshot will panic if l.Versions doesn't contain the key
+// l.Latest.Snapshot.
+func (l Listing) LatestSnapshot() Version {
+	if v, ok := l.Versions[l.Latest.Snapshot]; ok {
+		return v
+	}
+	panic("minecraft/versions: Listing.Versions does not contain Listing.Latest.Snapshot ('" + l.Latest.Snapshot + "')")
+}
+
+// Between returns the versions of l released in the time interval [from, to),
+// i.e. from inclusive and to exclusive, sorted chronologically by release date.
+// Versions with an unknown (zero) release date are never included.
+func (l Listing) Between(from, to time.Time) []Version {
+	var vs []Version
+	for _, v := range l.Versions {
+		if r := v.Released; !r.IsZero() && !r.Before(from) && r.Before(to) {
+			vs = append(vs, v)
+		}
+	}
+	sort.Sort(byReleaseTime(vs))
+	return vs
+}
+
+// SortedByReleaseTime returns the versions of l sorted by release date, newest
+// first. Versions released at the same time are sorted by ID, in descending
+// order. Versions with an unknown (zero) release date are sorted last.
+func (l Listing) SortedByReleaseTime() []Version {
+	vs := make([]Version, 0, len(l.Versions))
+	for _, v := range l.Versions {
+		vs = append(vs, v)
+	}
+	sort.Sort(sort.Reverse(byReleaseTime(vs)))
+	return vs
+}
+
+// Newest returns the version of l released last. ok is false if l contains no
+// versions with a known (non-zero) release date.
+func (l Listing) Newest() (v Version, ok bool) {
+	for _, x := range l.Versions {
+		if x.Released.IsZero() {
+			continue
+		}
+		if !ok || byReleaseTime([]Version{v, x}).Less(0, 1) {
+			v, ok = x, true
+		}
+	}
+	return v, ok
+}
+
+// Oldest returns the version of l released first. ok is false if l contains no
+// versions with a known (non-zero) release date.
+func (l Listing) Oldest() (v Version, ok bool) {
+	for _, x := range l.Versions {
+		if x.Released.IsZero() {
+			continue
+		}
+		if !ok || byReleaseTime([]Version{x, v}).Less(0, 1) {
+			v, ok = x, true
+		}
+	}
+	return v, ok
+}
+
+// WithPrefix returns the versions of l whose ID starts with prefix, sorted
+// chronologically by release date. Versions with an unknown (zero) release
+// date are sorted first. Note that the prefix is matched literally, so "1.1"
+// also matches versions such as "1.10" and "1.11.2"; use WithMinor to select
+// the versions of a specific minor release.
+func (l Listing) WithPrefix(prefix string) []Version {
+	var vs []Version
+	for id, v := range l.Versions {
+		if strings.HasPrefix(id, prefix) {
+			vs = append(vs, v)
+		}
+	}
+	sort.Sort(byReleaseTime(vs))
+	return vs
+}
+
+// Filter returns the versions of l whose type is one of types, sorted
+// chronologically by release date. Versions with an unknown (zero) release
+// date are sorted first. If no types are given, all versions of l are
+// returned.
+func (l Listing) Filter(types ...Type) []Version {
+	var vs []Version
+	for _, v := range l.Versions {
+		if len(types) == 0 || hasType(types, v.Type) {
+			vs = append(vs, v)
+		}
+	}
+	sort.Sort(byReleaseTime(vs))
+	return vs
+}
+
+// Releases returns the release versions of l, sorted chronologically by
+// release date. It is the same as l.Filter(Release).
+func (l Listing) Releases() []Version {
+	return l.Filter(Release)
+}
+
+func hasType(types []Type, t Type) bool {
+	for _, x := range types {
+		if x == t {
+			return true
+		}
+	}
+	return false
+}
+
+// WithMinor returns the versions of l which belong to the minor release
+// major.minor, sorted chronologically by release date. A version belongs to
+// the minor release if its ID is the major and minor version numbers, possibly
+// followed by a patch version or pre-release suffix, e.g. "1.16", "1.16.5",
+// "1.16-pre1" or "1.16.2-rc1" for WithMinor(1, 16). Versions with an unknown
+// (zero) release date are sorted first.
+//
+// Development snapshots named after their week of release, e.g. "20w45a",
+// can't be attributed to a release by their IDs and are never included.
+func (l Listing) WithMinor(major, minor int) []Version {
+	var vs []Version
+	for id, v := range l.Versions {
+		if m, n, ok := parseMinor(id); ok && m == major && n == minor {
+			vs = append(vs, v)
+		}
+	}
+	sort.Sort(byReleaseTime(vs))
+	return vs
+}
+
+// ReleaseFamily returns the versions of l which belong to the release family
+// named family, e.g. "1.17", sorted chronologically by release date. This is
+// every version WithMinor would return for the family, i.e. its releases,
+// patch releases and pre-releases, as well as the development snapshots which
+// lead up to it. If family isn't of the form "major.minor", ReleaseFamily
+// returns nil.
+//
+// Since the IDs of snapshots such as "21w03a" don't reveal which release they
+// belong to, snapshots are attributed to families heuristically by release
+// date: a snapshot belongs to the family of the first minor release, e.g.
+// "1.17" but not "1.16.5", which was released after it. Snapshots released
+// after the latest minor release listed, e.g. "1.17", are attributed to the
+// next family, e.g. "1.18". The heuristic attributes the rare snapshots of
+// patch releases, e.g. "16w50a" of "1.11.1", to the family following the
+// patch release, and can't predict when the major version number increases.
+// Snapshots with an unknown (zero) release date are never included.
+func (l Listing) ReleaseFamily(family string) []Version {
+	major, minor, ok := parseMinor(family)
+	if !ok || family != strconv.Itoa(major)+"."+strconv.Itoa(minor) {
+		return nil
+	}
+
+	// Minor releases, by which snapshots are attributed to families
+	var minors []Version
+	for id, v := range l.Versions {
+		if m, n, ok := parseMinor(id); ok && v.Type == Release && !v.Released.IsZero() &&
+			id == strconv.Itoa(m)+"."+strconv.Itoa(n) {
+			minors = append(minors, v)
+		}
+	}
+	sort.Sort(byReleaseTime(minors))
+
+	var vs []Version
+	for id, v := range l.Versions {
+		if m, n, ok := parseMinor(id); ok {
+			if m == major && n == minor {
+				vs = append(vs, v)
+			}
+		} else if v.Type == Snapshot && !v.Released.IsZero() {
+			if m, n, ok := snapshotFamily(minors, v.Released); ok && m == major && n == minor {
+				vs = append(vs, v)
+			}
+		}
+	}
+	sort.Sort(byReleaseTime(vs))
+	return vs
+}
+
+// snapshotFamily returns the release family of a snapshot released at t, as
+// described for Listing.ReleaseFamily. minors are the minor releases of the
+// listing, sorted chronologically by release date.
+func snapshotFamily(minors []Version, t time.Time) (major, minor int, ok bool) {
+	i := sort.Search(len(minors), func(i int) bool {
+		return !minors[i].Released.Before(t)
+	})
+	if i < len(minors) {
+		return parseMinor(minors[i].ID)
+	}
+	if len(minors) == 0 {
+		return 0, 0, false
+	}
+	major, minor, _ = parseMinor(minors[len(minors)-1].ID)
+	return major, minor + 1, true
+}
+
+// parseMinor parses the major and minor version numbers of a version ID as
+// described for Listing.WithMinor.
+func parseMinor(id string) (major, minor int, ok bool) {
+	i := strings.IndexByte(id, '.')
+	if i < 0 {
+		return 0, 0, false
+	}
+	j := len(id)
+	if k := strings.IndexAny(id[i+1:], ".- "); k >= 0 {
+		j = i + 1 + k
+	}
+	major, ok = parseNumber(id[:i])
+	if !ok {
+		return 0, 0, false
+	}
+	minor, ok = parseNumber(id[i+1 : j])
+	return major, minor, ok
+}
+
+// parseNumber parses s as a non-negative decimal number without sign.
+func parseNumber(s string) (int, bool) {
+	if s == "" || s[0] < '0' || s[0] > '9' {
+		return 0, false
+	}
+	n, err := strconv.Atoi(s)
+	return n, err == nil
+}
+
+// byReleaseTime sorts versions chronologically by release date.
+// Versions released at the same time instant are sorted by ID.
+type byReleaseTime []Version
+
+func (vs byReleaseTime) Len() int      { return len(vs) }
+func (vs byReleaseTime) Swap(i, j int) { vs[i], vs[j] = vs[j], vs[i] }
+func (vs byReleaseTime) Less(i, j int) bool {
+	if vs[i].Released.Equal(vs[j].Released) {
+		return vs[i].ID < vs[j].ID
+	}
+	return vs[i].Released.Before(vs[j].Released)
+}
+
 // Type represents the release type of a version.
 type Type string
 
@@ -103,33 +397,141 @@ func (t Type) String() string {
 // Version values should be used as map or database keys with caution as they
 // contain a time.Time field. Using ID as the key alone is recommended.
 // For the same reasons, do not use == with Version values; use Equal instead.
+//
+// Version values may be serialized in two ways. MarshalText and UnmarshalText
+// only encode ID, which is what encoding/json uses for map keys. Use this
+// compact form when the version will be looked up in a Listing again and full
+// fidelity isn't needed. MarshalJSON and UnmarshalJSON encode every field, using
+// the field names of Mojang's versions listing. Use this form when the version
+// must be reconstructed exactly, e.g. when caching version information.
 type Version struct {
 	ID       string    // Version identifier, e.g. "1.8.1".
 	Released time.Time // When the version was released.
 	Type     Type      // Type of release, e.g. ordinary release or development snapshot.
+
+	// ComplianceLevel reports whether the version meets Mojang's current
+	// safety standards: 1 if it does and 0 if the launcher should warn users
+	// about it. Version listings which don't report compliance levels, e.g.
+	// version_manifest.json passed to LoadFrom, leave ComplianceLevel 0.
+	ComplianceLevel int
+
+	// URL locates the version's JSON manifest, which describes the
+	// version's downloads. See DownloadURL. URL is empty if unknown.
+	URL string
+	// SHA1 is the hex-encoded SHA1 checksum of the version's JSON manifest
+	// at URL, e.g. to verify it once downloaded. Version listings which don't
+	// report checksums, e.g. version_manifest.json passed to LoadFrom, leave
+	// SHA1 empty.
+	SHA1 string
+	// Time is when the version's JSON manifest was last updated, which may be
+	// after the version's release. Time is the zero time if unknown.
+	Time time.Time
+}
+
+// IsCompliant reports whether v meets Mojang's current safety standards, i.e.
+// whether v.ComplianceLevel >= 1.
+func (v Version) IsCompliant() bool {
+	return v.ComplianceLevel >= 1
 }
 
 // Equal reports whether v and u represents the same Minecraft version.
 // For this to be true, v and u must have the same ID, be released at
-// the same time instant, and be of the same release type.
+// the same time instant, and be of the same release type. Information about
+// the version's manifest, i.e. URL, SHA1 and Time, as well as its
+// ComplianceLevel, isn't compared, since it differs between listings.
 // Do not use == with Version values.
 func (v Version) Equal(u Version) bool {
 	return v.ID == u.ID && v.Type == u.Type && v.Released.Equal(u.Released)
 }
 
+// Dedupe returns vs with duplicate versions removed, keeping the first
+// occurrence of each version in the order of vs. Versions are duplicates if
+// they are Equal. The underlying array of vs is reused for the result.
+func Dedupe(vs []Version) []Version {
+	seen := make(map[string][]Version, len(vs))
+	res := vs[:0]
+outer:
+	for _, v := range vs {
+		for _, u := range seen[v.ID] {
+			if u.Equal(v) {
+				continue outer
+			}
+		}
+		seen[v.ID] = append(seen[v.ID], v)
+		res = append(res, v)
+	}
+	return res
+}
+
 // String returns v.ID.
 func (v Version) String() string {
 	return v.ID
 }
 
+// MarshalText encodes v as its ID.
+func (v Version) MarshalText() ([]byte, error) {
+	return []byte(v.ID), nil
+}
+
+// UnmarshalText sets v to a Version which only has ID set, namely to text.
+func (v *Version) UnmarshalText(text []byte) error {
+	*v = Version{ID: string(text)}
+	return nil
+}
+
+// versionJSON is the JSON representation of a Version.
+type versionJSON struct {
+	ID       string    `json:"id"`
+	Type     Type      `json:"type"`
+	Released time.Time `json:"releaseTime"`
+
+	ComplianceLevel int       `json:"complianceLevel,omitempty"`
+	URL             string    `json:"url,omitempty"`
+	SHA1            string    `json:"sha1,omitempty"`
+	Time            time.Time `json:"time"`
+}
+
+// MarshalJSON encodes v as a JSON object containing every field of v.
+func (v Version) MarshalJSON() ([]byte, error) {
+	return json.Marshal(versionJSON{
+		ID:       v.ID,
+		Type:     v.Type,
+		Released: v.Released,
+
+		ComplianceLevel: v.ComplianceLevel,
+		URL:             v.URL,
+		SHA1:            v.SHA1,
+		Time:            v.Time,
+	})
+}
+
+// UnmarshalJSON decodes a JSON object produced by MarshalJSON into v.
+func (v *Version) UnmarshalJSON(data []byte) error {
+	var j versionJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	*v = Version{
+		ID:       j.ID,
+		Released: j.Released,
+		Type:     j.Type,
+
+		ComplianceLevel: j.ComplianceLevel,
+		URL:             j.URL,
+		SHA1:            j.SHA1,
+		Time:            j.Time,
+	}
+	return nil
+}
+
 var client = &http.Client{}
 
-func initialize(l *Listing, j interface{}) (err error) {
+func initialize(l *Listing, endpoint string, j interface{}) (err error) {
 	defer func() { // If JSON data isn't structured as expected
 		if r := recover(); r != nil {
 			err = &url.Error{
 				Op:  "Parse",
-				URL: versionsURL,
+				URL: endpoint,
 				Err: internal.ErrUnknownFormat,
 			}
 		}
@@ -157,6 +559,22 @@ func buildVersion(m map[string]interface{}, v *Version) {
 	v.ID = m["id"].(string)
 	v.Released = parseTime(m["releaseTime"].(string))
 	v.Type = Type(m["type"].(string))
+	v.ComplianceLevel = 0
+	if c, ok := m["complianceLevel"]; ok { // Absent from v1 listings
+		v.ComplianceLevel = int(c.(float64))
+	}
+	v.URL = ""
+	if u, ok := m["url"]; ok {
+		v.URL = u.(string)
+	}
+	v.SHA1 = ""
+	if s, ok := m["sha1"]; ok { // Absent from v1 listings
+		v.SHA1 = s.(string)
+	}
+	v.Time = time.Time{}
+	if t, ok := m["time"]; ok {
+		v.Time = parseTime(t.(string))
+	}
 }
 
 func parseTime(t string) time.Time {
@@ -2,6 +2,7 @@ package versions
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -29,7 +30,7 @@ var loadExpectations = [...]Version{
 		Released: time.Date(2011, 11, 17, 22, 00, 00, 00, time.UTC),
 		Type:     Release,
 	},
-	{ // Snapshot versions need to be updated along with testdata/version_manifest.json
+	{ // Snapshot versions need to be updated along with testdata/version_manifest_v2.json
 		ID:       "16w50a",
 		Released: time.Date(2016, 12, 15, 14, 38, 52, 00, time.UTC),
 		Type:     Snapshot,
@@ -74,6 +75,36 @@ func TestLoadContextUsed(t *testing.T) {
 	}
 }
 
+func TestLoadWith(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	client.Transport = &CtxStoreTransport{}
+	c := &http.Client{Transport: http.NewFileTransport(http.Dir("testdata/cached"))}
+	vs, err := LoadWith(context.Background(), c)
+	if err != nil || vs.Latest.Release != "1.11.2" {
+		t.Errorf("LoadWith(ctx, c) returned latest release %q, error %v; want \"1.11.2\", <nil>", vs.Latest.Release, err)
+	}
+	if client.Transport.(*CtxStoreTransport).Context != nil {
+		t.Error("LoadWith(ctx, c) used the default client")
+	}
+
+	client.Transport = http.NewFileTransport(http.Dir("testdata/cached"))
+	if vs, err = LoadWith(context.Background(), nil); err != nil || vs.Latest.Release != "1.11.2" {
+		t.Errorf("LoadWith(ctx, nil) returned latest release %q, error %v; want \"1.11.2\", <nil>", vs.Latest.Release, err)
+	}
+}
+
+func TestLoadWithNilContext(t *testing.T) {
+	const exp = "minecraft/versions: nil Context passed to LoadWith"
+	defer func() {
+		if r := recover(); r != exp {
+			t.Errorf("LoadWith(nil, nil) panicked with %#v; want %q", r, exp)
+		}
+	}()
+	LoadWith(nil, nil)
+}
+
 // Test that Load succeeds and that all returned Versions data is populated.
 func TestLoadInvariants(t *testing.T) {
 	origTransport := client.Transport
@@ -101,6 +132,8 @@ func TestLoadInvariants(t *testing.T) {
 		t.Errorf("Load(ctx).Latest.Snapshot = %q; want non-empty", "")
 	} else if _, ok := vs.Versions[s]; !ok {
 		t.Error("Load(ctx).Versions contained no information for Load(ctx).Latest.Snapshot")
+	} else if vs.LatestSnapshot() != vs.Versions[s] {
+		t.Error("Load(ctx).LatestSnapshot() differed from Load(ctx).Versions[Load().Latest.Snapshot]")
 	} else if vt := vs.Versions[s].Type; vt != Snapshot {
 		t.Errorf("Load(ctx).Latest.Snapshot denoted a version of type %s; want %s", vt, Snapshot)
 	}
@@ -166,6 +199,111 @@ func TestLoadError(t *testing.T) {
 	}
 }
 
+func TestLoadNilContext(t *testing.T) {
+	const exp = "minecraft/versions: nil Context passed to Load"
+	defer func() {
+		if r := recover(); r != exp {
+			t.Errorf("Load(nil) panicked with %#v; want %q", r, exp)
+		}
+	}()
+	Load(nil)
+}
+
+const testMirrorURL = "https://mirror.example.com/mc/game/version_manifest.json"
+
+var testLoadFromInput = [...]struct {
+	url        string
+	transport  http.RoundTripper
+	expRelease string
+	expErr     *url.Error
+}{
+	{
+		url:        testMirrorURL,
+		transport:  http.NewFileTransport(http.Dir("testdata/cached")),
+		expRelease: "1.11.2",
+	},
+	{
+		url:       testMirrorURL,
+		transport: http.NewFileTransport(http.Dir("testdata/malstructured")),
+		expErr:    &url.Error{Op: "Parse", URL: testMirrorURL, Err: internal.ErrUnknownFormat},
+	},
+	{
+		url:       testMirrorURL,
+		transport: http.NewFileTransport(http.Dir("testdata/nonexisting")),
+		expErr:    &url.Error{Op: "Get", URL: testMirrorURL, Err: &internal.FailedRequestError{StatusCode: 404}},
+	},
+	{
+		url:       "ftp://mirror.example.com/mc/game/version_manifest.json",
+		transport: &CtxStoreTransport{},
+		expErr:    &url.Error{Op: "parse", URL: "ftp://mirror.example.com/mc/game/version_manifest.json", Err: ErrInvalidURL},
+	},
+	{
+		url:       "/mc/game/version_manifest.json",
+		transport: &CtxStoreTransport{},
+		expErr:    &url.Error{Op: "parse", URL: "/mc/game/version_manifest.json", Err: ErrInvalidURL},
+	},
+	{
+		url:       "http://[::1",
+		transport: &CtxStoreTransport{},
+		expErr:    &url.Error{Op: "parse", URL: "http://[::1", Err: errors.New("missing ']' in host")},
+	},
+}
+
+func TestLoadFrom(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	for _, tc := range testLoadFromInput {
+		client.Transport = tc.transport
+		vs, err := LoadFrom(context.Background(), tc.url)
+
+		if tc.expErr != nil {
+			if !urlErrorAlike(tc.expErr, err) || !reflect.DeepEqual(vs, Listing{}) {
+				t.Errorf("LoadFrom(ctx, %q) returned result:\n"+
+					"      %s, %v\n"+
+					"want: %s, %s",
+					tc.url,
+					vs, err,
+					Listing{}, tc.expErr)
+			}
+		} else if err != nil || vs.Latest.Release != tc.expRelease {
+			t.Errorf("LoadFrom(ctx, %q) returned latest release %q, error %v; want %q, <nil>",
+				tc.url, vs.Latest.Release, err, tc.expRelease)
+		}
+
+		if ct, ok := tc.transport.(*CtxStoreTransport); ok && ct.Context != nil {
+			t.Errorf("LoadFrom(ctx, %q) contacted a server for an invalid URL", tc.url)
+		}
+	}
+}
+
+func TestHTTPStatus(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	client.Transport = http.NewFileTransport(http.Dir("testdata/nonexisting"))
+	_, err := LoadFrom(context.Background(), testMirrorURL)
+	if status, ok := HTTPStatus(err); status != 404 || !ok {
+		t.Errorf("HTTPStatus(%v) was %d, %t; want 404, true", err, status, ok)
+	}
+
+	client.Transport = http.NewFileTransport(http.Dir("testdata/malstructured"))
+	_, err = LoadFrom(context.Background(), testMirrorURL)
+	if status, ok := HTTPStatus(err); status != 0 || ok {
+		t.Errorf("HTTPStatus(%v) was %d, %t; want 0, false", err, status, ok)
+	}
+}
+
+func TestLoadFromNilContext(t *testing.T) {
+	const exp = "minecraft/versions: nil Context passed to LoadFrom"
+	defer func() {
+		if r := recover(); r != exp {
+			t.Errorf("LoadFrom(nil, %q) panicked with %#v; want %q", testMirrorURL, r, exp)
+		}
+	}()
+	LoadFrom(nil, testMirrorURL)
+}
+
 func TestLatestReleasePanic(t *testing.T) {
 	var l Listing
 	l.Versions = make(map[string]Version)
@@ -183,6 +321,205 @@ func TestLatestReleasePanic(t *testing.T) {
 	}
 }
 
+func TestLatestSnapshotPanic(t *testing.T) {
+	var l Listing
+	l.Versions = make(map[string]Version)
+	l.Latest.Snapshot = "doesNotExist"
+
+	test := func() (panicked bool) {
+		defer func() { recover() }()
+		panicked = true
+		l.LatestSnapshot()
+		return false
+	}
+
+	if panicked := test(); !panicked {
+		t.Error("LatestSnapshot() didn't panic as expected")
+	}
+}
+
+func TestListingBetween(t *testing.T) {
+	l := Listing{Versions: map[string]Version{
+		"zero": {ID: "zero"},
+		"b":    {ID: "b", Released: time.Date(2016, 06, 01, 00, 00, 00, 00, time.UTC)},
+		"a":    {ID: "a", Released: time.Date(2016, 06, 01, 00, 00, 00, 00, time.UTC)},
+		"c":    {ID: "c", Released: time.Date(2016, 01, 01, 00, 00, 00, 00, time.UTC)},
+		"d":    {ID: "d", Released: time.Date(2017, 01, 01, 00, 00, 00, 00, time.UTC)},
+		"e":    {ID: "e", Released: time.Date(2015, 12, 31, 23, 59, 59, 00, time.UTC)},
+	}}
+
+	from := time.Date(2016, 01, 01, 00, 00, 00, 00, time.UTC)
+	to := time.Date(2017, 01, 01, 00, 00, 00, 00, time.UTC)
+	exp := []string{"c", "a", "b"}
+
+	vs := l.Between(from, to)
+	if ids := versionIDs(vs); !reflect.DeepEqual(ids, exp) {
+		t.Errorf("Between(%s, %s) returned versions %q; want %q", from, to, ids, exp)
+	}
+}
+
+var testWithPrefixInput = [...]struct {
+	prefix string
+	exp    []string
+}{
+	{prefix: "1.11", exp: []string{"1.11.x", "1.11", "1.11.2"}},
+	{prefix: "1.1", exp: []string{"1.11.x", "1.1", "1.10", "1.11", "1.11.2"}},
+	{prefix: "16w", exp: []string{"16w50a"}},
+	{prefix: "2.0", exp: []string{}},
+}
+
+func TestListingWithPrefix(t *testing.T) {
+	l := Listing{Versions: map[string]Version{
+		"1.1":    {ID: "1.1", Released: time.Date(2012, 01, 11, 22, 00, 00, 00, time.UTC)},
+		"1.10":   {ID: "1.10", Released: time.Date(2016, 06, 8, 13, 6, 18, 00, time.UTC)},
+		"1.11.2": {ID: "1.11.2", Released: time.Date(2016, 12, 21, 9, 29, 12, 00, time.UTC)},
+		"1.11":   {ID: "1.11", Released: time.Date(2016, 11, 14, 14, 34, 40, 00, time.UTC)},
+		"1.11.x": {ID: "1.11.x"},
+		"16w50a": {ID: "16w50a", Released: time.Date(2016, 12, 15, 14, 38, 52, 00, time.UTC)},
+		"b1.1":   {ID: "b1.1", Released: time.Date(2010, 12, 21, 22, 00, 00, 00, time.UTC)},
+	}}
+
+	for _, tc := range testWithPrefixInput {
+		if ids := versionIDs(l.WithPrefix(tc.prefix)); !reflect.DeepEqual(ids, tc.exp) {
+			t.Errorf("WithPrefix(%q) returned versions %q; want %q", tc.prefix, ids, tc.exp)
+		}
+	}
+}
+
+func TestListingSortedByReleaseTime(t *testing.T) {
+	l := Listing{Versions: map[string]Version{
+		"1.11.2": {ID: "1.11.2", Released: time.Date(2016, 12, 21, 9, 29, 12, 00, time.UTC)},
+		"1.11.x": {ID: "1.11.x"},
+		"1.11":   {ID: "1.11", Released: time.Date(2016, 11, 14, 14, 34, 40, 00, time.UTC)},
+		"16w50a": {ID: "16w50a", Released: time.Date(2016, 12, 15, 14, 38, 52, 00, time.UTC)},
+		"16w50b": {ID: "16w50b", Released: time.Date(2016, 12, 15, 14, 38, 52, 00, time.UTC)},
+		"b1.1":   {ID: "b1.1", Released: time.Date(2010, 12, 21, 22, 00, 00, 00, time.UTC)},
+	}}
+
+	exp := []string{"1.11.2", "16w50b", "16w50a", "1.11", "b1.1", "1.11.x"}
+	if ids := versionIDs(l.SortedByReleaseTime()); !reflect.DeepEqual(ids, exp) {
+		t.Errorf("SortedByReleaseTime() returned versions %q; want %q", ids, exp)
+	}
+	if v, ok := l.Newest(); v.ID != "1.11.2" || !ok {
+		t.Errorf("Newest() was %q, %t; want \"1.11.2\", true", v.ID, ok)
+	}
+	if v, ok := l.Oldest(); v.ID != "b1.1" || !ok {
+		t.Errorf("Oldest() was %q, %t; want \"b1.1\", true", v.ID, ok)
+	}
+
+	l = Listing{Versions: map[string]Version{"1.11.x": {ID: "1.11.x"}}}
+	if v, ok := l.Newest(); ok {
+		t.Errorf("Newest() of listing without release dates was %q, true; want false", v.ID)
+	}
+	if v, ok := l.Oldest(); ok {
+		t.Errorf("Oldest() of listing without release dates was %q, true; want false", v.ID)
+	}
+}
+
+var testFilterInput = [...]struct {
+	types []Type
+	exp   []string
+}{
+	{types: nil, exp: []string{"a1.0.4", "b1.1", "1.11", "16w50a", "1.11.2"}},
+	{types: []Type{Release}, exp: []string{"1.11", "1.11.2"}},
+	{types: []Type{Alpha, Beta}, exp: []string{"a1.0.4", "b1.1"}},
+	{types: []Type{Snapshot, Snapshot}, exp: []string{"16w50a"}},
+	{types: []Type{"pending"}, exp: []string{}},
+}
+
+func TestListingFilter(t *testing.T) {
+	l := Listing{Versions: map[string]Version{
+		"1.11.2": {ID: "1.11.2", Type: Release, Released: time.Date(2016, 12, 21, 9, 29, 12, 00, time.UTC)},
+		"1.11":   {ID: "1.11", Type: Release, Released: time.Date(2016, 11, 14, 14, 34, 40, 00, time.UTC)},
+		"16w50a": {ID: "16w50a", Type: Snapshot, Released: time.Date(2016, 12, 15, 14, 38, 52, 00, time.UTC)},
+		"b1.1":   {ID: "b1.1", Type: Beta, Released: time.Date(2010, 12, 21, 22, 00, 00, 00, time.UTC)},
+		"a1.0.4": {ID: "a1.0.4", Type: Alpha, Released: time.Date(2010, 7, 9, 22, 00, 00, 00, time.UTC)},
+	}}
+
+	for _, tc := range testFilterInput {
+		if ids := versionIDs(l.Filter(tc.types...)); !reflect.DeepEqual(ids, tc.exp) {
+			t.Errorf("Filter(%q...) returned versions %q; want %q", tc.types, ids, tc.exp)
+		}
+	}
+	if ids, exp := versionIDs(l.Releases()), []string{"1.11", "1.11.2"}; !reflect.DeepEqual(ids, exp) {
+		t.Errorf("Releases() returned versions %q; want %q", ids, exp)
+	}
+}
+
+var testWithMinorInput = [...]struct {
+	major, minor int
+	exp          []string
+}{
+	{major: 1, minor: 16, exp: []string{"1.16-pre1", "1.16-rc1", "1.16", "1.16.1", "1.16.2-rc1", "1.16.2"}},
+	{major: 1, minor: 14, exp: []string{"1.14 Pre-Release 1", "1.14"}},
+	{major: 1, minor: 1, exp: []string{"1.1"}},
+	{major: 1, minor: 160, exp: []string{"1.160"}},
+	{major: 2, minor: 0, exp: []string{}},
+}
+
+func TestListingWithMinor(t *testing.T) {
+	released := time.Date(2020, 06, 01, 00, 00, 00, 00, time.UTC)
+	l := Listing{Versions: make(map[string]Version)}
+	for i, id := range []string{
+		"1.16-pre1", "1.16-rc1", "1.16", "20w45a", "1.16.1", "1.16.2-rc1", "1.16.2",
+		"1.14 Pre-Release 1", "1.14", "1.1", "1.160", "b1.16", "+1.16", "1.16a", "rd-132211",
+	} {
+		l.Versions[id] = Version{ID: id, Released: released.Add(time.Duration(i) * time.Hour)}
+	}
+
+	for _, tc := range testWithMinorInput {
+		if ids := versionIDs(l.WithMinor(tc.major, tc.minor)); !reflect.DeepEqual(ids, tc.exp) {
+			t.Errorf("WithMinor(%d, %d) returned versions %q; want %q", tc.major, tc.minor, ids, tc.exp)
+		}
+	}
+}
+
+var testReleaseFamilyInput = [...]struct {
+	family string
+	exp    []string
+}{
+	{family: "1.15", exp: []string{"1.15"}},
+	{family: "1.16", exp: []string{"20w06a", "1.16-pre1", "1.16", "1.16.1", "1.16.5"}},
+	{family: "1.17", exp: []string{"20w45a", "21w03a", "1.17"}},
+	{family: "1.18", exp: []string{"21w37a"}},
+	{family: "1.19", exp: []string{}},
+	{family: "1.16.1", exp: []string{}},
+	{family: "1.016", exp: []string{}},
+	{family: "21w03a", exp: []string{}},
+}
+
+func TestListingReleaseFamily(t *testing.T) {
+	released := time.Date(2020, 01, 01, 00, 00, 00, 00, time.UTC)
+	l := Listing{Versions: make(map[string]Version)}
+	for i, v := range []Version{
+		{ID: "1.15", Type: Release},
+		{ID: "20w06a", Type: Snapshot},
+		{ID: "1.16-pre1", Type: Snapshot},
+		{ID: "1.16", Type: Release},
+		{ID: "1.16.1", Type: Release},
+		{ID: "20w45a", Type: Snapshot},
+		{ID: "1.16.5", Type: Release},
+		{ID: "21w03a", Type: Snapshot},
+		{ID: "1.17", Type: Release},
+		{ID: "21w37a", Type: Snapshot},
+		{ID: "b1.16", Type: Beta},
+	} {
+		v.Released = released.Add(time.Duration(i) * time.Hour)
+		l.Versions[v.ID] = v
+	}
+	l.Versions["21w40a"] = Version{ID: "21w40a", Type: Snapshot} // Unknown release date
+
+	for _, tc := range testReleaseFamilyInput {
+		if ids := versionIDs(l.ReleaseFamily(tc.family)); !reflect.DeepEqual(ids, tc.exp) {
+			t.Errorf("ReleaseFamily(%q) returned versions %q; want %q", tc.family, ids, tc.exp)
+		}
+	}
+
+	if vs := (Listing{}).ReleaseFamily("1.16"); vs != nil {
+		t.Errorf("Listing{}.ReleaseFamily(\"1.16\") returned versions %q; want none", versionIDs(vs))
+	}
+}
+
 var knownTypes = [...]struct {
 	t Type
 	s string
@@ -221,12 +558,199 @@ func TestTypeString(t *testing.T) {
 	}
 }
 
+func TestVersionMarshalText(t *testing.T) {
+	v := loadExpectations[1]
+	text, err := v.MarshalText()
+	if string(text) != v.ID || err != nil {
+		t.Errorf("%s.MarshalText() was %q, %v; want %q, <nil>", pVersion(v), text, err, v.ID)
+	}
+
+	var u Version
+	if err := u.UnmarshalText([]byte(v.ID)); err != nil || !u.Equal(Version{ID: v.ID}) {
+		t.Errorf("Version.UnmarshalText(%q) produced %s, %v; want %s, <nil>", v.ID, pVersion(u), err, pVersion(Version{ID: v.ID}))
+	}
+}
+
+func TestVersionJSONRoundTrip(t *testing.T) {
+	for _, v := range loadExpectations {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Errorf("json.Marshal(%s) failed: %s", pVersion(v), err)
+			continue
+		}
+
+		var u Version
+		if err := json.Unmarshal(data, &u); err != nil {
+			t.Errorf("json.Unmarshal(%s) failed: %s", data, err)
+		} else if !u.Equal(v) {
+			t.Errorf("json.Unmarshal(json.Marshal(v)) was:\n"+
+				"      %s\n"+
+				"want: %s",
+				pVersion(u), pVersion(v))
+		}
+	}
+}
+
+func TestVersionComplianceLevel(t *testing.T) {
+	m := map[string]interface{}{
+		"id":              "1.16.5",
+		"type":            "release",
+		"releaseTime":     "2021-01-14T16:05:32+00:00",
+		"complianceLevel": 1.0,
+	}
+	var v Version
+	buildVersion(m, &v)
+	if v.ComplianceLevel != 1 || !v.IsCompliant() {
+		t.Errorf("Version built from %v has ComplianceLevel %d, IsCompliant() = %t; want 1, true", m, v.ComplianceLevel, v.IsCompliant())
+	}
+
+	data, err := json.Marshal(v)
+	var u Version
+	if err == nil {
+		err = json.Unmarshal(data, &u)
+	}
+	if u.ComplianceLevel != 1 || err != nil {
+		t.Errorf("json.Unmarshal(json.Marshal(v)) has ComplianceLevel %d, error %v; want 1, <nil>", u.ComplianceLevel, err)
+	}
+
+	delete(m, "complianceLevel")
+	buildVersion(m, &v)
+	if v.ComplianceLevel != 0 || v.IsCompliant() {
+		t.Errorf("Version built from %v has ComplianceLevel %d, IsCompliant() = %t; want 0, false", m, v.ComplianceLevel, v.IsCompliant())
+	}
+}
+
+func TestVersionManifestFields(t *testing.T) {
+	m := map[string]interface{}{
+		"id":          "1.16.5",
+		"type":        "release",
+		"url":         "https://piston-meta.mojang.com/v1/packages/3d3e6c9a1a3a8134e8e06d6a3bcea1e8b1e0bd58/1.16.5.json",
+		"time":        "2022-03-10T09:51:38+00:00",
+		"releaseTime": "2021-01-14T16:05:32+00:00",
+		"sha1":        "3d3e6c9a1a3a8134e8e06d6a3bcea1e8b1e0bd58",
+	}
+	expTime := time.Date(2022, 03, 10, 9, 51, 38, 0, time.UTC)
+
+	var v Version
+	buildVersion(m, &v)
+	if v.SHA1 != m["sha1"] || !v.Time.Equal(expTime) || v.URL != m["url"] {
+		t.Errorf("Version built from %v has SHA1 %q, Time %s, URL %q; want %q, %s, %q", m, v.SHA1, v.Time, v.URL, m["sha1"], expTime, m["url"])
+	}
+
+	data, err := json.Marshal(v)
+	var u Version
+	if err == nil {
+		err = json.Unmarshal(data, &u)
+	}
+	if u.SHA1 != v.SHA1 || !u.Time.Equal(v.Time) || err != nil {
+		t.Errorf("json.Unmarshal(json.Marshal(v)) has SHA1 %q, Time %s, error %v; want %q, %s, <nil>", u.SHA1, u.Time, err, v.SHA1, v.Time)
+	}
+
+	delete(m, "sha1")
+	delete(m, "time")
+	buildVersion(m, &v)
+	if v.SHA1 != "" || !v.Time.IsZero() {
+		t.Errorf("Version built from %v has SHA1 %q, Time %s; want \"\", zero time", m, v.SHA1, v.Time)
+	}
+}
+
+// Test that Load fetches a listing reporting the checksums of version manifests
+func TestLoadManifestChecksums(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	client.Transport = http.NewFileTransport(http.Dir("testdata/cached"))
+	vs, err := Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load(ctx) failed to fetch a version listing: %s", err)
+	}
+
+	const exp = "12f260fc1976f6dd688a211f1a906f956344abdd"
+	if v := vs.Versions["1.11.2"]; v.SHA1 != exp {
+		t.Errorf("Load(ctx).Versions[\"1.11.2\"].SHA1 = %q; want %q", v.SHA1, exp)
+	}
+	for id, v := range vs.Versions {
+		if v.SHA1 == "" {
+			t.Errorf("Load(ctx).Versions[%q].SHA1 = \"\"; want non-empty", id)
+		}
+	}
+}
+
+// Test that Load fetches a listing reporting the compliance levels of versions
+func TestLoadComplianceLevels(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	client.Transport = http.NewFileTransport(http.Dir("testdata/cached"))
+	vs, err := Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load(ctx) failed to fetch a version listing: %s", err)
+	}
+
+	for _, tc := range [...]struct {
+		id       string
+		expLevel int
+	}{
+		{id: "1.11.2", expLevel: 1},
+		{id: "16w50a", expLevel: 1},
+		{id: "b1.0", expLevel: 0},
+	} {
+		if v := vs.Versions[tc.id]; v.ComplianceLevel != tc.expLevel || v.IsCompliant() != (tc.expLevel >= 1) {
+			t.Errorf("Load(ctx).Versions[%q] has ComplianceLevel %d, IsCompliant() = %t; want %d, %t",
+				tc.id, v.ComplianceLevel, v.IsCompliant(), tc.expLevel, tc.expLevel >= 1)
+		}
+	}
+}
+
+func TestVersionJSONMapKey(t *testing.T) {
+	v := loadExpectations[2]
+	data, err := json.Marshal(map[Version]int{v: 1})
+	if exp := `{"` + v.ID + `":1}`; string(data) != exp || err != nil {
+		t.Errorf("json.Marshal(map[Version]int{...}) was %s, %v; want %s, <nil>", data, err, exp)
+	}
+}
+
+func TestDedupe(t *testing.T) {
+	released := time.Date(2016, 06, 01, 12, 00, 00, 00, time.UTC)
+	vs := []Version{
+		{ID: "a", Released: released, Type: Release},
+		{ID: "b", Released: released, Type: Snapshot},
+		{ID: "a", Released: released.In(time.FixedZone("CEST", 2*60*60)), Type: Release}, // Same instant
+		{ID: "a", Released: released.Add(time.Second), Type: Release},
+		{ID: "a", Released: released, Type: Snapshot},
+		{ID: "b", Released: released, Type: Snapshot},
+	}
+	exp := []Version{vs[0], vs[1], vs[3], vs[4]}
+
+	res := Dedupe(vs)
+	if len(res) != len(exp) {
+		t.Fatalf("Dedupe(vs) returned %d versions; want %d", len(res), len(exp))
+	}
+	for i := range exp {
+		if !res[i].Equal(exp[i]) {
+			t.Errorf("Dedupe(vs)[%d] was %s; want %s", i, pVersion(res[i]), pVersion(exp[i]))
+		}
+	}
+
+	if res := Dedupe(nil); res != nil {
+		t.Errorf("Dedupe(nil) was %v; want <nil>", res)
+	}
+}
+
 /*************
 * TEST UTILS *
 *************/
 
 var dummy struct{}
 
+func versionIDs(vs []Version) []string {
+	ids := make([]string, len(vs))
+	for i, v := range vs {
+		ids[i] = v.ID
+	}
+	return ids
+}
+
 func pVersion(v Version) string {
 	return fmt.Sprintf("Version{ID: %q, Released: %s, Type: %s}", v.ID, v.Released, v.Type)
 }
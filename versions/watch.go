@@ -0,0 +1,134 @@
+package versions
+
+import (
+	"context"
+	"time"
+
+	"github.com/PhilipBorgesen/minecraft/internal"
+)
+
+// Watcher watches Mojang's versions listing for changes, e.g. to be notified
+// when a new version of Minecraft is released. Watchers are created by Watch.
+type Watcher struct {
+	listings chan Listing
+	errors   chan error
+	cancel   context.CancelFunc
+	done     chan struct{} // Closed when the watching goroutine has returned.
+}
+
+// Watch starts watching Mojang's versions listing, fetching it at once and
+// then every interval. ctx must be non-nil; cancel it or call Stop to stop
+// watching. Watch panics if interval <= 0.
+//
+// The first listing fetched, and every listing which differs from the one
+// sent before it, is sent on the channel returned by Listings. Listings are
+// compared using Version.Equal, which ignores URL, SHA1, Time and
+// ComplianceLevel; hence a version re-published with a new manifest URL or
+// checksum isn't considered a change and isn't sent again. Errors which
+// occur while fetching are sent on the channel returned by Errors. Both
+// channels are unbuffered and the watcher waits for each value it sends to be
+// received before fetching again, so clients must receive from both channels
+// until they are closed or ctx is done.
+//
+// When ctx is done or Stop is called, any in-flight fetch is aborted and its
+// result discarded. Once the watcher has observed this, no further values are
+// sent; the Listings channel is closed, and then the Errors channel is closed.
+// Since either channel may block the watcher, a client must drain both, e.g.
+// in a single select loop, or call Stop, to avoid blocking or leaking the
+// watcher; ranging over only one of the channels blocks the watcher forever
+// on the first value sent on the other.
+func Watch(ctx context.Context, interval time.Duration) *Watcher {
+	internal.CheckContext(ctx, "versions", "Watch")
+	if interval <= 0 {
+		panic("minecraft/versions: non-positive interval passed to Watch")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	w := &Watcher{
+		listings: make(chan Listing),
+		errors:   make(chan error),
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	go w.run(ctx, interval)
+	return w
+}
+
+// Stop stops w and waits for it to close its channels, such that no goroutines
+// started by Watch remain when Stop returns, e.g. when shutting down a service
+// or at the end of a test. Values which w hasn't sent yet are discarded. Stop
+// may be called multiple times, also concurrently and after the context passed
+// to Watch is done; clients don't need to receive from w's channels for Stop
+// to return.
+func (w *Watcher) Stop() {
+	w.cancel()
+	<-w.done
+}
+
+// Listings returns the channel on which w sends changed versions listings.
+func (w *Watcher) Listings() <-chan Listing {
+	return w.listings
+}
+
+// Errors returns the channel on which w sends errors which occur when fetching
+// the versions listing.
+func (w *Watcher) Errors() <-chan error {
+	return w.errors
+}
+
+func (w *Watcher) run(ctx context.Context, interval time.Duration) {
+	// Deferred calls run in reverse: listings is closed before errors
+	defer close(w.done)
+	defer w.cancel() // Release resources of ctx
+	defer close(w.errors)
+	defer close(w.listings)
+
+	t := time.NewTimer(0)
+	defer t.Stop()
+
+	var last Listing
+	sent := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		l, err := Load(ctx)
+		if ctx.Err() != nil {
+			return // The fetch was aborted; discard its result
+		}
+
+		if err != nil {
+			select {
+			case w.errors <- err:
+			case <-ctx.Done():
+				return
+			}
+		} else if !sent || !sameListing(l, last) {
+			select {
+			case w.listings <- l:
+				last, sent = l, true
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		t.Reset(interval)
+	}
+}
+
+// sameListing reports whether a and b list the same versions, as compared by
+// Version.Equal.
+func sameListing(a, b Listing) bool {
+	if a.Latest != b.Latest || len(a.Versions) != len(b.Versions) {
+		return false
+	}
+	for id, v := range a.Versions {
+		if u, ok := b.Versions[id]; !ok || !u.Equal(v) {
+			return false
+		}
+	}
+	return true
+}
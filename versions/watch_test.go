@@ -0,0 +1,211 @@
+package versions
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	testError := errors.New("fetch failed")
+	st := &sequenceTransport{rts: []http.RoundTripper{
+		errorTransport{testError},
+		http.NewFileTransport(http.Dir("testdata/cached")),
+	}}
+	client.Transport = st
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w := Watch(ctx, time.Millisecond)
+
+	select {
+	case err := <-w.Errors():
+		if !errors.Is(err, testError) {
+			t.Errorf("Watcher sent error %v; want %s", err, testError)
+		}
+	case l := <-w.Listings():
+		t.Fatalf("Watcher sent listing %v; want error first", l.Latest)
+	case <-time.After(time.Second):
+		t.Fatal("Watcher sent no error within 1s")
+	}
+
+	select {
+	case l := <-w.Listings():
+		if l.Latest.Release != "1.11.2" {
+			t.Errorf("Watcher sent listing with latest release %q; want \"1.11.2\"", l.Latest.Release)
+		}
+	case err := <-w.Errors():
+		t.Fatalf("Watcher sent error %v; want listing", err)
+	case <-time.After(time.Second):
+		t.Fatal("Watcher sent no listing within 1s")
+	}
+
+	// Unchanged listings must not be sent again
+	for n := st.requests(); st.requests() < n+3; {
+		time.Sleep(time.Millisecond)
+	}
+	select {
+	case l := <-w.Listings():
+		t.Errorf("Watcher sent unchanged listing %v", l.Latest)
+	case err := <-w.Errors():
+		t.Errorf("Watcher sent error %v; want none", err)
+	default:
+	}
+
+	cancel()
+	expectClosed(t, w)
+}
+
+func TestWatchCancelledDuringFetch(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	bt := &blockingTransport{started: make(chan struct{})}
+	client.Transport = bt
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := Watch(ctx, time.Hour)
+
+	<-bt.started
+	cancel()
+	expectClosed(t, w)
+}
+
+func TestWatcher_Stop(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	client.Transport = http.NewFileTransport(http.Dir("testdata/cached"))
+
+	w := Watch(context.Background(), time.Millisecond)
+	w.Stop() // Must return although the first listing isn't received
+	expectClosed(t, w)
+	w.Stop() // Must be idempotent
+}
+
+func TestWatcher_StopDuringFetch(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	bt := &blockingTransport{started: make(chan struct{})}
+	client.Transport = bt
+
+	w := Watch(context.Background(), time.Hour)
+
+	<-bt.started
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.Stop()
+		}()
+	}
+	wg.Wait()
+	expectClosed(t, w)
+}
+
+func TestWatcher_StopAfterCancel(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	client.Transport = &blockingTransport{started: make(chan struct{})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := Watch(ctx, time.Hour)
+	cancel()
+	expectClosed(t, w)
+	w.Stop()
+}
+
+func TestWatchPanic(t *testing.T) {
+	const exp = "minecraft/versions: non-positive interval passed to Watch"
+	defer func() {
+		if r := recover(); r != exp {
+			t.Errorf("Watch(ctx, 0) panicked with %#v; want %q", r, exp)
+		}
+	}()
+	Watch(context.Background(), 0)
+}
+
+/*************
+* TEST UTILS *
+*************/
+
+// expectClosed fails t unless both channels of w are closed, Listings before
+// Errors, without further values being sent.
+func expectClosed(t *testing.T, w *Watcher) {
+	timeout := time.After(time.Second)
+	for done := false; !done; {
+		select {
+		case err, ok := <-w.Errors():
+			if ok {
+				t.Errorf("Watcher sent error %v after ctx was done", err)
+			}
+			done = !ok
+		case <-timeout:
+			t.Fatal("Watcher didn't close Errors within 1s after ctx was done")
+		}
+	}
+
+	select {
+	case l, ok := <-w.Listings():
+		if ok {
+			t.Errorf("Watcher sent listing %v after ctx was done", l.Latest)
+		}
+	default:
+		t.Error("Watcher closed Errors before Listings")
+	}
+}
+
+type errorTransport struct {
+	err error
+}
+
+func (et errorTransport) RoundTrip(_ *http.Request) (*http.Response, error) {
+	return nil, et.err
+}
+
+// sequenceTransport uses each of rts for a single request in turn, and then
+// the last of them for all remaining requests.
+type sequenceTransport struct {
+	mu  sync.Mutex
+	rts []http.RoundTripper
+	n   int
+}
+
+func (st *sequenceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	st.mu.Lock()
+	rt := st.rts[len(st.rts)-1]
+	if st.n < len(st.rts) {
+		rt = st.rts[st.n]
+	}
+	st.n++
+	st.mu.Unlock()
+	return rt.RoundTrip(req)
+}
+
+func (st *sequenceTransport) requests() int {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.n
+}
+
+// blockingTransport blocks requests until they are cancelled. started is
+// closed when the first request is made.
+type blockingTransport struct {
+	once    sync.Once
+	started chan struct{}
+}
+
+func (bt *blockingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	bt.once.Do(func() { close(bt.started) })
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}